@@ -1,6 +1,9 @@
 package main
 
-import "codifin-challenge/infrastructure/web"
+import (
+	"codifin-challenge/infrastructure/web"
+	"os"
+)
 
 // @title Codifin Challenge API
 // @version 1.0
@@ -15,6 +18,11 @@ import "codifin-challenge/infrastructure/web"
 // @contact.name   API Support
 // @contact.email  alfred.7790@gmail.com
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
 	server := web.NewServer()
 	server.Run()
 }
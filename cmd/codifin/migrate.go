@@ -0,0 +1,85 @@
+package main
+
+import (
+	"codifin-challenge/config"
+	"codifin-challenge/infrastructure/web"
+	"codifin-challenge/infrastructure/web/database"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+const migrateUsage = `usage: codifin migrate <command>
+
+commands:
+  up          apply every pending migration
+  down [n]    revert the last n applied migrations, 1 by default
+  status      list the migrations and whether they are applied`
+
+// runMigrate runs the migrate subcommand against the configured database.
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		log.Fatal(migrateUsage)
+	}
+
+	steps := 1
+	switch args[0] {
+	case "up", "status":
+		if len(args) > 1 {
+			log.Fatal(migrateUsage)
+		}
+	case "down":
+		if len(args) > 2 {
+			log.Fatal(migrateUsage)
+		}
+
+		if len(args) == 2 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				log.Fatalf("the number of migrations to revert must be a positive integer, got %q", args[1])
+			}
+			steps = n
+		}
+	default:
+		log.Fatal(migrateUsage)
+	}
+
+	cfg := config.GetConfig()
+	db, err := web.OpenDataBase(cfg.DB)
+	if err != nil {
+		log.Fatalf("error connecting to database: %s", err.Error())
+	}
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("error loading migrations: %s", err.Error())
+	}
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		err = migrator.Down(steps)
+	}
+	if err != nil {
+		log.Fatalf("error running migrations: %s", err.Error())
+	}
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		log.Fatalf("error reading migration status: %s", err.Error())
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	w.Flush()
+}
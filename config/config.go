@@ -10,6 +10,10 @@ import (
 type Config struct {
 	Host      Host
 	DB        DB
+	Cart      Cart
+	Catalog   Catalog
+	Tax       Tax
+	I18n      I18n
 	DebugMode bool `env:"DEBUG_MODE" default:"true"`
 }
 
@@ -19,12 +23,37 @@ type Host struct {
 }
 
 type DB struct {
-	Host     string `env:"DB_HOST" default:"db_products"`
-	Port     string `env:"DB_PORT"`
-	User     string `env:"DB_USER"`
-	Password string `env:"DB_PASSWORD"`
-	Name     string `env:"DB_NAME"`
-	Retries  int    `env:"DB_RETRIES" default:"3"`
+	Driver      string `env:"DB_DRIVER" default:"postgres"`
+	Host        string `env:"DB_HOST" default:"db_products"`
+	Port        string `env:"DB_PORT"`
+	User        string `env:"DB_USER"`
+	Password    string `env:"DB_PASSWORD"`
+	Name        string `env:"DB_NAME"`
+	SSLMode     string `env:"DB_SSL_MODE" default:"disable"`
+	SSLRootCert string `env:"DB_SSL_ROOT_CERT"`
+	SSLCert     string `env:"DB_SSL_CERT"`
+	SSLKey      string `env:"DB_SSL_KEY"`
+	Retries     int    `env:"DB_RETRIES" default:"3"`
+}
+
+type Cart struct {
+	ReservationMinutes     int `env:"CART_RESERVATION_MINUTES" default:"30"`
+	ReleaseIntervalSeconds int `env:"CART_RELEASE_INTERVAL_SECONDS" default:"60"`
+}
+
+type Catalog struct {
+	ScheduleIntervalSeconds int `env:"CATALOG_SCHEDULE_INTERVAL_SECONDS" default:"60"`
+	RetentionDays           int `env:"CATALOG_RETENTION_DAYS" default:"0"`
+	PurgeIntervalSeconds    int `env:"CATALOG_PURGE_INTERVAL_SECONDS" default:"3600"`
+}
+
+type Tax struct {
+	DefaultRegion    string `env:"TAX_DEFAULT_REGION" default:"MX"`
+	PricesIncludeTax bool   `env:"TAX_PRICES_INCLUDE_TAX" default:"false"`
+}
+
+type I18n struct {
+	DefaultLanguage string `env:"I18N_DEFAULT_LANGUAGE" default:"es"`
 }
 
 var config Config
@@ -21,9 +21,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/cart/{id}": {
-            "get": {
-                "description": "Retrieves a shopping cart by its ID",
+        "/admin/exchange-rate/{currency}": {
+            "put": {
+                "description": "Creates or replaces the units of a currency that one unit of the default currency buys. Products without a price in the price list of the currency are sold at their converted price.",
                 "consumes": [
                     "application/json"
                 ],
@@ -31,44 +31,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Shopping Carts"
+                    "Prices"
                 ],
-                "summary": "Get a shopping cart by ID",
-                "operationId": "find-shopping-cart",
+                "summary": "Set the exchange rate of a currency",
+                "operationId": "set-exchange-rate",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Shopping cart ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "ISO 4217 currency code",
+                        "name": "currency",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Exchange rate data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExchangeRateData"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Found shopping cart",
+                        "description": "Saved exchange rate",
                         "schema": {
-                            "$ref": "#/definitions/dto.ShoppingCartDTO"
+                            "$ref": "#/definitions/dto.ExchangeRateDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid shopping cart ID",
+                        "description": "Invalid currency or rate",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve shopping cart",
+                        "description": "Failed to save exchange rate",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     }
                 }
-            }
-        },
-        "/cart/{id}/items": {
-            "post": {
-                "description": "Adds an item to the specified shopping cart",
+            },
+            "delete": {
+                "description": "Deletes the exchange rate of a currency, so prices are no longer offered in it",
                 "consumes": [
                     "application/json"
                 ],
@@ -76,51 +83,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Shopping Carts"
+                    "Prices"
                 ],
-                "summary": "Add an item to a shopping cart",
-                "operationId": "add-item",
+                "summary": "Delete the exchange rate of a currency",
+                "operationId": "remove-exchange-rate",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Shopping cart ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "ISO 4217 currency code",
+                        "name": "currency",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Item data",
-                        "name": "item",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.ItemData"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated shopping cart",
+                        "description": "Exchange rate deleted successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.ShoppingCartDTO"
+                            "$ref": "#/definitions/responses.SuccessDTO"
                         }
                     },
-                    "400": {
-                        "description": "Invalid shopping cart ID or item data",
+                    "404": {
+                        "description": "Currency has no exchange rate",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     },
                     "500": {
-                        "description": "Failed to add item to shopping cart",
+                        "description": "Failed to delete exchange rate",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Removes the specified items from the shopping cart",
+            }
+        },
+        "/admin/exchange-rates": {
+            "get": {
+                "description": "Retrieves the units of every currency that one unit of the default currency buys",
                 "consumes": [
                     "application/json"
                 ],
@@ -128,56 +128,104 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Shopping Carts"
+                    "Prices"
                 ],
-                "summary": "Remove items from a shopping cart",
-                "operationId": "remove-items",
+                "summary": "Get the list of exchange rates",
+                "operationId": "find-exchange-rates",
+                "responses": {
+                    "200": {
+                        "description": "List of exchange rates",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ExchangeRateDTO"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve exchange rates",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/product/{id}": {
+            "get": {
+                "description": "Retrieves a product by its ID like the product endpoint, including drafts and archived products",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a product in any status by ID",
+                "operationId": "find-admin-product",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Shopping cart ID",
+                        "description": "Product ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "IDs of the products to remove",
-                        "name": "productIds",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "integer"
-                            }
-                        }
+                        "type": "string",
+                        "description": "Currency of the prices, the default currency if omitted",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices when the currency parameter is omitted",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product the client already has",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated shopping cart",
+                        "description": "Product found",
                         "schema": {
-                            "$ref": "#/definitions/dto.ShoppingCartDTO"
+                            "$ref": "#/definitions/dto.ProductDTO"
                         }
                     },
+                    "304": {
+                        "description": "The product has not changed since the ETag of If-None-Match"
+                    },
                     "400": {
-                        "description": "Invalid shopping cart ID or item IDs",
+                        "description": "Invalid product ID or unavailable currency",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Product does not exist",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     },
                     "500": {
-                        "description": "Failed to remove items from shopping cart",
+                        "description": "Failed to retrieve product",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     }
                 }
             }
         },
-        "/carts": {
-            "post": {
-                "description": "Creates a new shopping cart with the specified items",
+        "/admin/product/{id}/prices": {
+            "get": {
+                "description": "Retrieves the prices of a product in the price list of every currency it has one",
                 "consumes": [
                     "application/json"
                 ],
@@ -185,49 +233,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Shopping Carts"
+                    "Prices"
                 ],
-                "summary": "Create a new shopping cart",
-                "operationId": "new-cart",
+                "summary": "Get the price lists of a product",
+                "operationId": "find-product-prices",
                 "parameters": [
                     {
-                        "description": "Items to add to the shopping cart",
-                        "name": "items",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.ItemData"
-                            }
-                        }
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created shopping cart",
+                    "200": {
+                        "description": "List of product prices",
                         "schema": {
-                            "$ref": "#/definitions/dto.ShoppingCartDTO"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProductPriceDTO"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid item data",
+                    "404": {
+                        "description": "Product does not exist",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     },
                     "500": {
-                        "description": "Failed to create shopping cart",
+                        "description": "Failed to retrieve product prices",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     }
                 }
-            }
-        },
-        "/product/{id}": {
-            "get": {
-                "description": "Retrieves a product by its ID",
+            },
+            "put": {
+                "description": "Creates or replaces the price of a product in the price list of the currency of the price, which can not be the default currency",
                 "consumes": [
                     "application/json"
                 ],
@@ -235,10 +279,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Prices"
                 ],
-                "summary": "Get a product by ID",
-                "operationId": "find-product",
+                "summary": "Set the price of a product in a currency",
+                "operationId": "set-product-price",
                 "parameters": [
                     {
                         "type": "integer",
@@ -246,37 +290,48 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Product price data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductPriceData"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product found",
+                        "description": "Saved product price",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProductDTO"
+                            "$ref": "#/definitions/dto.ProductPriceDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid product ID",
+                        "description": "Invalid price or currency",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     },
                     "404": {
                         "description": "Product does not exist",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve product",
+                        "description": "Failed to save product price",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/product/{id}/prices/{currency}": {
             "delete": {
-                "description": "Deletes a product by its ID",
+                "description": "Deletes the price of a product in the price list of a currency, so it is sold at its converted price",
                 "consumes": [
                     "application/json"
                 ],
@@ -284,10 +339,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Prices"
                 ],
-                "summary": "Delete a product",
-                "operationId": "remove-product",
+                "summary": "Delete the price of a product in a currency",
+                "operationId": "remove-product-price",
                 "parameters": [
                     {
                         "type": "integer",
@@ -295,31 +350,40 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency code",
+                        "name": "currency",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product deleted successfully",
+                        "description": "Product price deleted successfully",
                         "schema": {
                             "$ref": "#/definitions/responses.SuccessDTO"
                         }
                     },
-                    "400": {
-                        "description": "Invalid product ID",
+                    "404": {
+                        "description": "Product has no price in the currency",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     },
                     "500": {
-                        "description": "Failed to delete product",
+                        "description": "Failed to delete product price",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Updates a product with the provided updates",
+            }
+        },
+        "/admin/product/{id}/restore": {
+            "post": {
+                "description": "Undoes the deletion of a product. The items that open carts lost when it was deleted are not restored.",
                 "consumes": [
                     "application/json"
                 ],
@@ -329,8 +393,8 @@ const docTemplate = `{
                 "tags": [
                     "Products"
                 ],
-                "summary": "Update a product",
-                "operationId": "update-product",
+                "summary": "Restore a deleted product",
+                "operationId": "restore-product",
                 "parameters": [
                     {
                         "type": "integer",
@@ -338,42 +402,39 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Product updates",
-                        "name": "updates",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.ProductData"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product updated successfully",
+                        "description": "Product restored successfully",
                         "schema": {
                             "$ref": "#/definitions/responses.SuccessDTO"
                         }
                     },
-                    "400": {
-                        "description": "Invalid product ID or updates",
+                    "404": {
+                        "description": "Product does not exist",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Product is not deleted, or another product has its code, with code PRODUCT_CODE_TAKEN",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     },
                     "500": {
-                        "description": "Failed to update product",
+                        "description": "Failed to restore product",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     }
                 }
             }
         },
-        "/products": {
+        "/admin/products": {
             "get": {
-                "description": "Retrieves a paginated and filtered list of products based on search criteria",
+                "description": "Retrieves a paginated and filtered list of products like the product list, including drafts and archived products",
                 "consumes": [
                     "application/json"
                 ],
@@ -383,16 +444,15 @@ const docTemplate = `{
                 "tags": [
                     "Products"
                 ],
-                "summary": "Get a paginated and filtered list of products",
-                "operationId": "find-products",
+                "summary": "Get a paginated and filtered list of products in any status",
+                "operationId": "find-admin-products",
                 "parameters": [
                     {
                         "minimum": 1,
                         "type": "integer",
                         "description": "Page number",
                         "name": "page",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
                         "minimum": 1,
@@ -404,46 +464,250 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Search term to filter products by code or name",
+                        "description": "Opaque cursor of the page to list, the nextCursor or prevCursor of a previous response with the same sort. Without it the first page is listed. Cannot be combined with page.",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search term to find products by code, name or description, regardless of case and accents, by word prefixes and with typos. Every word must match, and every product found has a relevance score.",
                         "name": "searchTerm",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Field to order results by. Can be 'name', 'price', or 'code'. Default is 'price'.",
+                        "description": "Fields to sort results by, separated by commas and prefixed with '-' to sort descending, such as -price,name. Can be 'id', 'code', 'name', 'price' or 'createdAt'. Ties are broken by id. Default is relevance when searching and price otherwise.",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deprecated, use sort. Field to order results by, when sort is omitted.",
                         "name": "orderBy",
                         "in": "query"
                     },
                     {
                         "type": "boolean",
-                        "description": "Whether to order results in ascending or descending order. Default is true.",
+                        "description": "Deprecated, use sort. Whether orderBy orders results in ascending order. Default is true.",
                         "name": "ascending",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Category ID to filter products by",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether the category filter also matches its descendant categories. Default is false.",
+                        "name": "includeDescendants",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lowest price of the products, inclusive, as a decimal amount in the store currency",
+                        "name": "minPrice",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Highest price of the products, inclusive, as a decimal amount in the store currency",
+                        "name": "maxPrice",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Products created from this moment on, as an RFC 3339 timestamp or a YYYY-MM-DD date",
+                        "name": "createdAfter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Products created before this moment, as an RFC 3339 timestamp or a YYYY-MM-DD date",
+                        "name": "createdBefore",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attribute a variant of the products must have, such as attr[color]=red. Can be repeated for different attributes, which the same variant must have.",
+                        "name": "attr[name]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the default currency if omitted",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices when the currency parameter is omitted",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statuses of the products, separated by commas: 'draft', 'active' or 'archived'. Default is every status.",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated and filtered list of products",
+                        "description": "Paginated and filtered list of products, with the facets of all the products that pass the filters and, when paged by cursor, the cursors of the adjacent pages",
                         "schema": {
                             "$ref": "#/definitions/dto.ProductsListResp"
                         }
                     },
                     "400": {
-                        "description": "Invalid page, pageSize, or search parameters",
+                        "description": "Invalid page, pageSize, cursor, sort, search, status or filter parameters, or unavailable currency",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     },
-                    "500": {
-                        "description": "Failed to retrieve products",
+                    "404": {
+                        "description": "Category does not exist",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve products",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/by-code/{code}": {
+            "get": {
+                "description": "Retrieves the product with a code like the product by code endpoint, including drafts and archived products",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a product in any status by code",
+                "operationId": "find-admin-product-by-code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the default currency if omitted",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices when the currency parameter is omitted",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product the client already has",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductDTO"
+                        }
+                    },
+                    "304": {
+                        "description": "The product has not changed since the ETag of If-None-Match"
+                    },
+                    "400": {
+                        "description": "Unavailable currency",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "No product has the code",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve product",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/deleted": {
+            "get": {
+                "description": "Retrieves a paginated list of the deleted products, the most recently deleted first, so they can be restored or purged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a paginated list of deleted products",
+                "operationId": "find-deleted-products",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated list of deleted products",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeletedProductsListResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid page or pageSize",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve deleted products",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     }
                 }
             },
-            "post": {
-                "description": "Creates a new product with the provided data",
+            "delete": {
+                "description": "Permanently deletes the products deleted more than a number of days ago, with their variants, prices, category and promotion assignments and the cart items that held them. Orders keep their copies of the products.",
                 "consumes": [
                     "application/json"
                 ],
@@ -453,108 +717,2950 @@ const docTemplate = `{
                 "tags": [
                     "Products"
                 ],
-                "summary": "Create a new product",
-                "operationId": "new-product",
+                "summary": "Permanently delete old deleted products",
+                "operationId": "purge-products",
                 "parameters": [
                     {
-                        "description": "Product data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Minimum days since the products were deleted",
+                        "name": "olderThanDays",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of products purged",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProductData"
+                            "$ref": "#/definitions/dto.PurgeResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid olderThanDays",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to purge products; the products purged before the failure stay purged",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart/{id}": {
+            "get": {
+                "description": "Retrieves a shopping cart by its ID with its line totals, subtotal, item count, discounts, taxes and total. Prices are tax inclusive or exclusive as configured. The ETag header identifies this representation of the cart, so it changes with its items and coupons and with the prices, promotions, taxes and exchange rates behind its totals.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shopping Carts"
+                ],
+                "summary": "Get a shopping cart by ID",
+                "operationId": "find-shopping-cart",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shopping cart ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the default currency if omitted",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices when the currency parameter is omitted",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the shopping cart the client already has",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Found shopping cart",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ShoppingCartDTO"
+                        }
+                    },
+                    "304": {
+                        "description": "The shopping cart has not changed since the ETag of If-None-Match"
+                    },
+                    "400": {
+                        "description": "Invalid shopping cart ID or unavailable currency",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Shopping cart does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve shopping cart",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     }
+                }
+            }
+        },
+        "/cart/{id}/checkout": {
+            "post": {
+                "description": "Turns a shopping cart into a pending order, freezing its items and unit prices and locking the cart against further changes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Checkout a shopping cart",
+                "operationId": "checkout",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shopping cart ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created product",
+                        "description": "Created order",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProductDTO"
+                            "$ref": "#/definitions/dto.OrderDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid product data",
+                        "description": "Empty shopping cart",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Shopping cart does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Shopping cart already checked out, changed during the checkout or not enough stock",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     },
                     "500": {
-                        "description": "Failed to create product",
+                        "description": "Failed to create order",
                         "schema": {
-                            "$ref": "#/definitions/responses.ErrorDTO"
+                            "$ref": "#/definitions/responses.ProblemDTO"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.ItemCartDTO": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
+        },
+        "/cart/{id}/coupons": {
+            "post": {
+                "description": "Applies the promotion of a coupon code to the shopping cart. The cart response lists the discount of every coupon and the adjusted total.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shopping Carts"
+                ],
+                "summary": "Apply a coupon to a shopping cart",
+                "operationId": "apply-coupon",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shopping cart ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the shopping cart the change was made on, or * for any",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Coupon code",
+                        "name": "coupon",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CouponData"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the default currency if omitted",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices when the currency parameter is omitted",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated shopping cart, with its new entity tag in the ETag header",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ShoppingCartDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid coupon data",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Shopping cart or coupon does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Shopping cart already checked out, coupon already applied, expired or exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "412": {
+                        "description": "The shopping cart no longer matches If-Match, with code VERSION_CONFLICT",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to apply coupon",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart/{id}/coupons/{code}": {
+            "delete": {
+                "description": "Removes a coupon code from the shopping cart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shopping Carts"
+                ],
+                "summary": "Remove a coupon from a shopping cart",
+                "operationId": "remove-coupon",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shopping cart ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the shopping cart the change was made on, or * for any",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Coupon code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the default currency if omitted",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices when the currency parameter is omitted",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated shopping cart, with its new entity tag in the ETag header",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ShoppingCartDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Shopping cart or coupon does not exist, or coupon not applied",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Shopping cart already checked out",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "412": {
+                        "description": "The shopping cart no longer matches If-Match, with code VERSION_CONFLICT",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to remove coupon",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart/{id}/items": {
+            "post": {
+                "description": "Adds an item to the specified shopping cart and reserves its stock for a limited time. Products sold in variants require the variantID, and counts are merged per variant.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shopping Carts"
+                ],
+                "summary": "Add an item to a shopping cart",
+                "operationId": "add-item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shopping cart ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the shopping cart the change was made on, or * for any",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Item data",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ItemData"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the default currency if omitted",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices when the currency parameter is omitted",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated shopping cart, with its new entity tag in the ETag header",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ShoppingCartDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid shopping cart ID or item data",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Shopping cart does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Shopping cart already checked out, with code CART_LOCKED, not enough stock, or the product is not published yet or archived, with code PRODUCT_NOT_PUBLISHED or PRODUCT_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "412": {
+                        "description": "The shopping cart no longer matches If-Match, with code VERSION_CONFLICT",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to add item to shopping cart",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes the specified products, with all their variants, from the shopping cart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shopping Carts"
+                ],
+                "summary": "Remove items from a shopping cart",
+                "operationId": "remove-items",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shopping cart ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the shopping cart the change was made on, or * for any",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "IDs of the products to remove",
+                        "name": "productIds",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the default currency if omitted",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices when the currency parameter is omitted",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated shopping cart, with its new entity tag in the ETag header",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ShoppingCartDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid shopping cart ID or item IDs",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Shopping cart does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Shopping cart already checked out",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "412": {
+                        "description": "The shopping cart no longer matches If-Match, with code VERSION_CONFLICT",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to remove items from shopping cart",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/carts": {
+            "post": {
+                "description": "Creates a new shopping cart with the specified items, taxed with the rules of a region",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shopping Carts"
+                ],
+                "summary": "Create a new shopping cart",
+                "operationId": "new-cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tax region of the cart, the configured default region if omitted",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Items to add to the shopping cart",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ItemData"
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the default currency if omitted",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices when the currency parameter is omitted",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created shopping cart, with its entity tag in the ETag header",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ShoppingCartDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid item data or region without tax rules",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Product does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Not enough stock, or a product is not published yet or archived, with code PRODUCT_NOT_PUBLISHED or PRODUCT_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create shopping cart",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "description": "Retrieves every category nested under its parent, to render the catalog navigation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Get the category tree",
+                "operationId": "find-category-tree",
+                "responses": {
+                    "200": {
+                        "description": "Root categories with their descendants",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.CategoryTreeDTO"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve categories",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new category, optionally nested under a parent category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Create a new category",
+                "operationId": "new-category",
+                "parameters": [
+                    {
+                        "description": "Category data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created category",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid category data or parent",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create category",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/category/{id}": {
+            "get": {
+                "description": "Retrieves a category with its direct subcategories by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Get a category by ID",
+                "operationId": "find-category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Category found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryTreeDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Category does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve category",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the name and parent of a category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Update a category",
+                "operationId": "update-category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated category",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid category data or parent",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Category does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update category",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a category without subcategories and unassigns it from its products",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Delete a category",
+                "operationId": "remove-category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Category deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SuccessDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Category does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Category has subcategories",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete category",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}": {
+            "get": {
+                "description": "Retrieves an order with its lines by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get an order by ID",
+                "operationId": "find-order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Order does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve order",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/{action}": {
+            "post": {
+                "description": "Applies a transition to an order. Allowed transitions are pending → paid|cancelled, paid → shipped|refunded, shipped → delivered and delivered → refunded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Move an order to another status",
+                "operationId": "change-order-status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pay",
+                            "ship",
+                            "deliver",
+                            "cancel",
+                            "refund"
+                        ],
+                        "type": "string",
+                        "description": "Transition to apply",
+                        "name": "action",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated order",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown transition",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Order does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Transition not allowed from the current status",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update order",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}": {
+            "get": {
+                "description": "Retrieves an active product by its ID. The ETag header identifies this representation of the product, prices included, to check with If-Match on changes or If-None-Match on later requests.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a product by ID",
+                "operationId": "find-product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the default currency if omitted",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices when the currency parameter is omitted",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product the client already has",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductDTO"
+                        }
+                    },
+                    "304": {
+                        "description": "The product has not changed since the ETag of If-None-Match"
+                    },
+                    "400": {
+                        "description": "Invalid product ID or unavailable currency",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Product does not exist or is not active",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve product",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a product by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Delete a product",
+                "operationId": "remove-product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product to delete, or * for any",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices the ETag of If-Match was taken with, the default currency if omitted",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices the ETag of If-Match was taken with when the currency parameter is omitted",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SuccessDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID or unavailable currency",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Product does not exist, when If-Match is given",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "412": {
+                        "description": "The product no longer matches If-Match, with code VERSION_CONFLICT",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete product",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates a product with a JSON Merge Patch (RFC 7396), where null members clear their fields, or a JSON Patch (RFC 6902) of its document, the fields of dto.ProductData. Plain JSON is taken as a merge patch. A price may be given as a decimal string or as an object with an amount and a currency. The ETag header holds the entity tag of the updated product.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Update a product",
+                "operationId": "update-product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product the updates were made on, or * for any",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices the ETag of If-Match was taken with, the default currency if omitted",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices the ETag of If-Match was taken with when the currency parameter is omitted",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge patch of the product, or an array of JSON Patch operations with op, path, from and value members",
+                        "name": "updates",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SuccessDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID, patch or field value",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Product does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Another product has the code, with code PRODUCT_CODE_TAKEN, another request changed the product at the same time, with code CONCURRENT_MODIFICATION, or a test operation failed, with code PATCH_TEST_FAILED",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "412": {
+                        "description": "The product no longer matches If-Match, with code VERSION_CONFLICT",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "413": {
+                        "description": "The patch is larger than 1 MB",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported content type",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "422": {
+                        "description": "The JSON Patch refers to values the product does not have, or the product refers to a record that does not exist, with code MISSING_REFERENCE or PRODUCT_CATEGORIES_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update product",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}/variants": {
+            "post": {
+                "description": "Adds a variant, such as a size or a color, with its own SKU, price override, stock and attributes to a product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Create a product variant",
+                "operationId": "new-variant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Variant data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VariantData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created variant",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VariantDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid variant data",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Product does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "SKU already registered",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create variant",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}/variants/{variantID}": {
+            "put": {
+                "description": "Replaces the SKU, price override, stock and attributes of a product variant",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Update a product variant",
+                "operationId": "update-variant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Variant ID",
+                        "name": "variantID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Variant data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VariantData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated variant",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VariantDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid variant data",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Variant does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "SKU already registered",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update variant",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a variant of a product by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Delete a product variant",
+                "operationId": "remove-variant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Variant ID",
+                        "name": "variantID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Variant deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SuccessDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Variant does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete variant",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "description": "Retrieves a paginated and filtered list of the active products based on search criteria",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a paginated and filtered list of products",
+                "operationId": "find-products",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor of the page to list, the nextCursor or prevCursor of a previous response with the same sort. Without it the first page is listed. Cannot be combined with page.",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search term to find products by code, name or description, regardless of case and accents, by word prefixes and with typos. Every word must match, and every product found has a relevance score.",
+                        "name": "searchTerm",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to sort results by, separated by commas and prefixed with '-' to sort descending, such as -price,name. Can be 'id', 'code', 'name', 'price' or 'createdAt'. Ties are broken by id. Default is relevance when searching and price otherwise.",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deprecated, use sort. Field to order results by, when sort is omitted.",
+                        "name": "orderBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Deprecated, use sort. Whether orderBy orders results in ascending order. Default is true.",
+                        "name": "ascending",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Category ID to filter products by",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether the category filter also matches its descendant categories. Default is false.",
+                        "name": "includeDescendants",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lowest price of the products, inclusive, as a decimal amount in the store currency",
+                        "name": "minPrice",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Highest price of the products, inclusive, as a decimal amount in the store currency",
+                        "name": "maxPrice",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Products created from this moment on, as an RFC 3339 timestamp or a YYYY-MM-DD date",
+                        "name": "createdAfter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Products created before this moment, as an RFC 3339 timestamp or a YYYY-MM-DD date",
+                        "name": "createdBefore",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attribute a variant of the products must have, such as attr[color]=red. Can be repeated for different attributes, which the same variant must have.",
+                        "name": "attr[name]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the default currency if omitted",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices when the currency parameter is omitted",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated and filtered list of products, with the facets of all the products that pass the filters and, when paged by cursor, the cursors of the adjacent pages",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductsListResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid page, pageSize, cursor, sort, search or filter parameters, or unavailable currency",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Category does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve products",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new product with the provided data. The price is an object with a decimal string amount and a currency, such as {\"amount\": \"12.30\", \"currency\": \"MXN\"}, in the store currency",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Create a new product",
+                "operationId": "new-product",
+                "parameters": [
+                    {
+                        "description": "Product data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created product",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product data or unknown category",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Another product has the code, with code PRODUCT_CODE_TAKEN",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "422": {
+                        "description": "The product refers to a record that does not exist, with code MISSING_REFERENCE or PRODUCT_CATEGORIES_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create product",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/by-code/{code}": {
+            "get": {
+                "description": "Retrieves the active product with a code among the ones that are not deleted, with its entity tag in the ETag header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a product by code",
+                "operationId": "find-product-by-code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the default currency if omitted",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices when the currency parameter is omitted",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product the client already has",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductDTO"
+                        }
+                    },
+                    "304": {
+                        "description": "The product has not changed since the ETag of If-None-Match"
+                    },
+                    "400": {
+                        "description": "Unavailable currency",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "No active product has the code",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve product",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/export": {
+            "get": {
+                "description": "Streams every product that passes the same filters as the product list, at its catalog price, as CSV, NDJSON or XLSX. The default columns are the ones the import reads, so CSV and NDJSON exports can be imported back; the id, available, createdAt and updatedAt columns are skipped by imports.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Export the product catalog",
+                "operationId": "export-products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Format of the export: 'csv', 'ndjson' or 'xlsx'. Default is csv.",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Columns to export, separated by commas: id, code, name, description, price, currency, imageURL, taxCategory, stock, available, categoryIDs, status, publishAt, unpublishAt, createdAt, updatedAt. Default is every column the import reads.",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search term to find products by code, name or description",
+                        "name": "searchTerm",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to sort products by, such as -price,name. Default is relevance when searching and id otherwise.",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Category ID to filter products by",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether the category filter also matches its descendant categories. Default is false.",
+                        "name": "includeDescendants",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lowest price of the products, inclusive, as a decimal amount in the store currency",
+                        "name": "minPrice",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Highest price of the products, inclusive, as a decimal amount in the store currency",
+                        "name": "maxPrice",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Products created from this moment on, as an RFC 3339 timestamp or a YYYY-MM-DD date",
+                        "name": "createdAfter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Products created before this moment, as an RFC 3339 timestamp or a YYYY-MM-DD date",
+                        "name": "createdBefore",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attribute a variant of the products must have, such as attr[color]=red",
+                        "name": "attr[name]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statuses of the products, separated by commas: 'draft', 'active' or 'archived'. Default is every status.",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product catalog",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format, columns, sort, status or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Category does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to export products",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/import": {
+            "post": {
+                "description": "Creates or updates, by code, the products of a CSV or NDJSON file, normalized and validated as when creating a product, and reports what happened with every row. CSV files have a header with the fields of the product data, plus an optional currency column for the price; category IDs are separated by | and empty cells are left unchanged. NDJSON files have a product data object per line. Updates only change the fields a row has. The file is read as it is uploaded, either as the body or as the file part of a multipart form.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Import products from a CSV or NDJSON file",
+                "operationId": "import-products",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or NDJSON file, for multipart uploads",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format of the file, 'csv' or 'ndjson'. Default is taken from the content type or the file name.",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether to validate the rows without saving them. Default is false.",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Created, updated and rejected rows",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportReportDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported format, invalid header or unreadable file",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to import products; the rows before the failure are kept",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotion/{id}": {
+            "get": {
+                "description": "Retrieves a promotion with its rule, limits, usage count and scope by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Get a promotion by ID",
+                "operationId": "find-promotion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Promotion found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PromotionDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Promotion does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve promotion",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the code, rule, limits and scope of a promotion, keeping its usage count",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Update a promotion",
+                "operationId": "update-promotion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Promotion data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PromotionData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated promotion",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PromotionDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid promotion data",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Promotion does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Code already registered",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update promotion",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a promotion and removes its coupon from the carts it was applied to",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Delete a promotion",
+                "operationId": "remove-promotion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Promotion deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SuccessDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Promotion does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete promotion",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions": {
+            "get": {
+                "description": "Retrieves every promotion with its rule, limits, usage count and scope",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Get the list of promotions",
+                "operationId": "find-promotions",
+                "responses": {
+                    "200": {
+                        "description": "List of promotions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.PromotionDTO"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve promotions",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a coupon code with a percentage off, fixed amount off, buy X get Y or free item over threshold rule, optionally limited to a validity window, a number of uses and some products or categories",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Create a new promotion",
+                "operationId": "new-promotion",
+                "parameters": [
+                    {
+                        "description": "Promotion data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PromotionData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created promotion",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PromotionDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid promotion data",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Code already registered",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create promotion",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-rule/{id}": {
+            "get": {
+                "description": "Retrieves a tax rule by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Taxes"
+                ],
+                "summary": "Get a tax rule by ID",
+                "operationId": "find-tax-rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tax rule found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TaxRuleDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Tax rule does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve tax rule",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the region, tax category and rate of a tax rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Taxes"
+                ],
+                "summary": "Update a tax rule",
+                "operationId": "update-tax-rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tax rule data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TaxRuleData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated tax rule",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TaxRuleDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tax rule data",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Tax rule does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Tax category already has a rule in the region",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update tax rule",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a tax rule. Products of its tax category are then taxed at the standard rate of the region.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Taxes"
+                ],
+                "summary": "Delete a tax rule",
+                "operationId": "remove-tax-rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tax rule deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SuccessDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Tax rule does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete tax rule",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-rules": {
+            "get": {
+                "description": "Retrieves the tax rate of every tax category in every region",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Taxes"
+                ],
+                "summary": "Get the list of tax rules",
+                "operationId": "find-tax-rules",
+                "responses": {
+                    "200": {
+                        "description": "List of tax rules",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.TaxRuleDTO"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve tax rules",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Sets the tax rate, as a fraction between 0 and 1, of a tax category in a region",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Taxes"
+                ],
+                "summary": "Create a new tax rule",
+                "operationId": "new-tax-rule",
+                "parameters": [
+                    {
+                        "description": "Tax rule data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TaxRuleData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created tax rule",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TaxRuleDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tax rule data",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Tax category already has a rule in the region",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create tax rule",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemDTO"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AttributeFacetDTO": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CategoryDTO": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "parentID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CategoryData": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "parentID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CategoryFacetDTO": {
+            "type": "object",
+            "properties": {
+                "categoryID": {
+                    "type": "integer"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CategoryTreeDTO": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CategoryTreeDTO"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "parentID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CouponData": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "dto.CreatedFacetDTO": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.DeletedProductDTO": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CategoryDTO"
+                    }
+                },
+                "categoryIDs": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imageURL": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "price": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "publishAt": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "active",
+                        "archived"
+                    ]
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "taxCategory": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "unpublishAt": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VariantDTO"
+                    }
+                }
+            }
+        },
+        "dto.DeletedProductsListResp": {
+            "type": "object",
+            "properties": {
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DeletedProductDTO"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.DiscountDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ExchangeRateDTO": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 0.058
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ExchangeRateData": {
+            "type": "object",
+            "properties": {
+                "rate": {
+                    "type": "number",
+                    "example": 0.058
+                }
+            }
+        },
+        "dto.ImportReportDTO": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ImportResultDTO"
+                    }
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ImportResultDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "productID": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ItemCartDTO": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "discount": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "lineTotal": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "product": {
+                    "$ref": "#/definitions/dto.ProductDTO"
+                },
+                "productDeleted": {
+                    "description": "ProductDeleted tells that the product was deleted after the item was\nadded to the cart; the item is priced at zero and the cart cannot be\nchecked out with it.",
+                    "type": "boolean"
+                },
+                "productID": {
+                    "type": "integer"
+                },
+                "tax": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "taxRate": {
+                    "type": "number"
+                },
+                "unitPrice": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "variant": {
+                    "$ref": "#/definitions/dto.VariantDTO"
+                },
+                "variantID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ItemData": {
+            "type": "object",
+            "required": [
+                "productID"
+            ],
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "productID": {
+                    "type": "integer"
+                },
+                "variantID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.OrderDTO": {
+            "type": "object",
+            "properties": {
+                "cartID": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "discount": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DiscountDTO"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "itemCount": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OrderLineDTO"
+                    }
+                },
+                "pricesIncludeTax": {
+                    "type": "boolean"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "tax": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "total": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OrderLineDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "discount": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "lineTotal": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "productID": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
                 },
-                "product": {
-                    "$ref": "#/definitions/dto.ProductDTO"
+                "tax": {
+                    "$ref": "#/definitions/model.Money"
                 },
-                "productID": {
+                "taxRate": {
+                    "type": "number"
+                },
+                "unitPrice": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "variantID": {
                     "type": "integer"
                 }
             }
         },
-        "dto.ItemData": {
+        "dto.PriceFacetDTO": {
             "type": "object",
             "properties": {
                 "count": {
                     "type": "integer"
                 },
-                "productID": {
-                    "type": "integer"
+                "max": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "min": {
+                    "$ref": "#/definitions/model.Money"
                 }
             }
         },
         "dto.ProductDTO": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CategoryDTO"
+                    }
+                },
+                "categoryIDs": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "code": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 5000
                 },
                 "id": {
                     "type": "integer"
                 },
                 "imageURL": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 2048
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 255
                 },
                 "price": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "publishAt": {
+                    "type": "string"
+                },
+                "score": {
                     "type": "number"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "active",
+                        "archived"
+                    ]
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "taxCategory": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "unpublishAt": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VariantDTO"
+                    }
                 }
             }
         },
         "dto.ProductData": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
+                "categoryIDs": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "code": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 5000
                 },
                 "imageURL": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 2048
                 },
                 "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "price": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "publishAt": {
                     "type": "string"
                 },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "active",
+                        "archived"
+                    ]
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "taxCategory": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "unpublishAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ProductFacetsDTO": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AttributeFacetDTO"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CategoryFacetDTO"
+                    }
+                },
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CreatedFacetDTO"
+                    }
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PriceFacetDTO"
+                    }
+                }
+            }
+        },
+        "dto.ProductPriceDTO": {
+            "type": "object",
+            "properties": {
                 "price": {
-                    "type": "number"
+                    "$ref": "#/definitions/model.Money"
+                },
+                "productID": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ProductPriceData": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "$ref": "#/definitions/model.Money"
                 }
             }
         },
         "dto.ProductsListResp": {
             "type": "object",
             "properties": {
+                "facets": {
+                    "$ref": "#/definitions/dto.ProductFacetsDTO"
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "prevCursor": {
+                    "type": "string"
+                },
                 "products": {
                     "type": "array",
                     "items": {
@@ -566,28 +3672,367 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.PromotionDTO": {
+            "type": "object",
+            "required": [
+                "code",
+                "type"
+            ],
+            "properties": {
+                "amountOff": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "buyQuantity": {
+                    "type": "integer"
+                },
+                "categoryIDs": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "endsAt": {
+                    "type": "string"
+                },
+                "freeProductID": {
+                    "type": "integer"
+                },
+                "getQuantity": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "perCartLimit": {
+                    "type": "integer"
+                },
+                "productIDs": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "startsAt": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "percentage",
+                        "fixed_amount",
+                        "buy_x_get_y",
+                        "free_item_over_threshold"
+                    ]
+                },
+                "usageCount": {
+                    "type": "integer"
+                },
+                "usageLimit": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "dto.PromotionData": {
+            "type": "object",
+            "required": [
+                "code",
+                "type"
+            ],
+            "properties": {
+                "amountOff": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "buyQuantity": {
+                    "type": "integer"
+                },
+                "categoryIDs": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "endsAt": {
+                    "type": "string"
+                },
+                "freeProductID": {
+                    "type": "integer"
+                },
+                "getQuantity": {
+                    "type": "integer"
+                },
+                "perCartLimit": {
+                    "type": "integer"
+                },
+                "productIDs": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "startsAt": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "percentage",
+                        "fixed_amount",
+                        "buy_x_get_y",
+                        "free_item_over_threshold"
+                    ]
+                },
+                "usageLimit": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "dto.PurgeResp": {
+            "type": "object",
+            "properties": {
+                "purged": {
+                    "type": "integer"
+                }
+            }
+        },
         "dto.ShoppingCartDTO": {
             "type": "object",
             "properties": {
+                "discount": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DiscountDTO"
+                    }
+                },
                 "id": {
                     "type": "integer"
                 },
+                "itemCount": {
+                    "type": "integer"
+                },
                 "items": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/dto.ItemCartDTO"
                     }
+                },
+                "locked": {
+                    "type": "boolean"
+                },
+                "pricesIncludeTax": {
+                    "type": "boolean"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "tax": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "total": {
+                    "$ref": "#/definitions/model.Money"
+                }
+            }
+        },
+        "dto.TaxRuleDTO": {
+            "type": "object",
+            "required": [
+                "region",
+                "taxCategory"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "region": {
+                    "type": "string",
+                    "maxLength": 16
+                },
+                "taxCategory": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "dto.TaxRuleData": {
+            "type": "object",
+            "required": [
+                "region",
+                "taxCategory"
+            ],
+            "properties": {
+                "rate": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "region": {
+                    "type": "string",
+                    "maxLength": 16
+                },
+                "taxCategory": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "dto.VariantDTO": {
+            "type": "object",
+            "required": [
+                "attributes",
+                "sku"
+            ],
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "available": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "sku": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.VariantData": {
+            "type": "object",
+            "required": [
+                "attributes",
+                "sku"
+            ],
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "price": {
+                    "$ref": "#/definitions/model.Money"
+                },
+                "sku": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Money": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "12.30"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "MXN"
                 }
             }
         },
-        "responses.ErrorDTO": {
+        "responses.FieldErrorDTO": {
             "type": "object",
             "properties": {
-                "errorMessage": {
+                "field": {
                     "type": "string"
                 },
                 "message": {
                     "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.ProblemDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "PRODUCT_NOT_FOUND"
+                },
+                "debug": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "El producto solicitado no existe"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.FieldErrorDTO"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/v1/product/7"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Recurso no encontrado"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "urn:codifin:problem:product-not-found"
                 }
             }
         },
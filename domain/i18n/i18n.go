@@ -0,0 +1,188 @@
+// Package i18n is the catalog of the messages of the API, keyed by the codes
+// of errors and successes, in every language it has a bundle for. Bundles are
+// the JSON files of messages, embedded in the binary; their messages are
+// formats for the arguments of each message.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed messages/*.json
+var files embed.FS
+
+// bundles are the messages of each language by key.
+var bundles = loadBundles()
+
+var defaultLanguage = "es"
+
+// Localizable is a message that can be shown in any language of the catalog.
+type Localizable interface {
+	Localize(language string) string
+}
+
+// Message is a message of the catalog with the arguments of its format.
+// Arguments that are messages themselves are localized too.
+type Message struct {
+	Key  string
+	Args []interface{}
+}
+
+// NewMessage returns the message with the key given.
+func NewMessage(key string, args ...interface{}) *Message {
+	return &Message{Key: key, Args: args}
+}
+
+func (m *Message) Localize(language string) string {
+	return Translate(language, m.Key, m.Args...)
+}
+
+// Localize returns a message in a language, or nothing if there is no message.
+func Localize(m Localizable, language string) string {
+	if m == nil {
+		return ""
+	}
+
+	return m.Localize(language)
+}
+
+// List is a list of messages, shown one after another.
+type List []Localizable
+
+func (l List) Localize(language string) string {
+	messages := make([]string, 0, len(l))
+	for _, v := range l {
+		messages = append(messages, v.Localize(language))
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func loadBundles() map[string]map[string]string {
+	entries, err := files.ReadDir("messages")
+	if err != nil {
+		panic(err)
+	}
+
+	loaded := make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := files.ReadFile(path.Join("messages", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		messages := make(map[string]string)
+		if err = json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("invalid bundle %s: %v", entry.Name(), err))
+		}
+		loaded[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+
+	return loaded
+}
+
+// Languages returns the languages of the catalog.
+func Languages() []string {
+	languages := make([]string, 0, len(bundles))
+	for k := range bundles {
+		languages = append(languages, k)
+	}
+	sort.Strings(languages)
+
+	return languages
+}
+
+// Keys returns the keys of the messages of a language.
+func Keys(language string) []string {
+	keys := make([]string, 0, len(bundles[language]))
+	for k := range bundles[language] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// SetDefaultLanguage sets the language of the messages of requests that do
+// not ask for a language of the catalog.
+func SetDefaultLanguage(language string) error {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if _, ok := bundles[language]; !ok {
+		return fmt.Errorf("unsupported default language %q, the languages are: %s", language, strings.Join(Languages(), ", "))
+	}
+
+	defaultLanguage = language
+	return nil
+}
+
+func DefaultLanguage() string {
+	return defaultLanguage
+}
+
+// Translate returns the message of a key in a language, with its arguments
+// filled in. Messages missing from the language are taken from the default
+// one, and missing from both are shown as their key.
+func Translate(language, key string, args ...interface{}) string {
+	message, ok := bundles[language][key]
+	if !ok {
+		language = defaultLanguage
+		if message, ok = bundles[language][key]; !ok {
+			message = key
+		}
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+
+	localized := make([]interface{}, len(args))
+	for i, v := range args {
+		if m, ok := v.(Localizable); ok {
+			v = m.Localize(language)
+		}
+		localized[i] = v
+	}
+
+	return fmt.Sprintf(message, localized...)
+}
+
+// Negotiate returns the language of the catalog a request prefers by its
+// Accept-Language header (RFC 9110), by the primary subtag of its ranges, or
+// the default language if it prefers none of them.
+func Negotiate(acceptLanguage string) string {
+	best, bestWeight := defaultLanguage, 0.0
+
+	for _, v := range strings.Split(acceptLanguage, ",") {
+		parts := strings.Split(v, ";")
+		tag := strings.ToLower(strings.TrimSpace(parts[0]))
+		language := strings.SplitN(tag, "-", 2)[0]
+		if language == "*" {
+			language = defaultLanguage
+		}
+		if _, ok := bundles[language]; !ok {
+			continue
+		}
+
+		weight := 1.0
+		for _, param := range parts[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					weight = q
+				}
+			}
+		}
+
+		if weight > bestWeight {
+			best, bestWeight = language, weight
+		}
+	}
+
+	return best
+}
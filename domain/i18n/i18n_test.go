@@ -0,0 +1,43 @@
+package i18n
+
+import "testing"
+
+// Test_Negotiate tests that requests get the language of the catalog they
+// prefer the most, or the default one.
+func Test_Negotiate(t *testing.T) {
+	for header, expected := range map[string]string{
+		"":                          "es",
+		"en":                        "en",
+		"en-US,en;q=0.9":            "en",
+		"fr-FR, en;q=0.5, es;q=0.8": "es",
+		"fr, de":                    "es",
+		"es;q=0.2, en-GB;q=0.7, *":  "es",
+		"EN-gb;q=0.9, fr;q=1":       "en",
+		"en;q=0, es;q=0.1":          "es",
+	} {
+		if language := Negotiate(header); language != expected {
+			t.Errorf("Expected %q to negotiate %s, got %s", header, expected, language)
+		}
+	}
+}
+
+// Test_Translate tests that messages fall back to the default language and
+// then to their key, and that arguments that are messages are localized.
+func Test_Translate(t *testing.T) {
+	field := NewMessage("RULE_TYPE", "price", NewMessage("TYPE_NUMBER"))
+	if message := Translate("en", "VALIDATION_FAILED", List{field}); message != "The data sent is invalid: The field price must be a number" {
+		t.Errorf("Expected the message in english, got %s", message)
+	}
+
+	if message := Translate("fr", "PRODUCT_NOT_FOUND"); message != "El producto solicitado no existe" {
+		t.Errorf("Expected the message in the default language, got %s", message)
+	}
+
+	if message := Translate("en", "UNKNOWN"); message != "UNKNOWN" {
+		t.Errorf("Expected the key of an unknown message, got %s", message)
+	}
+
+	if err := SetDefaultLanguage("fr"); err == nil {
+		t.Errorf("Expected an error setting an unsupported default language")
+	}
+}
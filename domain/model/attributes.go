@@ -0,0 +1,50 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Attributes is a set of name/value pairs, such as size=M or color=red,
+// stored as a JSON document.
+type Attributes map[string]string
+
+// Value implements driver.Valuer.
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner.
+func (a *Attributes) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*a = Attributes{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type %T for attributes", value)
+	}
+
+	attributes := Attributes{}
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return err
+	}
+
+	*a = attributes
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package model
+
+import "codifin-challenge/domain/i18n"
+
+// CartSummary is a shopping cart together with the amounts computed from the
+// current price of its products, the discounts of its coupons and the taxes
+// of its region. Subtotal and DiscountTotal are net of taxes, and Total is
+// what the customer pays. PricesIncludeTax tells how the prices are displayed.
+type CartSummary struct {
+	Cart             *ShoppingCart
+	Lines            []*CartLine
+	Discounts        []*CartDiscount
+	ItemCount        uint
+	Subtotal         Money
+	DiscountTotal    Money
+	TaxTotal         Money
+	Total            Money
+	PricesIncludeTax bool
+}
+
+// CartLine is a cart item priced with the current price of its product. The
+// prices are net; Discount is the share of the coupon discounts taken off
+// the line, and Tax is charged on the discounted line total.
+type CartLine struct {
+	Item      *ItemCart
+	UnitPrice Money
+	LineTotal Money
+	Discount  Money
+	TaxRate   float64
+	Tax       Money
+}
+
+// CartDiscount is the amount a coupon takes off the cart. Coupons whose
+// promotion does not apply to the cart have a zero amount and a reason. Lines
+// are the cart lines the amount is taken from.
+type CartDiscount struct {
+	Promotion *Promotion
+	Amount    Money
+	Reason    i18n.Localizable
+	Lines     []*CartLine
+}
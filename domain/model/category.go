@@ -0,0 +1,11 @@
+package model
+
+import "gorm.io/gorm"
+
+type Category struct {
+	gorm.Model
+	Name     string      `gorm:"not null"`
+	ParentID *uint       `gorm:"index"`
+	Parent   *Category   `gorm:"foreignKey:ParentID"`
+	Children []*Category `gorm:"foreignKey:ParentID"`
+}
@@ -0,0 +1,11 @@
+package model
+
+import "gorm.io/gorm"
+
+// ExchangeRate is the number of units of a currency that one unit of the
+// default currency buys: a USD rate of 0.058 means 1 MXN is worth 0.058 USD.
+type ExchangeRate struct {
+	gorm.Model
+	Currency string  `gorm:"size:3;not null;uniqueIndex"`
+	Rate     float64 `gorm:"not null"`
+}
@@ -0,0 +1,373 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of the catalog prices and of amounts that
+// do not name one.
+const DefaultCurrency = "MXN"
+
+// minorUnits lists the currencies whose minor unit is not the cent.
+var minorUnits = map[string]int{
+	"BHD": 3,
+	"CLP": 0,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+}
+
+// MinorUnits returns the number of decimals of a currency, 2 unless the
+// currency is known to use another minor unit.
+func MinorUnits(currency string) int {
+	if digits, ok := minorUnits[currency]; ok {
+		return digits
+	}
+	return 2
+}
+
+// Money is an amount of a currency stored as an integer number of its minor
+// unit, so 12.30 MXN is stored as 1230. Money is a value: its operations
+// return new amounts and never modify the receiver.
+//
+// Rounding rules:
+//   - Parsing is exact: a decimal with more digits than the minor unit of
+//     the currency is rejected instead of rounded.
+//   - Add, Sub and Mul are exact integer arithmetic.
+//   - MulRate first rounds the rate to 6 decimals, then rounds the product
+//     half away from zero to the minor unit.
+//   - Convert first rounds the exchange rate to 8 decimals, then rounds the
+//     converted amount half away from zero to the minor unit of the target
+//     currency.
+//   - Allocate splits an amount in proportion to some weights, rounding every
+//     share half away from zero; the last share takes the difference, so the
+//     shares always add up to the amount.
+//
+// Operations on amounts of different currencies panic; the zero Money has no
+// currency and takes the currency of the other operand.
+type Money struct {
+	Amount   int64  `json:"amount" swaggertype:"string" example:"12.30"`
+	Currency string `gorm:"size:3" json:"currency" example:"MXN"`
+}
+
+// NewMoney returns an amount given in minor units of the currency.
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// ParseMoney parses a decimal such as "12.30" or "-5" as an amount of the
+// currency, the default currency if empty.
+func ParseMoney(s, currency string) (Money, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	if !IsCurrencyCode(currency) {
+		return Money{}, fmt.Errorf("invalid currency %q", currency)
+	}
+
+	s = strings.TrimSpace(s)
+	digits := MinorUnits(currency)
+
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+
+	whole, fraction, hasPoint := strings.Cut(s, ".")
+	if whole == "" || !isDigits(whole) || !isDigits(fraction) || (hasPoint && fraction == "") {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+	if len(fraction) > digits {
+		return Money{}, fmt.Errorf("amount %q has more than %d decimals for %s", s, digits, currency)
+	}
+	fraction += strings.Repeat("0", digits-len(fraction))
+
+	amount, err := strconv.ParseInt(sign+whole+fraction, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// IsCurrencyCode reports whether code looks like an ISO 4217 code.
+func IsCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits reports whether s only holds decimal digits.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the amount as a decimal with the digits of its minor unit.
+func (m Money) String() string {
+	digits := MinorUnits(m.currency())
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+	}
+
+	abs := new(big.Int).Abs(big.NewInt(amount)).String()
+	if digits == 0 {
+		return sign + abs
+	}
+	if len(abs) <= digits {
+		abs = strings.Repeat("0", digits-len(abs)+1) + abs
+	}
+
+	return sign + abs[:len(abs)-digits] + "." + abs[len(abs)-digits:]
+}
+
+// currency returns the currency of the amount or the default currency.
+func (m Money) currency() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// OrDefaultCurrency returns the amount in the default currency if it has no currency.
+func (m Money) OrDefaultCurrency() Money {
+	if m.Currency == "" {
+		m.Currency = DefaultCurrency
+	}
+	return m
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative reports whether the amount is below zero.
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Cmp compares two amounts of the same currency and returns -1, 0 or 1.
+func (m Money) Cmp(o Money) int {
+	sameCurrency(m, o)
+	switch {
+	case m.Amount < o.Amount:
+		return -1
+	case m.Amount > o.Amount:
+		return 1
+	}
+	return 0
+}
+
+// Add returns the sum of two amounts of the same currency.
+func (m Money) Add(o Money) Money {
+	return Money{Amount: m.Amount + o.Amount, Currency: sameCurrency(m, o)}
+}
+
+// Sub returns the difference of two amounts of the same currency.
+func (m Money) Sub(o Money) Money {
+	return Money{Amount: m.Amount - o.Amount, Currency: sameCurrency(m, o)}
+}
+
+// Mul returns the amount multiplied by a quantity.
+func (m Money) Mul(quantity uint) Money {
+	return Money{Amount: m.Amount * int64(quantity), Currency: m.Currency}
+}
+
+// MulRate returns the amount multiplied by a rate such as 0.16 for a 16%
+// tax. The rate is rounded to 6 decimals and the result half away from zero
+// to the minor unit.
+func (m Money) MulRate(rate float64) Money {
+	micros := int64(math.Round(rate * 1e6))
+	return Money{Amount: mulDiv(m.Amount, micros, 1e6), Currency: m.Currency}
+}
+
+// Gross adds a tax rate to a net amount, rounding the tax as MulRate does.
+func (m Money) Gross(rate float64) Money {
+	return m.Add(m.MulRate(rate))
+}
+
+// Convert returns the amount in another currency, given the units of that
+// currency one unit of this currency buys. The rate is rounded to 8 decimals
+// and the result half away from zero to the minor unit of the currency.
+func (m Money) Convert(currency string, rate float64) Money {
+	from := MinorUnits(m.currency())
+	to := MinorUnits(currency)
+
+	numerator := int64(math.Round(rate * 1e8))
+	denominator := int64(1e8)
+	if to > from {
+		numerator *= int64(math.Pow10(to - from))
+	} else {
+		denominator *= int64(math.Pow10(from - to))
+	}
+
+	return Money{Amount: mulDiv(m.Amount, numerator, denominator), Currency: currency}
+}
+
+// Min returns the smaller of two amounts of the same currency.
+func (m Money) Min(o Money) Money {
+	if m.Cmp(o) <= 0 {
+		return Money{Amount: m.Amount, Currency: sameCurrency(m, o)}
+	}
+	return Money{Amount: o.Amount, Currency: sameCurrency(m, o)}
+}
+
+// Allocate splits the amount in proportion to the weights. Every share is
+// rounded half away from zero and the last one takes the difference, so the
+// shares add up to the amount. Without positive weights nothing is allocated.
+func (m Money) Allocate(weights []int64) []Money {
+	shares := make([]Money, len(weights))
+
+	total := int64(0)
+	for _, w := range weights {
+		total += w
+	}
+	for i := range shares {
+		shares[i] = Money{Currency: m.Currency}
+	}
+	if total <= 0 || len(weights) == 0 {
+		return shares
+	}
+
+	remaining := m.Amount
+	for i, w := range weights {
+		share := remaining
+		if i < len(weights)-1 {
+			share = mulDiv(m.Amount, w, total)
+			remaining -= share
+		}
+		shares[i].Amount = share
+	}
+
+	return shares
+}
+
+// mulDiv computes a*b/c rounded half away from zero, without overflowing on
+// the intermediate product.
+func mulDiv(a, b, c int64) int64 {
+	product := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+	divisor := big.NewInt(c)
+
+	quotient, remainder := new(big.Int).QuoRem(product, divisor, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(new(big.Int).Abs(divisor)) >= 0 {
+		if product.Sign()*divisor.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	return quotient.Int64()
+}
+
+// sameCurrency returns the currency shared by two amounts, panicking if they
+// have different currencies.
+func sameCurrency(a, b Money) string {
+	switch {
+	case a.Currency == "":
+		return b.Currency
+	case b.Currency == "" || a.Currency == b.Currency:
+		return a.Currency
+	}
+	panic(fmt.Sprintf("money: mixing %s and %s amounts", a.Currency, b.Currency))
+}
+
+// moneyJSON is the JSON representation of Money.
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as a decimal string together with its
+// currency, such as {"amount":"12.30","currency":"MXN"}.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{Amount: m.String(), Currency: m.currency()})
+}
+
+// UnmarshalJSON decodes an object with a decimal amount, given as a string or
+// a number, and an optional currency. A bare decimal is also accepted as an
+// amount of the default currency. Numbers are parsed from their literal, so
+// 12.30 is exactly 1230 minor units.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var value moneyJSON
+	if len(data) > 0 && data[0] == '{' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	} else {
+		value.Amount = data
+	}
+
+	amount, err := decimalLiteral(value.Amount)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := ParseMoney(amount, value.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+
+	return nil
+}
+
+// decimalLiteral returns the decimal held by a JSON string or number.
+func decimalLiteral(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return "", fmt.Errorf("missing amount")
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return "", fmt.Errorf("invalid amount %s", raw)
+	}
+
+	s := n.String()
+	if strings.ContainsAny(s, "eE") {
+		f, err := n.Float64()
+		if err != nil {
+			return "", err
+		}
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,76 @@
+package model
+
+import "gorm.io/gorm"
+
+type OrderStatus string
+
+const (
+	OrderPending   OrderStatus = "pending"
+	OrderPaid      OrderStatus = "paid"
+	OrderShipped   OrderStatus = "shipped"
+	OrderDelivered OrderStatus = "delivered"
+	OrderCancelled OrderStatus = "cancelled"
+	OrderRefunded  OrderStatus = "refunded"
+)
+
+// orderTransitions lists the states an order can move to from each state.
+// Cancelled and refunded orders are final.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderPending:   {OrderPaid, OrderCancelled},
+	OrderPaid:      {OrderShipped, OrderRefunded},
+	OrderShipped:   {OrderDelivered},
+	OrderDelivered: {OrderRefunded},
+}
+
+// CanTransitionTo reports whether an order in this state can move to next.
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, v := range orderTransitions[s] {
+		if v == next {
+			return true
+		}
+	}
+	return false
+}
+
+type Order struct {
+	gorm.Model
+	ShoppingCartID   uint          `gorm:"not null;uniqueIndex"`
+	ShoppingCart     *ShoppingCart `gorm:"foreignKey:ShoppingCartID"`
+	Status           OrderStatus   `gorm:"not null;default:pending"`
+	Lines            []*OrderLine
+	Discounts        []*OrderDiscount
+	Region           string
+	ItemCount        uint
+	Subtotal         Money `gorm:"embedded;embeddedPrefix:subtotal_"`
+	DiscountTotal    Money `gorm:"embedded;embeddedPrefix:discount_total_"`
+	TaxTotal         Money `gorm:"embedded;embeddedPrefix:tax_total_"`
+	Total            Money `gorm:"embedded;embeddedPrefix:total_"`
+	PricesIncludeTax bool
+}
+
+// OrderLine is a frozen copy of a cart item taken at checkout, so later
+// changes to the product do not alter the order.
+type OrderLine struct {
+	gorm.Model
+	OrderID   uint `gorm:"not null"`
+	ProductID uint `gorm:"not null"`
+	VariantID *uint
+	Code      string
+	SKU       string
+	Name      string
+	UnitPrice Money `gorm:"embedded;embeddedPrefix:unit_price_"`
+	Count     uint
+	LineTotal Money `gorm:"embedded;embeddedPrefix:line_total_"`
+	Discount  Money `gorm:"embedded;embeddedPrefix:discount_"`
+	TaxRate   float64
+	Tax       Money `gorm:"embedded;embeddedPrefix:tax_"`
+}
+
+// OrderDiscount is a frozen copy of a coupon discount taken at checkout.
+type OrderDiscount struct {
+	gorm.Model
+	OrderID     uint `gorm:"not null"`
+	PromotionID uint `gorm:"not null"`
+	Code        string
+	Amount      Money `gorm:"embedded;embeddedPrefix:amount_"`
+}
@@ -0,0 +1,73 @@
+package model
+
+import "gorm.io/gorm"
+
+// ProductPrice is the price of a product in the price list of a currency
+// other than the default one, stored in minor units of that currency.
+type ProductPrice struct {
+	gorm.Model
+	ProductID uint   `gorm:"not null;uniqueIndex:idx_product_price"`
+	Currency  string `gorm:"size:3;not null;uniqueIndex:idx_product_price"`
+	Amount    int64  `gorm:"not null"`
+}
+
+// Price returns the list price as Money.
+func (p *ProductPrice) Price() Money {
+	return NewMoney(p.Amount, p.Currency)
+}
+
+// PriceBook prices products in a currency. A product is sold at its list
+// price in the currency if it has one, or else at its default currency price
+// converted with the exchange rate.
+type PriceBook struct {
+	Currency string
+	Rate     float64
+	Prices   map[uint]Money
+}
+
+// NewPriceBook returns the book of the default currency, where every product
+// is sold at its own price.
+func NewPriceBook() *PriceBook {
+	return &PriceBook{Currency: DefaultCurrency, Rate: 1, Prices: map[uint]Money{}}
+}
+
+// Convert returns an amount in the currency of the book. Amounts already in
+// that currency are returned as they are.
+func (b *PriceBook) Convert(m Money) Money {
+	m = m.OrDefaultCurrency()
+	if m.Currency == b.Currency {
+		return m
+	}
+	return m.Convert(b.Currency, b.Rate)
+}
+
+// ProductPrice returns the price of a product in the currency of the book.
+func (b *PriceBook) ProductPrice(p *Product) Money {
+	if price, ok := b.Prices[p.ID]; ok {
+		return price
+	}
+	return b.Convert(p.Price)
+}
+
+// Localize replaces the prices of a product and of its variants with their
+// prices in the currency of the book. Localizing a product twice has no
+// further effect.
+func (b *PriceBook) Localize(p *Product) {
+	if p == nil {
+		return
+	}
+
+	p.Price = b.ProductPrice(p)
+	for _, v := range p.Variants {
+		b.LocalizeVariant(v)
+	}
+}
+
+// LocalizeVariant replaces the price of a variant that does not inherit the
+// price of its product with its converted price.
+func (b *PriceBook) LocalizeVariant(v *ProductVariant) {
+	if v != nil && v.Price != nil {
+		price := b.Convert(*v.Price)
+		v.Price = &price
+	}
+}
@@ -1,11 +1,69 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type ProductStatus string
+
+// Products are drafted, sold while active, and archived when no longer sold.
+const (
+	ProductDraft    ProductStatus = "draft"
+	ProductActive   ProductStatus = "active"
+	ProductArchived ProductStatus = "archived"
+)
+
+// ProductStatuses are the statuses a product can have.
+var ProductStatuses = []ProductStatus{ProductDraft, ProductActive, ProductArchived}
+
+// IsValid reports whether s is one of the product statuses.
+func (s ProductStatus) IsValid() bool {
+	for _, v := range ProductStatuses {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
 
 type Product struct {
 	gorm.Model
-	Code     string
-	Name     string
-	Price    float64
-	ImageURL string
+	// Code identifies the product among the ones that are not deleted.
+	Code        string `gorm:"index:idx_products_code,unique,where:deleted_at IS NULL AND code <> ''"`
+	Name        string
+	Description string
+	Price       Money `gorm:"embedded;embeddedPrefix:price_"`
+	ImageURL    string
+	TaxCategory string `gorm:"not null;default:standard"`
+	Stock       uint   `gorm:"not null;default:0"`
+	Reserved    uint   `gorm:"not null;default:0"`
+	// Status tells whether the product is on sale. PublishAt and UnpublishAt
+	// schedule when a draft is activated and an active product archived,
+	// and are cleared when that happens.
+	Status      ProductStatus `gorm:"not null;default:active;index:idx_products_status"`
+	PublishAt   *time.Time
+	UnpublishAt *time.Time
+	// Version counts the changes made to the product, its variants and its
+	// prices, but not the units carts reserve or buy.
+	Version    uint        `gorm:"not null;default:1"`
+	Categories []*Category `gorm:"many2many:product_categories;"`
+	Variants   []*ProductVariant
+	// Score is the relevance of the product to the search it was listed by,
+	// 0 when products are not searched.
+	Score float64 `gorm:"->;-:migration"`
+}
+
+// Available returns the units in stock that are not reserved by a cart.
+func (p *Product) Available() uint {
+	if p.Reserved >= p.Stock {
+		return 0
+	}
+	return p.Stock - p.Reserved
+}
+
+// IsDeleted reports whether the product was soft deleted, as products loaded
+// for carts and by admins may be.
+func (p *Product) IsDeleted() bool {
+	return p.DeletedAt.Valid
 }
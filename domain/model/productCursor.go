@@ -0,0 +1,47 @@
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ProductCursor is a position in a list of products: the values the product
+// at that position has for the keys the list is ordered by, the last key
+// being its ID. Sort identifies the ordering, so a cursor is only used with
+// the ordering it was taken from. Before pages backwards from the position.
+type ProductCursor struct {
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+	Before bool          `json:"b,omitempty"`
+}
+
+// Encode returns the cursor as an opaque URL-safe string.
+func (c *ProductCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeProductCursor reads a cursor encoded by Encode. Numbers are decoded
+// as json.Number, so integers keep their precision.
+func DecodeProductCursor(s string) (*ProductCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var cursor ProductCursor
+	if err = decoder.Decode(&cursor); err != nil {
+		return nil, err
+	}
+
+	if cursor.Sort == "" || len(cursor.Values) == 0 {
+		return nil, errors.New("cursor without position")
+	}
+
+	return &cursor, nil
+}
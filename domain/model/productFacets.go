@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// PriceFacetBounds are the prices, in minor units of the default currency,
+// that split products into the buckets of the price facet.
+var PriceFacetBounds = []int64{10000, 25000, 50000, 100000}
+
+// CreatedFacetDays are the windows of the created facet, in days back from
+// the moment the products are listed.
+var CreatedFacetDays = []int{7, 30, 90, 365}
+
+// ProductList is a page of products, the total of products that pass the
+// filter and the facets they have. Lists paged by cursor have the cursors of
+// the next and previous pages, nil at either end of the list.
+type ProductList struct {
+	Products   []*Product
+	Total      uint
+	Facets     *ProductFacets
+	NextCursor *ProductCursor
+	PrevCursor *ProductCursor
+}
+
+// ProductFacets counts the products that pass a filter by the values of every
+// dimension they can be filtered by, so a storefront can offer those values as
+// filters.
+type ProductFacets struct {
+	Prices     []*PriceFacet
+	Categories []*CategoryFacet
+	Created    []*CreatedFacet
+	Attributes []*AttributeFacet
+}
+
+// PriceFacet counts the products priced from Min, inclusively, up to Max. The
+// last bucket has no Max.
+type PriceFacet struct {
+	Min   Money
+	Max   *Money
+	Count uint
+}
+
+// CategoryFacet counts the products assigned to a category.
+type CategoryFacet struct {
+	CategoryID uint
+	Name       string
+	Count      uint
+}
+
+// CreatedFacet counts the products created in the last Days days, since After.
+type CreatedFacet struct {
+	Days  int
+	After time.Time
+	Count uint
+}
+
+// AttributeFacet counts the products with a variant that has an attribute.
+type AttributeFacet struct {
+	Name  string
+	Value string
+	Count uint
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// ProductFilter holds the criteria to list products.
+type ProductFilter struct {
+	// Page is the page of PageSize products to list, counting from 1. When it
+	// is 0 products are listed from Cursor, or from the start without it.
+	Page       int
+	PageSize   int
+	Cursor     *ProductCursor
+	SearchTerm string
+	// Sort lists the fields products are sorted by, ties broken by ID.
+	// Searches without it are sorted by relevance.
+	Sort               []SortField
+	CategoryID         uint
+	IncludeDescendants bool
+	// MinPrice and MaxPrice bound the price of the products, inclusively and
+	// in the default currency. Variants priced apart do not count.
+	MinPrice *Money
+	MaxPrice *Money
+	// CreatedAfter and CreatedBefore bound when the products were created,
+	// the first inclusively and the second exclusively.
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// Attributes are the name/value pairs one variant of the products must
+	// have, all of them.
+	Attributes map[string]string
+	// Statuses are the statuses the products may have, any of them if empty.
+	Statuses []ProductStatus
+	// Currency is the currency the products are priced in, the default
+	// currency if empty. Products are still ordered by their own price.
+	Currency string
+	// CategoryIDs is resolved by the service from CategoryID, adding its
+	// descendants when IncludeDescendants is set.
+	CategoryIDs []uint
+}
@@ -0,0 +1,54 @@
+package model
+
+import "codifin-challenge/domain/i18n"
+
+// Statuses of the rows of a product import.
+const (
+	ImportCreated  = "created"
+	ImportUpdated  = "updated"
+	ImportRejected = "rejected"
+)
+
+// ImportRow is a product read from a row of an import. Fields lists the
+// fields the row has, by their JSON names, so updates leave the rest alone.
+// Err is set instead of Product when the row cannot be read.
+type ImportRow struct {
+	Line    int
+	Product *Product
+	Fields  map[string]bool
+	Err     error
+}
+
+// ImportResult is what an import did, or would do in a dry run, with a row.
+// Reason explains why a row was rejected.
+type ImportResult struct {
+	Line      int
+	Code      string
+	Status    string
+	ProductID uint
+	Reason    i18n.Localizable
+}
+
+// ImportReport is the result of every row of an import and how many rows
+// were created, updated and rejected.
+type ImportReport struct {
+	DryRun   bool
+	Created  uint
+	Updated  uint
+	Rejected uint
+	Rows     []*ImportResult
+}
+
+// Add records the result of a row.
+func (r *ImportReport) Add(result *ImportResult) {
+	switch result.Status {
+	case ImportCreated:
+		r.Created++
+	case ImportUpdated:
+		r.Updated++
+	default:
+		r.Rejected++
+	}
+
+	r.Rows = append(r.Rows, result)
+}
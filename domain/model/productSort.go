@@ -0,0 +1,44 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProductSortFields are the fields products can be sorted by.
+var ProductSortFields = []string{"id", "code", "name", "price", "createdAt"}
+
+// SortField is a field products are sorted by and its direction.
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// ParseSort parses a sort specification: fields separated by commas, each
+// descending if prefixed with a minus sign, such as -price,name. It checks the
+// syntax only; the fields are checked by whoever sorts by them.
+func ParseSort(spec string) ([]SortField, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	fields := make([]SortField, 0)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+
+		field := SortField{Field: part}
+		if strings.HasPrefix(part, "-") {
+			field = SortField{Field: strings.TrimSpace(part[1:]), Descending: true}
+		} else if strings.HasPrefix(part, "+") {
+			field.Field = strings.TrimSpace(part[1:])
+		}
+
+		if field.Field == "" {
+			return nil, fmt.Errorf("empty field in sort %q", spec)
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
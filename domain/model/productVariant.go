@@ -0,0 +1,35 @@
+package model
+
+import "gorm.io/gorm"
+
+// ProductVariant is a sellable version of a product, such as a size or a
+// color, with its own SKU and stock. When Price is nil the variant is sold at
+// the price of its product.
+type ProductVariant struct {
+	gorm.Model
+	ProductID  uint       `gorm:"not null;index"`
+	SKU        string     `gorm:"not null;index"`
+	Price      *Money     `gorm:"embedded;embeddedPrefix:price_"`
+	Stock      uint       `gorm:"not null;default:0"`
+	Reserved   uint       `gorm:"not null;default:0"`
+	Attributes Attributes `gorm:"type:text"`
+}
+
+// Available returns the units in stock that are not reserved by a cart.
+func (v *ProductVariant) Available() uint {
+	if v.Reserved >= v.Stock {
+		return 0
+	}
+	return v.Stock - v.Reserved
+}
+
+// UnitPrice returns the price of the variant, falling back to the price of its product.
+func (v *ProductVariant) UnitPrice(product *Product) Money {
+	if v.Price != nil {
+		return *v.Price
+	}
+	if product != nil {
+		return product.Price
+	}
+	return Money{}
+}
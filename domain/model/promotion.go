@@ -0,0 +1,75 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type PromotionType string
+
+const (
+	PromotionPercentage   PromotionType = "percentage"
+	PromotionFixedAmount  PromotionType = "fixed_amount"
+	PromotionBuyXGetY     PromotionType = "buy_x_get_y"
+	PromotionFreeItemOver PromotionType = "free_item_over_threshold"
+)
+
+// IsValid reports whether the type is one of the supported promotion rules.
+func (t PromotionType) IsValid() bool {
+	switch t {
+	case PromotionPercentage, PromotionFixedAmount, PromotionBuyXGetY, PromotionFreeItemOver:
+		return true
+	}
+	return false
+}
+
+// Promotion is a discount rule redeemed with a coupon code. Value is the
+// percentage off of percentage rules and AmountOff the amount taken off by
+// fixed amount rules. Threshold is
+// the minimum subtotal of the eligible lines for the rule to apply. UsageLimit
+// caps the orders that can redeem the code and PerCartLimit caps the units
+// discounted in a single cart; zero means no limit. A promotion without
+// products or categories applies to every product.
+type Promotion struct {
+	gorm.Model
+	Code          string `gorm:"not null;uniqueIndex"`
+	Description   string
+	Type          PromotionType `gorm:"not null"`
+	Value         float64
+	AmountOff     Money `gorm:"embedded;embeddedPrefix:amount_off_"`
+	BuyQuantity   uint
+	GetQuantity   uint
+	Threshold     Money `gorm:"embedded;embeddedPrefix:threshold_"`
+	FreeProductID *uint
+	StartsAt      *time.Time
+	EndsAt        *time.Time
+	UsageLimit    uint
+	UsageCount    uint `gorm:"not null;default:0"`
+	PerCartLimit  uint
+	Products      []*Product  `gorm:"many2many:promotion_products"`
+	Categories    []*Category `gorm:"many2many:promotion_categories"`
+}
+
+// IsActive reports whether the promotion validity window includes the instant.
+func (p *Promotion) IsActive(at time.Time) bool {
+	if p.StartsAt != nil && at.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !at.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// IsExhausted reports whether the promotion was already redeemed as many times as allowed.
+func (p *Promotion) IsExhausted() bool {
+	return p.UsageLimit > 0 && p.UsageCount >= p.UsageLimit
+}
+
+// CartCoupon is a promotion code applied to a shopping cart.
+type CartCoupon struct {
+	gorm.Model
+	ShoppingCartID uint       `gorm:"not null;uniqueIndex:idx_cart_coupon"`
+	PromotionID    uint       `gorm:"not null;uniqueIndex:idx_cart_coupon"`
+	Promotion      *Promotion `gorm:"foreignKey:PromotionID"`
+}
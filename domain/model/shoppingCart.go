@@ -1,17 +1,33 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"gorm.io/gorm"
+	"time"
+)
 
 type ShoppingCart struct {
 	gorm.Model
-	Items []*ItemCart
+	Items        []*ItemCart
+	Coupons      []*CartCoupon
+	Region       string `gorm:"not null;default:MX"`
+	CheckedOutAt *time.Time
+	// Version counts the changes made to the items and coupons of the cart.
+	Version uint `gorm:"not null;default:1"`
+}
+
+// IsLocked reports whether the cart was already checked out and no longer
+// accepts changes to its items.
+func (c *ShoppingCart) IsLocked() bool {
+	return c.CheckedOutAt != nil
 }
 
 type ItemCart struct {
 	gorm.Model
-	ShoppingCartID uint          `gorm:"not null"`
-	ShoppingCart   *ShoppingCart `gorm:"foreignKey:ShoppingCartID"`
-	ProductID      uint          `gorm:"not null"`
-	Product        *Product      `gorm:"foreignKey:ProductID"`
-	Count          uint          `gorm:"default:0"`
+	ShoppingCartID uint            `gorm:"not null"`
+	ShoppingCart   *ShoppingCart   `gorm:"foreignKey:ShoppingCartID"`
+	ProductID      uint            `gorm:"not null"`
+	Product        *Product        `gorm:"foreignKey:ProductID"`
+	VariantID      *uint           `gorm:"index"`
+	Variant        *ProductVariant `gorm:"foreignKey:VariantID"`
+	Count          uint            `gorm:"default:0"`
 }
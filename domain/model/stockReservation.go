@@ -0,0 +1,17 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// StockReservation holds units of a product for a shopping cart until it
+// expires, so other carts can not take them in the meantime.
+type StockReservation struct {
+	gorm.Model
+	ShoppingCartID uint      `gorm:"not null;index"`
+	ProductID      uint      `gorm:"not null;index"`
+	VariantID      *uint     `gorm:"index"`
+	Quantity       uint      `gorm:"not null"`
+	ExpiresAt      time.Time `gorm:"not null;index"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "gorm.io/gorm"
+
+const (
+	TaxStandard = "standard"
+	TaxExempt   = "exempt"
+)
+
+// TaxRule is the rate charged in a region on the products of a tax category,
+// as a fraction of the net price: 0.16 is a 16% tax.
+type TaxRule struct {
+	gorm.Model
+	Region      string  `gorm:"not null;uniqueIndex:idx_tax_rule"`
+	TaxCategory string  `gorm:"not null;uniqueIndex:idx_tax_rule"`
+	Rate        float64 `gorm:"not null;default:0"`
+}
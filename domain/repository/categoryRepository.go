@@ -0,0 +1,138 @@
+// Package repository provides implementations for interacting with category data in the database.
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+// CategoryRepository defines methods for interacting with category data.
+type CategoryRepository interface {
+	GetAll() ([]*model.Category, error)
+	GetByID(categoryID uint) (*model.Category, error)
+	GetByIDs(categoryIDs []uint) ([]*model.Category, error)
+	Create(c *model.Category) error
+	Update(c *model.Category) error
+	Delete(categoryID uint) error
+}
+
+// CategoryRepositoryImpl is an implementation of CategoryRepository.
+type CategoryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepository creates a new instance of CategoryRepositoryImpl.
+func NewCategoryRepository(db *gorm.DB) *CategoryRepositoryImpl {
+	return &CategoryRepositoryImpl{db: db}
+}
+
+// GetAll retrieves every category ordered by name.
+func (r *CategoryRepositoryImpl) GetAll() ([]*model.Category, error) {
+	var categories []*model.Category
+
+	err := r.db.Model(&model.Category{}).
+		Order("name ASC").
+		Find(&categories).Error
+	if err != nil {
+		return nil, utils.NewError(utils.ErrCategoryListFailed, err)
+	}
+
+	return categories, nil
+}
+
+// GetByID retrieves a category with its direct children by its ID.
+func (r *CategoryRepositoryImpl) GetByID(categoryID uint) (*model.Category, error) {
+	var category *model.Category
+
+	err := r.db.Model(&model.Category{}).
+		Preload("Children", func(db *gorm.DB) *gorm.DB {
+			return db.Order("name ASC")
+		}).
+		Where("id = ?", categoryID).
+		First(&category).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewError(utils.ErrCategoryNotFound, err)
+		}
+		return nil, utils.NewError(utils.ErrCategoryReadFailed, err)
+	}
+
+	return category, nil
+}
+
+// GetByIDs retrieves the categories with the given IDs.
+func (r *CategoryRepositoryImpl) GetByIDs(categoryIDs []uint) ([]*model.Category, error) {
+	categories := make([]*model.Category, 0)
+	if len(categoryIDs) == 0 {
+		return categories, nil
+	}
+
+	err := r.db.Model(&model.Category{}).
+		Where("id IN ?", categoryIDs).
+		Find(&categories).Error
+	if err != nil {
+		return nil, utils.NewError(utils.ErrCategoryListFailed, err)
+	}
+
+	return categories, nil
+}
+
+// Create adds a new category to the database.
+func (r *CategoryRepositoryImpl) Create(c *model.Category) error {
+	err := r.db.Omit("Parent", "Children").Create(c).Error
+	if err != nil {
+		return utils.NewError(utils.ErrCategoryCreateFailed, err)
+	}
+
+	return nil
+}
+
+// Update updates the name and parent of an existing category.
+func (r *CategoryRepositoryImpl) Update(c *model.Category) error {
+	err := r.db.Omit("Parent", "Children").Save(c).Error
+	if err != nil {
+		return utils.NewError(utils.ErrCategoryUpdateFailed, err)
+	}
+
+	return nil
+}
+
+// Delete deletes a category without subcategories and unassigns it from its products.
+func (r *CategoryRepositoryImpl) Delete(categoryID uint) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	var children int64
+	err := tx.Model(&model.Category{}).Where("parent_id = ?", categoryID).Count(&children).Error
+	if err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrCategoryDeleteFailed, err)
+	}
+
+	if children > 0 {
+		tx.Rollback()
+		return utils.NewError(utils.ErrCategoryHasChildren, fmt.Errorf("category %d has %d children", categoryID, children))
+	}
+
+	err = tx.Exec("DELETE FROM product_categories WHERE category_id = ?", categoryID).Error
+	if err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrCategoryDeleteFailed, err)
+	}
+
+	if err = tx.Delete(&model.Category{}, categoryID).Error; err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrCategoryDeleteFailed, err)
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrCategoryDeleteFailed, err)
+	}
+
+	return nil
+}
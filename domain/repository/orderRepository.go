@@ -0,0 +1,137 @@
+// Package repository provides implementations for interacting with order data in the database.
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+// OrderRepository defines methods for interacting with order data.
+type OrderRepository interface {
+	Checkout(order *model.Order, cartVersion uint) error
+	GetByID(orderID uint) (*model.Order, error)
+	UpdateStatus(orderID uint, from, to model.OrderStatus) error
+}
+
+// OrderRepositoryImpl is an implementation of OrderRepository.
+type OrderRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOrderRepository creates a new instance of OrderRepositoryImpl.
+func NewOrderRepository(db *gorm.DB) *OrderRepositoryImpl {
+	return &OrderRepositoryImpl{db: db}
+}
+
+// Checkout locks the shopping cart of the order, takes the units of every line
+// out of the product stock, redeems its coupons and stores the order with its
+// lines and discounts in a single transaction. The cart is only locked at the
+// version the order was built from, so the order has the items the cart has.
+// It fails if the cart was already checked out or changed since that version,
+// if there is not enough stock for a line or if a coupon reached its usage
+// limit.
+func (r *OrderRepositoryImpl) Checkout(order *model.Order, cartVersion uint) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	result := tx.Model(&model.ShoppingCart{}).
+		Where("id = ? AND checked_out_at IS NULL AND version = ?", order.ShoppingCartID, cartVersion).
+		Updates(map[string]interface{}{"checked_out_at": time.Now(), "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrCartCheckoutFailed, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return r.checkoutConflict(order.ShoppingCartID, cartVersion)
+	}
+
+	for _, line := range order.Lines {
+		if err := consumeStock(tx, order.ShoppingCartID, line.ProductID, line.VariantID, line.Count); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	for _, discount := range order.Discounts {
+		if err := redeemPromotion(tx, discount.PromotionID, discount.Code); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Create(order).Error; err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrOrderCreateFailed, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrOrderCreateFailed, err)
+	}
+
+	return nil
+}
+
+// checkoutConflict tells why a shopping cart could not be locked for its
+// checkout: it was already checked out, or it changed since the version the
+// order was built from.
+func (r *OrderRepositoryImpl) checkoutConflict(cartID, cartVersion uint) error {
+	var cart model.ShoppingCart
+	err := r.db.Where("id = ?", cartID).First(&cart).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.NewError(utils.ErrCartNotFound, err)
+	}
+	if err != nil {
+		return utils.NewError(utils.ErrCartCheckoutFailed, err)
+	}
+
+	if cart.IsLocked() {
+		return utils.NewError(utils.ErrCartLocked, fmt.Errorf("shopping cart %d is checked out", cartID))
+	}
+
+	return utils.NewError(utils.ErrCartChanged, fmt.Errorf("shopping cart %d is no longer at version %d", cartID, cartVersion))
+}
+
+// GetByID retrieves an order with its lines and discounts by its ID.
+func (r *OrderRepositoryImpl) GetByID(orderID uint) (*model.Order, error) {
+	var order model.Order
+
+	err := r.db.Model(&model.Order{}).
+		Preload("Lines").
+		Preload("Discounts").
+		Where("id = ?", orderID).
+		First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewError(utils.ErrOrderNotFound, err)
+		}
+		return nil, utils.NewError(utils.ErrOrderReadFailed, err)
+	}
+
+	return &order, nil
+}
+
+// UpdateStatus moves an order from one status to another. The update only
+// applies if the order is still in the expected status, so two concurrent
+// transitions can not both succeed.
+func (r *OrderRepositoryImpl) UpdateStatus(orderID uint, from, to model.OrderStatus) error {
+	result := r.db.Model(&model.Order{}).
+		Where("id = ? AND status = ?", orderID, from).
+		Update("status", to)
+	if result.Error != nil {
+		return utils.NewError(utils.ErrOrderUpdateFailed, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return utils.NewError(utils.ErrOrderStatusChanged, fmt.Errorf("order %d is no longer %s", orderID, from))
+	}
+
+	return nil
+}
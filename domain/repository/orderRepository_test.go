@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Test_Checkout tests that the Checkout function of the OrderRepository locks the shopping cart.
+func Test_Checkout(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{}, &model.ShoppingCart{}, &model.ItemCart{}, &model.StockReservation{}, &model.Order{}, &model.OrderLine{}, &model.OrderDiscount{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	cartRepo := NewShoppingCartRepository(db, time.Minute)
+	repo := NewOrderRepository(db)
+
+	product := &model.Product{Name: "Product", Price: model.NewMoney(1000, model.DefaultCurrency), Stock: 5}
+	if err = db.Create(product).Error; err != nil {
+		t.Fatalf("Error inserting product: %v", err)
+	}
+
+	cart := &model.ShoppingCart{Items: []*model.ItemCart{{ProductID: product.ID, Count: 2}}}
+	if err = cartRepo.Create(cart); err != nil {
+		t.Fatalf("Error creating shopping cart: %v", err)
+	}
+
+	order := &model.Order{
+		ShoppingCartID: cart.ID,
+		Status:         model.OrderPending,
+		Lines:          []*model.OrderLine{{ProductID: product.ID, UnitPrice: model.NewMoney(1000, model.DefaultCurrency), Count: 2, LineTotal: model.NewMoney(2000, model.DefaultCurrency)}},
+	}
+	if err = repo.Checkout(order, cart.Version); err != nil {
+		t.Fatalf("Error checking out shopping cart: %v", err)
+	}
+
+	err = cartRepo.AddItems([]*model.ItemCart{{ShoppingCartID: cart.ID, ProductID: product.ID, Count: 1}}, nil)
+	if err == nil || utils.GetCustomError(err).Status != http.StatusConflict {
+		t.Errorf("Expected a conflict adding items to a checked out cart, got %v", err)
+	}
+
+	err = repo.Checkout(&model.Order{ShoppingCartID: cart.ID, Status: model.OrderPending}, cart.Version)
+	if err == nil || utils.GetCustomError(err).Status != http.StatusConflict {
+		t.Errorf("Expected a conflict checking out the cart twice, got %v", err)
+	}
+
+	created, err := repo.GetByID(order.ID)
+	if err != nil {
+		t.Fatalf("Error getting created order: %v", err)
+	}
+
+	if len(created.Lines) != 1 || created.Lines[0].LineTotal.Amount != 2000 {
+		t.Errorf("Expected the order to keep its frozen line, got %+v", created.Lines)
+	}
+
+	var stocked model.Product
+	if err = db.First(&stocked, product.ID).Error; err != nil {
+		t.Fatalf("Error getting product: %v", err)
+	}
+
+	if stocked.Stock != 3 || stocked.Reserved != 0 {
+		t.Errorf("Expected 3 units in stock and none reserved, found %d and %d", stocked.Stock, stocked.Reserved)
+	}
+}
+
+// Test_CheckoutChangedCart tests that Checkout does not lock a shopping cart
+// that changed since the version its order was built from.
+func Test_CheckoutChangedCart(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{}, &model.ShoppingCart{}, &model.ItemCart{}, &model.StockReservation{}, &model.Order{}, &model.OrderLine{}, &model.OrderDiscount{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	cartRepo := NewShoppingCartRepository(db, time.Minute)
+	repo := NewOrderRepository(db)
+
+	product := &model.Product{Name: "Product", Price: model.NewMoney(1000, model.DefaultCurrency), Stock: 5}
+	if err = db.Create(product).Error; err != nil {
+		t.Fatalf("Error inserting product: %v", err)
+	}
+
+	cart := &model.ShoppingCart{Items: []*model.ItemCart{{ProductID: product.ID, Count: 2}}}
+	if err = cartRepo.Create(cart); err != nil {
+		t.Fatalf("Error creating shopping cart: %v", err)
+	}
+
+	if err = cartRepo.DeleteProducts(cart.ID, []uint{product.ID}, nil); err != nil {
+		t.Fatalf("Error removing products from shopping cart: %v", err)
+	}
+
+	order := &model.Order{
+		ShoppingCartID: cart.ID,
+		Status:         model.OrderPending,
+		Lines:          []*model.OrderLine{{ProductID: product.ID, UnitPrice: model.NewMoney(1000, model.DefaultCurrency), Count: 2, LineTotal: model.NewMoney(2000, model.DefaultCurrency)}},
+	}
+	err = repo.Checkout(order, cart.Version)
+	if e, ok := err.(*utils.DBError); !ok || e.Code != utils.ErrCartChanged {
+		t.Fatalf("Expected the checkout of a changed cart to fail, got %v", err)
+	}
+
+	var stocked model.Product
+	if err = db.First(&stocked, product.ID).Error; err != nil {
+		t.Fatalf("Error getting product: %v", err)
+	}
+
+	if stocked.Stock != 5 || stocked.Reserved != 0 {
+		t.Errorf("Expected 5 units in stock and none reserved, found %d and %d", stocked.Stock, stocked.Reserved)
+	}
+
+	if err = cartRepo.AddItems([]*model.ItemCart{{ShoppingCartID: cart.ID, ProductID: product.ID, Count: 1}}, nil); err != nil {
+		t.Errorf("Expected the cart to stay open, got %v", err)
+	}
+}
+
+// Test_UpdateStatus tests the UpdateStatus function of the OrderRepository.
+func Test_UpdateStatus(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.ShoppingCart{}, &model.Order{}, &model.OrderLine{}, &model.OrderDiscount{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	repo := NewOrderRepository(db)
+
+	order := &model.Order{ShoppingCartID: 1, Status: model.OrderPending}
+	if err = db.Create(order).Error; err != nil {
+		t.Fatalf("Error inserting order: %v", err)
+	}
+
+	if err = repo.UpdateStatus(order.ID, model.OrderPending, model.OrderPaid); err != nil {
+		t.Fatalf("Error updating order status: %v", err)
+	}
+
+	err = repo.UpdateStatus(order.ID, model.OrderPending, model.OrderCancelled)
+	if err == nil || utils.GetCustomError(err).Status != http.StatusConflict {
+		t.Errorf("Expected a conflict updating a stale status, got %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+// Package repository provides implementations for interacting with exchange rate and price list data in the database.
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+// PriceRepository defines methods for interacting with exchange rate and price list data.
+type PriceRepository interface {
+	GetExchangeRates() ([]*model.ExchangeRate, error)
+	GetExchangeRate(currency string) (*model.ExchangeRate, error)
+	SaveExchangeRate(rate *model.ExchangeRate) error
+	DeleteExchangeRate(currency string) error
+	GetProductPrices(productID uint) ([]*model.ProductPrice, error)
+	GetListPrices(currency string, productIDs []uint) ([]*model.ProductPrice, error)
+	SaveProductPrice(price *model.ProductPrice) error
+	DeleteProductPrice(productID uint, currency string) error
+}
+
+// PriceRepositoryImpl is an implementation of PriceRepository.
+type PriceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPriceRepository creates a new instance of PriceRepositoryImpl.
+func NewPriceRepository(db *gorm.DB) *PriceRepositoryImpl {
+	return &PriceRepositoryImpl{db: db}
+}
+
+// GetExchangeRates retrieves every exchange rate ordered by currency.
+func (r *PriceRepositoryImpl) GetExchangeRates() ([]*model.ExchangeRate, error) {
+	var rates []*model.ExchangeRate
+
+	err := r.db.Model(&model.ExchangeRate{}).Order("currency").Find(&rates).Error
+	if err != nil {
+		return nil, utils.NewError(utils.ErrExchangeRateListFailed, err)
+	}
+
+	return rates, nil
+}
+
+// GetExchangeRate retrieves the exchange rate of a currency.
+func (r *PriceRepositoryImpl) GetExchangeRate(currency string) (*model.ExchangeRate, error) {
+	var rate *model.ExchangeRate
+
+	err := r.db.Model(&model.ExchangeRate{}).Where("currency = ?", currency).First(&rate).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewError(utils.ErrExchangeRateNotFound, err, currency)
+		}
+		return nil, utils.NewError(utils.ErrExchangeRateReadFailed, err)
+	}
+
+	return rate, nil
+}
+
+// SaveExchangeRate creates the exchange rate of a currency or replaces the
+// one it already has.
+func (r *PriceRepositoryImpl) SaveExchangeRate(rate *model.ExchangeRate) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	var stored model.ExchangeRate
+	err := tx.Where("currency = ?", rate.Currency).Limit(1).Find(&stored).Error
+	if err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrExchangeRateSaveFailed, err)
+	}
+
+	if stored.ID != 0 {
+		rate.ID, rate.CreatedAt = stored.ID, stored.CreatedAt
+	}
+
+	if err = tx.Save(rate).Error; err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrExchangeRateSaveFailed, err)
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrExchangeRateSaveFailed, err)
+	}
+
+	return nil
+}
+
+// DeleteExchangeRate deletes the exchange rate of a currency for good, so it
+// can be registered again.
+func (r *PriceRepositoryImpl) DeleteExchangeRate(currency string) error {
+	result := r.db.Unscoped().Where("currency = ?", currency).Delete(&model.ExchangeRate{})
+	if result.Error != nil {
+		return utils.NewError(utils.ErrExchangeRateDeleteFailed, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return utils.NewError(utils.ErrExchangeRateNotFound, fmt.Errorf("exchange rate %s not found", currency), currency)
+	}
+
+	return nil
+}
+
+// GetProductPrices retrieves the list prices of a product ordered by currency.
+func (r *PriceRepositoryImpl) GetProductPrices(productID uint) ([]*model.ProductPrice, error) {
+	var prices []*model.ProductPrice
+
+	err := r.db.Model(&model.ProductPrice{}).Where("product_id = ?", productID).Order("currency").Find(&prices).Error
+	if err != nil {
+		return nil, utils.NewError(utils.ErrProductPriceListFailed, err)
+	}
+
+	return prices, nil
+}
+
+// GetListPrices retrieves the prices of some products in the price list of a currency.
+func (r *PriceRepositoryImpl) GetListPrices(currency string, productIDs []uint) ([]*model.ProductPrice, error) {
+	var prices []*model.ProductPrice
+
+	if len(productIDs) == 0 {
+		return prices, nil
+	}
+
+	err := r.db.Model(&model.ProductPrice{}).
+		Where("currency = ? AND product_id IN ?", currency, productIDs).
+		Find(&prices).Error
+	if err != nil {
+		return nil, utils.NewError(utils.ErrPriceListFailed, err)
+	}
+
+	return prices, nil
+}
+
+// SaveProductPrice creates the price of a product in a currency or replaces
+// the one it already has.
+func (r *PriceRepositoryImpl) SaveProductPrice(price *model.ProductPrice) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	var stored model.ProductPrice
+	err := tx.Where("product_id = ? AND currency = ?", price.ProductID, price.Currency).Limit(1).Find(&stored).Error
+	if err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrProductPriceSaveFailed, err)
+	}
+
+	if stored.ID != 0 {
+		price.ID, price.CreatedAt = stored.ID, stored.CreatedAt
+	}
+
+	if err = tx.Save(price).Error; err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrProductPriceSaveFailed, err)
+	}
+
+	if err = touchProduct(tx, price.ProductID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrProductPriceSaveFailed, err)
+	}
+
+	return nil
+}
+
+// DeleteProductPrice deletes the price of a product in a currency for good,
+// so the product goes back to its converted price.
+func (r *PriceRepositoryImpl) DeleteProductPrice(productID uint, currency string) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	result := tx.Unscoped().Where("product_id = ? AND currency = ?", productID, currency).Delete(&model.ProductPrice{})
+	if result.Error != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrProductPriceDeleteFailed, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return utils.NewError(utils.ErrProductPriceNotFound, fmt.Errorf("price of product %d in %s not found", productID, currency), currency)
+	}
+
+	if err := touchProduct(tx, productID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrProductPriceDeleteFailed, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"net/http"
+	"testing"
+)
+
+// Test_SaveExchangeRate tests that the SaveExchangeRate function of the PriceRepository replaces the rate of a currency.
+func Test_SaveExchangeRate(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.ExchangeRate{}, &model.Product{}, &model.ProductPrice{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	repo := NewPriceRepository(db)
+
+	for _, rate := range []float64{0.05, 0.058} {
+		if err = repo.SaveExchangeRate(&model.ExchangeRate{Currency: "USD", Rate: rate}); err != nil {
+			t.Fatalf("Error saving exchange rate: %v", err)
+		}
+	}
+
+	rates, err := repo.GetExchangeRates()
+	if err != nil {
+		t.Fatalf("Error getting exchange rates: %v", err)
+	}
+
+	if len(rates) != 1 || rates[0].Rate != 0.058 {
+		t.Errorf("Expected a single USD rate of 0.058, got %+v", rates)
+	}
+
+	if err = repo.DeleteExchangeRate("USD"); err != nil {
+		t.Fatalf("Error deleting exchange rate: %v", err)
+	}
+
+	_, err = repo.GetExchangeRate("USD")
+	if err == nil || utils.GetCustomError(err).Status != http.StatusNotFound {
+		t.Errorf("Expected a deleted exchange rate not to be found, got %v", err)
+	}
+}
+
+// Test_GetListPrices tests the GetListPrices function of the PriceRepository.
+func Test_GetListPrices(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.ExchangeRate{}, &model.Product{}, &model.ProductPrice{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	repo := NewPriceRepository(db)
+
+	prices := []*model.ProductPrice{
+		{ProductID: 1, Currency: "USD", Amount: 999},
+		{ProductID: 1, Currency: "EUR", Amount: 899},
+		{ProductID: 2, Currency: "USD", Amount: 1999},
+	}
+	for _, v := range prices {
+		if err = repo.SaveProductPrice(v); err != nil {
+			t.Fatalf("Error saving product price: %v", err)
+		}
+	}
+
+	listed, err := repo.GetListPrices("USD", []uint{1, 3})
+	if err != nil {
+		t.Fatalf("Error getting list prices: %v", err)
+	}
+
+	if len(listed) != 1 || listed[0].Price() != model.NewMoney(999, "USD") {
+		t.Fatalf("Expected the USD price of product 1, got %+v", listed)
+	}
+
+	book := &model.PriceBook{Currency: "USD", Rate: 0.058, Prices: map[uint]model.Money{1: listed[0].Price()}}
+	listedProduct := &model.Product{Model: gorm.Model{ID: 1}, Price: model.NewMoney(20000, model.DefaultCurrency)}
+	convertedProduct := &model.Product{Model: gorm.Model{ID: 3}, Price: model.NewMoney(20000, model.DefaultCurrency)}
+
+	if price := book.ProductPrice(listedProduct); price != model.NewMoney(999, "USD") {
+		t.Errorf("Expected the list price 9.99 USD, got %s %s", price, price.Currency)
+	}
+
+	if price := book.ProductPrice(convertedProduct); price != model.NewMoney(1160, "USD") {
+		t.Errorf("Expected the converted price 11.60 USD, got %s %s", price, price.Currency)
+	}
+}
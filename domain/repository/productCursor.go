@@ -0,0 +1,188 @@
+// Package repository provides implementations for paging product data by cursor.
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+)
+
+// GetListByCursor retrieves a page of products that follows, or precedes, the
+// position of the cursor of the filter, or the first page without it. Unlike
+// offsets, cursors keep their position when products are added or removed.
+func (r *ProductRepositoryImpl) GetListByCursor(filter *model.ProductFilter) (*model.ProductList, error) {
+	var products []*model.Product
+	var total int64
+
+	query, terms, score, err := r.filteredQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := sortKeys(filter.Sort, len(terms) > 0, score)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = query.Count(&total).Error; err != nil {
+		return nil, utils.NewError(utils.ErrProductCountFailed, err)
+	}
+
+	if len(terms) > 0 {
+		query = query.Select("products.*, ? AS score", score)
+	}
+
+	before := false
+	cursor := filter.Cursor
+	if cursor != nil {
+		values, err := cursorValues(keys, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		before = cursor.Before
+		query = query.Where(keysetCondition(keys, values, before))
+	}
+
+	query = orderBy(query, keys, before)
+
+	err = query.Preload("Categories").
+		Preload("Variants").
+		Limit(filter.PageSize + 1).
+		Find(&products).Error
+	if err != nil {
+		return nil, utils.NewError(utils.ErrProductListFailed, err)
+	}
+
+	// The extra product tells whether there are more in the direction paged.
+	more := len(products) > filter.PageSize
+	if more {
+		products = products[:filter.PageSize]
+	}
+
+	if before {
+		for i, j := 0, len(products)-1; i < j; i, j = i+1, j-1 {
+			products[i], products[j] = products[j], products[i]
+		}
+	}
+
+	list := &model.ProductList{Products: products, Total: uint(total)}
+	if len(products) == 0 {
+		return list, nil
+	}
+
+	if more || before {
+		list.NextCursor = productCursor(keys, products[len(products)-1], false)
+	}
+
+	if (more && before) || (cursor != nil && !before) {
+		list.PrevCursor = productCursor(keys, products[0], true)
+	}
+
+	return list, nil
+}
+
+// ForEachBatch calls fn with the products that pass the filter, in the order
+// of its sort, in batches of up to size products. Every batch is read after
+// the last product of the previous one, so reading stays fast to the end.
+func (r *ProductRepositoryImpl) ForEachBatch(filter *model.ProductFilter, size int, fn func([]*model.Product) error) error {
+	query, terms, score, err := r.filteredQuery(filter)
+	if err != nil {
+		return err
+	}
+
+	keys, err := sortKeys(filter.Sort, len(terms) > 0, score)
+	if err != nil {
+		return err
+	}
+
+	if len(terms) > 0 {
+		query = query.Select("products.*, ? AS score", score)
+	}
+
+	var last *model.Product
+	for {
+		batch := query.Session(&gorm.Session{})
+		if last != nil {
+			batch = batch.Where(keysetCondition(keys, productCursor(keys, last, false).Values, false))
+		}
+
+		var products []*model.Product
+		err = orderBy(batch, keys, false).Preload("Categories").Limit(size).Find(&products).Error
+		if err != nil {
+			return utils.NewError(utils.ErrProductListFailed, err)
+		}
+
+		if len(products) == 0 {
+			return nil
+		}
+
+		if err = fn(products); err != nil {
+			return err
+		}
+
+		if len(products) < size {
+			return nil
+		}
+		last = products[len(products)-1]
+	}
+}
+
+// productCursor returns the cursor of the position of a product.
+func productCursor(keys []sortKey, p *model.Product, before bool) *model.ProductCursor {
+	values := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, key.field.value(p))
+	}
+
+	return &model.ProductCursor{Sort: sortSignature(keys), Values: values, Before: before}
+}
+
+// cursorValues returns the values of the keys at the position of a cursor,
+// failing if it was taken from another ordering.
+func cursorValues(keys []sortKey, cursor *model.ProductCursor) ([]interface{}, error) {
+	if cursor.Sort != sortSignature(keys) || len(cursor.Values) != len(keys) {
+		return nil, utils.NewError(utils.ErrCursorSortMismatch, fmt.Errorf("cursor for %s used with %s", cursor.Sort, sortSignature(keys)))
+	}
+
+	values := make([]interface{}, 0, len(keys))
+	for i, key := range keys {
+		value, ok := key.field.decode(cursor.Values[i])
+		if !ok {
+			return nil, utils.NewError(utils.ErrInvalidCursor, fmt.Errorf("invalid cursor value %v for %s", cursor.Values[i], key.name))
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// keysetCondition returns the condition of the products after the values of
+// the keys, or before them, in lexicographic order: the first key that differs
+// decides.
+func keysetCondition(keys []sortKey, values []interface{}, before bool) clause.Expr {
+	alternatives := make([]string, 0, len(keys))
+	vars := make([]interface{}, 0)
+
+	for i, key := range keys {
+		conditions := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, "? = ?")
+			vars = append(vars, keys[j].expr, values[j])
+		}
+
+		operator := ">"
+		if key.desc != before {
+			operator = "<"
+		}
+		conditions = append(conditions, "? "+operator+" ?")
+		vars = append(vars, key.expr, values[i])
+
+		alternatives = append(alternatives, "("+strings.Join(conditions, " AND ")+")")
+	}
+
+	return clause.Expr{SQL: "(" + strings.Join(alternatives, " OR ") + ")", Vars: vars}
+}
@@ -3,107 +3,550 @@ package repository
 
 import (
 	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/search"
 	"codifin-challenge/domain/utils"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"gorm.io/gorm"
-	"net/http"
+	"gorm.io/gorm/clause"
+	"sort"
+	"strings"
+	"time"
 )
 
 // ProductRepository defines methods for interacting with product data.
 type ProductRepository interface {
-	GetList(page, pageSize int, searchTerm, orderBy string, ascending bool) ([]*model.Product, uint, error)
+	GetList(filter *model.ProductFilter) ([]*model.Product, uint, error)
+	GetListByCursor(filter *model.ProductFilter) (*model.ProductList, error)
+	ForEachBatch(filter *model.ProductFilter, size int, fn func([]*model.Product) error) error
+	GetFacets(filter *model.ProductFilter) (*model.ProductFacets, error)
 	GetByID(productID uint) (*model.Product, error)
+	GetByCode(code string) (*model.Product, error)
 	Create(p *model.Product) error
 	Update(p *model.Product) error
-	Delete(productID uint) error
+	Delete(productID uint, version *uint) error
+	ApplySchedule(now time.Time) (published int64, archived int64, err error)
+	GetDeletedList(page, pageSize int) ([]*model.Product, uint, error)
+	Restore(productID uint) error
+	Purge(deletedBefore time.Time) (int64, error)
 }
 
 // ProductRepositoryImpl is an implementation of ProductRepository.
 type ProductRepositoryImpl struct {
-	db *gorm.DB
+	db       *gorm.DB
+	searcher ProductSearcher
 }
 
 // NewProductRepository creates a new instance of ProductRepositoryImpl.
 func NewProductRepository(db *gorm.DB) *ProductRepositoryImpl {
-	return &ProductRepositoryImpl{db: db}
+	return &ProductRepositoryImpl{db: db, searcher: NewProductSearcher(db)}
 }
 
-// GetList retrieves a list of products with pagination support.
-func (r *ProductRepositoryImpl) GetList(page, pageSize int, searchTerm, orderBy string, ascending bool) ([]*model.Product, uint, error) {
+// GetList retrieves a list of products with pagination support. Searched
+// products are scored by relevance, and ordered by it unless the filter
+// sorts them by its fields.
+func (r *ProductRepositoryImpl) GetList(filter *model.ProductFilter) ([]*model.Product, uint, error) {
 	var products []*model.Product
 	var total int64
 
-	query := r.db.Model(&model.Product{})
+	query, terms, score, err := r.filteredQuery(filter)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	if len(searchTerm) > 0 {
-		query = query.Where(r.db.Where("name ILIKE ?", "%"+searchTerm+"%").
-			Or("name ILIKE ?", "%"+searchTerm+"%"))
+	keys, err := sortKeys(filter.Sort, len(terms) > 0, score)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, utils.ToUserError(http.StatusInternalServerError, "No fue posible obtener el total productos debido a un error interno", err)
+	if err = query.Count(&total).Error; err != nil {
+		return nil, 0, utils.NewError(utils.ErrProductCountFailed, err)
 	}
 
-	if orderBy != "" {
-		orderDirection := "ASC"
-		if !ascending {
-			orderDirection = "DESC"
-		}
-		query = query.Order(orderBy + " " + orderDirection)
+	if len(terms) > 0 {
+		query = query.Select("products.*, ? AS score", score)
 	}
 
-	err := query.Offset((page - 1) * pageSize).
-		Limit(pageSize).
+	query = orderBy(query, keys, false)
+
+	err = query.Preload("Categories").
+		Preload("Variants").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
 		Find(&products).Error
 	if err != nil {
-		return nil, 0, utils.ToUserError(http.StatusInternalServerError, "No fue posible obtener la lista de productos debido a un error interno", err)
+		return nil, 0, utils.NewError(utils.ErrProductListFailed, err)
 	}
 
 	return products, uint(total), nil
 }
 
+// filteredQuery returns the query of the products that pass the filter, with
+// the terms of its search and the expression of their score when searching.
+func (r *ProductRepositoryImpl) filteredQuery(filter *model.ProductFilter) (*gorm.DB, []string, clause.Expr, error) {
+	query := r.db.Model(&model.Product{})
+
+	if len(filter.CategoryIDs) > 0 {
+		query = query.Where("products.id IN (?)", r.db.Table("product_categories").
+			Select("product_id").
+			Where("category_id IN ?", filter.CategoryIDs))
+	}
+
+	if len(filter.Statuses) > 0 {
+		query = query.Where("products.status IN ?", filter.Statuses)
+	}
+
+	if filter.MinPrice != nil {
+		query = query.Where("products.price_amount >= ?", filter.MinPrice.Amount)
+	}
+
+	if filter.MaxPrice != nil {
+		query = query.Where("products.price_amount <= ?", filter.MaxPrice.Amount)
+	}
+
+	if filter.CreatedAfter != nil {
+		query = query.Where("products.created_at >= ?", *filter.CreatedAfter)
+	}
+
+	if filter.CreatedBefore != nil {
+		query = query.Where("products.created_at < ?", *filter.CreatedBefore)
+	}
+
+	if len(filter.Attributes) > 0 {
+		variants := r.db.Model(&model.ProductVariant{}).Select("product_id")
+		for name, value := range filter.Attributes {
+			variants = variants.Where("attributes LIKE ? ESCAPE '!'", attributePattern(name, value))
+		}
+		query = query.Where("products.id IN (?)", variants)
+	}
+
+	var score clause.Expr
+	terms := search.Terms(filter.SearchTerm)
+	if len(terms) > 0 {
+		var err error
+		if query, score, err = r.searcher.Match(query, terms); err != nil {
+			return nil, nil, score, err
+		}
+	}
+
+	return query, terms, score, nil
+}
+
+// attributePattern returns the LIKE pattern that matches the attributes of a
+// variant with an attribute. Attributes are stored as JSON objects encoded by
+// encoding/json, whose output is the same for the same pair wherever it
+// appears in the object.
+func attributePattern(name, value string) string {
+	pair := model.Attributes{name: value}
+	data, _ := json.Marshal(pair)
+
+	escaped := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(string(data[1 : len(data)-1]))
+	return "%" + escaped + "%"
+}
+
+// GetFacets counts the products that pass the filter by price bucket,
+// category, creation window and variant attribute.
+func (r *ProductRepositoryImpl) GetFacets(filter *model.ProductFilter) (*model.ProductFacets, error) {
+	query, _, _, err := r.filteredQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	facets := &model.ProductFacets{}
+
+	if facets.Prices, err = r.priceFacets(query); err != nil {
+		return nil, utils.NewError(utils.ErrPriceFacetsFailed, err)
+	}
+
+	if facets.Categories, err = r.categoryFacets(query); err != nil {
+		return nil, utils.NewError(utils.ErrCategoryFacetsFailed, err)
+	}
+
+	if facets.Created, err = r.createdFacets(query); err != nil {
+		return nil, utils.NewError(utils.ErrDateFacetsFailed, err)
+	}
+
+	if facets.Attributes, err = r.attributeFacets(query); err != nil {
+		return nil, utils.NewError(utils.ErrAttributeFacetsFailed, err)
+	}
+
+	return facets, nil
+}
+
+// bucketCounts counts the products of the query by the first of some
+// conditions they meet, numbered from 0, or by the number of conditions if
+// they meet none.
+func bucketCounts(query *gorm.DB, conditions []string, vars []interface{}) (map[int]uint, error) {
+	var rows []struct {
+		Bucket int
+		Count  uint
+	}
+
+	cases := make([]string, 0, len(conditions))
+	for i, condition := range conditions {
+		cases = append(cases, fmt.Sprintf("WHEN %s THEN %d", condition, i))
+	}
+
+	selection := fmt.Sprintf("CASE %s ELSE %d END AS bucket, COUNT(*) AS count", strings.Join(cases, " "), len(conditions))
+	err := query.Session(&gorm.Session{}).Select(selection, vars...).Group("bucket").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]uint, len(rows))
+	for _, row := range rows {
+		counts[row.Bucket] = row.Count
+	}
+
+	return counts, nil
+}
+
+func (r *ProductRepositoryImpl) priceFacets(query *gorm.DB) ([]*model.PriceFacet, error) {
+	conditions := make([]string, 0, len(model.PriceFacetBounds))
+	vars := make([]interface{}, 0, len(model.PriceFacetBounds))
+	for _, bound := range model.PriceFacetBounds {
+		conditions = append(conditions, "products.price_amount < ?")
+		vars = append(vars, bound)
+	}
+
+	counts, err := bucketCounts(query, conditions, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	facets := make([]*model.PriceFacet, 0, len(model.PriceFacetBounds)+1)
+	min := model.NewMoney(0, model.DefaultCurrency)
+	for i := 0; i <= len(model.PriceFacetBounds); i++ {
+		facet := &model.PriceFacet{Min: min, Count: counts[i]}
+		if i < len(model.PriceFacetBounds) {
+			max := model.NewMoney(model.PriceFacetBounds[i], model.DefaultCurrency)
+			facet.Max = &max
+			min = max
+		}
+		facets = append(facets, facet)
+	}
+
+	return facets, nil
+}
+
+func (r *ProductRepositoryImpl) createdFacets(query *gorm.DB) ([]*model.CreatedFacet, error) {
+	now := time.Now()
+
+	// The windows are nested, so products are counted in the shortest window
+	// they were created in and added up to the longer ones.
+	conditions := make([]string, 0, len(model.CreatedFacetDays))
+	vars := make([]interface{}, 0, len(model.CreatedFacetDays))
+	for _, days := range model.CreatedFacetDays {
+		conditions = append(conditions, "products.created_at >= ?")
+		vars = append(vars, now.AddDate(0, 0, -days))
+	}
+
+	counts, err := bucketCounts(query, conditions, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	facets := make([]*model.CreatedFacet, 0, len(model.CreatedFacetDays))
+	var count uint
+	for i, days := range model.CreatedFacetDays {
+		count += counts[i]
+		facets = append(facets, &model.CreatedFacet{Days: days, After: vars[i].(time.Time), Count: count})
+	}
+
+	return facets, nil
+}
+
+func (r *ProductRepositoryImpl) categoryFacets(query *gorm.DB) ([]*model.CategoryFacet, error) {
+	var facets []*model.CategoryFacet
+
+	err := query.Session(&gorm.Session{}).
+		Joins("JOIN product_categories ON product_categories.product_id = products.id").
+		Joins("JOIN categories ON categories.id = product_categories.category_id AND categories.deleted_at IS NULL").
+		Select("categories.id AS category_id, categories.name AS name, COUNT(*) AS count").
+		Group("categories.id, categories.name").
+		Order("count DESC, categories.name").
+		Scan(&facets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+// attributeFacets counts the attributes of the variants of the products in
+// process, since they are stored as JSON documents.
+func (r *ProductRepositoryImpl) attributeFacets(query *gorm.DB) ([]*model.AttributeFacet, error) {
+	var variants []*model.ProductVariant
+
+	err := r.db.Model(&model.ProductVariant{}).
+		Select("product_id, attributes").
+		Where("product_id IN (?)", query.Session(&gorm.Session{}).Select("products.id")).
+		Find(&variants).Error
+	if err != nil {
+		return nil, err
+	}
+
+	products := make(map[model.AttributeFacet]map[uint]bool)
+	for _, v := range variants {
+		for name, value := range v.Attributes {
+			key := model.AttributeFacet{Name: name, Value: value}
+			if products[key] == nil {
+				products[key] = make(map[uint]bool)
+			}
+			products[key][v.ProductID] = true
+		}
+	}
+
+	facets := make([]*model.AttributeFacet, 0, len(products))
+	for key, ids := range products {
+		facets = append(facets, &model.AttributeFacet{Name: key.Name, Value: key.Value, Count: uint(len(ids))})
+	}
+
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Name != facets[j].Name {
+			return facets[i].Name < facets[j].Name
+		}
+		return facets[i].Value < facets[j].Value
+	})
+
+	return facets, nil
+}
+
 // GetByID retrieves a product by its ID.
 func (r *ProductRepositoryImpl) GetByID(productID uint) (*model.Product, error) {
 	var product *model.Product
 
 	err := r.db.Model(&model.Product{}).
+		Preload("Categories").
+		Preload("Variants").
 		Where("id = ?", productID).
 		First(&product).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, utils.ToUserError(http.StatusNotFound, "El producto solicitado no existe", err)
+			return nil, utils.NewError(utils.ErrProductNotFound, err)
 		}
-		return nil, utils.ToUserError(http.StatusInternalServerError, "No fue posible obtener detalle de producto debido a un error interno", err)
+		return nil, utils.NewError(utils.ErrProductReadFailed, err)
 	}
 
 	return product, nil
 }
 
-// Create adds a new product to the database.
+// GetByCode retrieves the product with a code. Imports look up every code
+// they read, so missing products are not logged as errors.
+func (r *ProductRepositoryImpl) GetByCode(code string) (*model.Product, error) {
+	var products []*model.Product
+
+	err := r.db.Model(&model.Product{}).
+		Preload("Categories").
+		Preload("Variants").
+		Where("code = ?", code).
+		Order("id").
+		Limit(1).
+		Find(&products).Error
+	if err != nil {
+		return nil, utils.NewError(utils.ErrProductReadFailed, err)
+	}
+
+	if len(products) == 0 {
+		return nil, utils.NewError(utils.ErrProductCodeNotFound, gorm.ErrRecordNotFound)
+	}
+
+	return products[0], nil
+}
+
+// Create adds a new product to the database together with its category assignments.
 func (r *ProductRepositoryImpl) Create(p *model.Product) error {
-	err := r.db.Create(p).Error
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	p.Version = 1
+	err := tx.Omit("Categories", "Variants").Create(p).Error
 	if err != nil {
-		return utils.ToUserError(http.StatusInternalServerError, "No fue posible registrar el producto debido a un error interno", err)
+		tx.Rollback()
+		return productWriteError(p, utils.ErrProductCreateFailed, err)
+	}
+
+	if err = r.replaceCategories(tx, p); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return productWriteError(p, utils.ErrProductCreateFailed, err)
 	}
 
 	return nil
 }
 
-// Update updates an existing product in the database.
+// Update updates an existing product in the database, moving it to its next
+// version, and replaces its category assignments. It fails if the product is
+// no longer at the version it was read at, so concurrent updates do not
+// overwrite each other. The reserved units are left untouched because carts
+// change them concurrently.
 func (r *ProductRepositoryImpl) Update(p *model.Product) error {
-	err := r.db.Save(p).Error
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	version := p.Version
+	p.Version++
+
+	result := tx.Model(p).
+		Where("version = ?", version).
+		Select("*").
+		Omit("CreatedAt", "Reserved", "Categories", "Variants").
+		Updates(p)
+	if result.Error != nil {
+		tx.Rollback()
+		p.Version = version
+		return productWriteError(p, utils.ErrProductUpdateFailed, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		p.Version = version
+		return utils.NewError(utils.ErrConcurrentModification, fmt.Errorf("product %d is no longer at version %d", p.ID, version))
+	}
+
+	if err := r.replaceCategories(tx, p); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return productWriteError(p, utils.ErrProductUpdateFailed, err)
+	}
+
+	return nil
+}
+
+// touchProduct moves a product to its next version when something that is
+// part of it, such as a variant or a price, changes.
+func touchProduct(tx *gorm.DB, productID uint) error {
+	err := tx.Model(&model.Product{}).Where("id = ?", productID).UpdateColumn("version", gorm.Expr("version + 1")).Error
 	if err != nil {
-		return utils.ToUserError(http.StatusInternalServerError, "No fue posible actualizar el producto debido a un error interno", err)
+		return utils.NewError(utils.ErrProductVersionFailed, err)
 	}
 
 	return nil
 }
 
-// Delete deletes a product from the database by its ID.
-func (r *ProductRepositoryImpl) Delete(id uint) error {
-	err := r.db.Delete(&model.Product{}, id).Error
+// productWriteError maps an error writing a product: a code another product
+// has is a conflict, and a reference to a row that does not exist, such as a
+// category, cannot be processed. Any other error is internal.
+func productWriteError(p *model.Product, code utils.ErrorCode, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return utils.NewError(utils.ErrProductCodeTaken, err, p.Code)
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return utils.NewError(utils.ErrMissingReference, err)
+	}
+
+	return utils.NewError(code, err)
+}
+
+// replaceCategories makes the categories of the product the only ones assigned to it.
+func (r *ProductRepositoryImpl) replaceCategories(tx *gorm.DB, p *model.Product) error {
+	err := tx.Exec("DELETE FROM product_categories WHERE product_id = ?", p.ID).Error
 	if err != nil {
-		return utils.ToUserError(http.StatusInternalServerError, "No fue posible eliminar el producto debido a un error interno", err)
+		return utils.NewError(utils.ErrProductCategoriesFailed, err)
+	}
+
+	for _, category := range p.Categories {
+		err = tx.Exec("INSERT INTO product_categories (product_id, category_id) VALUES (?, ?)", p.ID, category.ID).Error
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return utils.NewError(utils.ErrProductCategoriesNotFound, err)
+		}
+		if err != nil {
+			return utils.NewError(utils.ErrProductCategoriesFailed, err)
+		}
+	}
+
+	return nil
+}
+
+// ApplySchedule activates the drafts whose publication is due and archives
+// the active products whose withdrawal is due, clearing the timestamps that
+// fired. A draft whose withdrawal is due as well is left as it is.
+func (r *ProductRepositoryImpl) ApplySchedule(now time.Time) (int64, int64, error) {
+	published := r.db.Model(&model.Product{}).
+		Where("status = ? AND publish_at <= ?", model.ProductDraft, now).
+		Where("unpublish_at IS NULL OR unpublish_at > ?", now).
+		Updates(map[string]interface{}{"status": model.ProductActive, "publish_at": nil, "version": gorm.Expr("version + 1")})
+	if published.Error != nil {
+		return 0, 0, utils.NewError(utils.ErrProductPublishFailed, published.Error)
+	}
+
+	archived := r.db.Model(&model.Product{}).
+		Where("status = ? AND unpublish_at <= ?", model.ProductActive, now).
+		Updates(map[string]interface{}{"status": model.ProductArchived, "unpublish_at": nil, "version": gorm.Expr("version + 1")})
+	if archived.Error != nil {
+		return published.RowsAffected, 0, utils.NewError(utils.ErrProductArchiveFailed, archived.Error)
+	}
+
+	return published.RowsAffected, archived.RowsAffected, nil
+}
+
+// Delete soft deletes a product by its ID, if it is still at the version
+// given, when one is given. Open carts lose their items of the product and the
+// stock they reserved for it, and move to their next version, while
+// checked-out carts keep them, since they are part of an order.
+func (r *ProductRepositoryImpl) Delete(id uint, version *uint) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	var reservations []*model.StockReservation
+	err := tx.Where("product_id = ?", id).Find(&reservations).Error
+	if err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrProductDeleteFailed, err)
+	}
+
+	if err = deleteReservations(tx, reservations); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	cartsWithProduct := tx.Model(&model.ItemCart{}).Select("shopping_cart_id").Where("product_id = ?", id)
+	err = tx.Model(&model.ShoppingCart{}).
+		Where("id IN (?) AND checked_out_at IS NULL", cartsWithProduct).
+		UpdateColumn("version", gorm.Expr("version + 1")).Error
+	if err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrProductDeleteFailed, err)
+	}
+
+	openCarts := tx.Model(&model.ShoppingCart{}).Select("id").Where("checked_out_at IS NULL")
+	err = tx.Where("product_id = ? AND shopping_cart_id IN (?)", id, openCarts).Delete(&model.ItemCart{}).Error
+	if err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrProductDeleteFailed, err)
+	}
+
+	query := tx.Where("id = ?", id)
+	if version != nil {
+		query = query.Where("version = ?", *version)
+	}
+
+	result := query.Delete(&model.Product{})
+	if result.Error != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrProductDeleteFailed, result.Error)
+	}
+
+	if version != nil && result.RowsAffected == 0 {
+		tx.Rollback()
+		return utils.NewError(utils.ErrVersionConflict, fmt.Errorf("product %d is no longer at version %d", id, *version))
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrProductDeleteFailed, err)
 	}
 
 	return nil
@@ -2,10 +2,14 @@ package repository
 
 import (
 	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
 	"fmt"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 )
 
 // Test_GetList tests the GetList function of the ProductRepository.
@@ -14,7 +18,7 @@ func Test_GetList(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error opening database: %v", err)
 	}
-	err = db.AutoMigrate(&model.Product{})
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{})
 	if err != nil {
 		t.Fatalf("Error migrating table: %v", err)
 	}
@@ -28,7 +32,7 @@ func Test_GetList(t *testing.T) {
 		}
 	}
 
-	products, total, err := repo.GetList(1, 5, "", "id", true)
+	products, total, err := repo.GetList(&model.ProductFilter{Page: 1, PageSize: 5, Sort: []model.SortField{{Field: "id"}}})
 	if err != nil {
 		t.Fatalf("Error getting product list: %v", err)
 	}
@@ -48,7 +52,7 @@ func Test_Create(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error opening database: %v", err)
 	}
-	err = db.AutoMigrate(&model.Product{})
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{})
 	if err != nil {
 		t.Fatalf("Error migrating table: %v", err)
 	}
@@ -82,7 +86,7 @@ func Test_GetByID(t *testing.T) {
 		t.Fatalf("Error opening database: %v", err)
 	}
 
-	err = db.AutoMigrate(&model.Product{})
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{})
 	if err != nil {
 		t.Fatalf("Error migrating table: %v", err)
 	}
@@ -115,7 +119,7 @@ func Test_Update(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error opening database: %v", err)
 	}
-	err = db.AutoMigrate(&model.Product{})
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{})
 	if err != nil {
 		t.Fatalf("Error migrating table: %v", err)
 	}
@@ -148,13 +152,58 @@ func Test_Update(t *testing.T) {
 	}
 }
 
+// Test_UpdateVersionConflict tests that Update refuses to overwrite the changes
+// another request made since the product was read.
+func Test_UpdateVersionConflict(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{})
+	if err != nil {
+		t.Fatalf("Error migrating table: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+
+	product := &model.Product{Name: "Product"}
+	if err = repo.Create(product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+
+	first, _ := repo.GetByID(product.ID)
+	second, _ := repo.GetByID(product.ID)
+
+	first.Name = "First"
+	if err = repo.Update(first); err != nil {
+		t.Fatalf("Error updating product: %v", err)
+	}
+	if first.Version != 2 {
+		t.Errorf("Expected version 2 after the update, got %d", first.Version)
+	}
+
+	second.Name = "Second"
+	err = repo.Update(second)
+	if e, ok := err.(*utils.DBError); !ok || e.Status != http.StatusConflict || e.Code != utils.ErrConcurrentModification {
+		t.Fatalf("Expected a version conflict, got %v", err)
+	}
+	if second.Version != 1 {
+		t.Errorf("Expected the version of the stale copy to stay 1, got %d", second.Version)
+	}
+
+	stored, _ := repo.GetByID(product.ID)
+	if stored.Name != "First" {
+		t.Errorf("Expected the first update to be kept, got '%s'", stored.Name)
+	}
+}
+
 // Test_Delete tests the Delete function of the ProductRepository.
 func Test_Delete(t *testing.T) {
 	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
 	if err != nil {
 		t.Fatalf("error trying to open sqlite: %v", err)
 	}
-	err = db.AutoMigrate(&model.Product{})
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{}, &model.ShoppingCart{}, &model.ItemCart{}, &model.StockReservation{})
 	if err != nil {
 		t.Fatalf("error trying to migrate product model: %v", err)
 	}
@@ -167,7 +216,21 @@ func Test_Delete(t *testing.T) {
 		t.Fatalf("error trying to create product: %v", err)
 	}
 
-	err = repo.Delete(productToDelete.ID)
+	stale := productToDelete.Version
+	productToDelete.Name = "Renamed product"
+	if err = repo.Update(productToDelete); err != nil {
+		t.Fatalf("error trying to update product: %v", err)
+	}
+
+	err = repo.Delete(productToDelete.ID, &stale)
+	if e, ok := err.(*utils.DBError); !ok || e.Code != utils.ErrVersionConflict {
+		t.Fatalf("Expected a version conflict deleting at version %d, got %v", stale, err)
+	}
+	if _, err = repo.GetByID(productToDelete.ID); err != nil {
+		t.Fatalf("Expected the product to be kept after a version conflict, got %v", err)
+	}
+
+	err = repo.Delete(productToDelete.ID, &productToDelete.Version)
 	if err != nil {
 		t.Fatalf("error trying to remove product: %v", err)
 	}
@@ -177,3 +240,681 @@ func Test_Delete(t *testing.T) {
 		t.Fatalf("Expected the product to be deleted, but it still exists")
 	}
 }
+
+// Test_GetListByCategory tests the category filter of the GetList function of the ProductRepository.
+func Test_GetListByCategory(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Category{}, &model.Product{}, &model.ProductVariant{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+
+	clothes := &model.Category{Name: "Clothes"}
+	if err = db.Create(clothes).Error; err != nil {
+		t.Fatalf("Error inserting category: %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		product := &model.Product{Name: fmt.Sprintf("Product %d", i)}
+		if i%2 == 0 {
+			product.Categories = []*model.Category{clothes}
+		}
+		if err = repo.Create(product); err != nil {
+			t.Fatalf("Error creating product: %v", err)
+		}
+	}
+
+	products, total, err := repo.GetList(&model.ProductFilter{Page: 1, PageSize: 10, Sort: []model.SortField{{Field: "id"}}, CategoryIDs: []uint{clothes.ID}})
+	if err != nil {
+		t.Fatalf("Error getting product list: %v", err)
+	}
+
+	if total != 2 || len(products) != 2 {
+		t.Fatalf("Expected 2 products in the category, found %d", total)
+	}
+
+	if len(products[0].Categories) != 1 || products[0].Categories[0].Name != clothes.Name {
+		t.Errorf("Expected the product categories to be loaded, got %+v", products[0].Categories)
+	}
+}
+
+// Test_GetListSearchTerm tests that the GetList function of the ProductRepository searches names regardless of case.
+func Test_GetListSearchTerm(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{})
+	if err != nil {
+		t.Fatalf("Error migrating table: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+
+	for _, name := range []string{"Coffee Mug", "Travel MUG", "T-Shirt"} {
+		err = db.Create(&model.Product{Name: name}).Error
+		if err != nil {
+			t.Fatalf("Error inserting product: %v", err)
+		}
+	}
+
+	products, total, err := repo.GetList(&model.ProductFilter{Page: 1, PageSize: 10, SearchTerm: "mUg"})
+	if err != nil {
+		t.Fatalf("Error getting product list: %v", err)
+	}
+
+	if len(products) != 2 || total != 2 {
+		t.Errorf("Expected the 2 mugs, found %d of %d products", len(products), total)
+	}
+}
+
+// Test_GetListSearchManyMatches tests that searches in process keep the most
+// relevant matches when more products than they keep match.
+func Test_GetListSearchManyMatches(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{})
+	if err != nil {
+		t.Fatalf("Error migrating table: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+
+	products := make([]*model.Product, 0, maxInProcessMatches+100)
+	for i := 0; i < maxInProcessMatches+99; i++ {
+		products = append(products, &model.Product{Code: fmt.Sprintf("P-%05d", i), Name: "Taza de ceramica"})
+	}
+	products = append(products, &model.Product{Code: "TAZA", Name: "Taza"})
+	if err = db.CreateInBatches(products, 500).Error; err != nil {
+		t.Fatalf("Error inserting products: %v", err)
+	}
+
+	found, total, err := repo.GetList(&model.ProductFilter{Page: 1, PageSize: 10, SearchTerm: "taza"})
+	if err != nil {
+		t.Fatalf("Error getting product list: %v", err)
+	}
+
+	if total != maxInProcessMatches || len(found) != 10 || found[0].Code != "TAZA" {
+		t.Errorf("Expected TAZA first of %d products, found %d of %d", maxInProcessMatches, len(found), total)
+	}
+}
+
+// Test_GetListRelevance tests that the GetList function of the ProductRepository orders searched products by relevance.
+func Test_GetListRelevance(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{})
+	if err != nil {
+		t.Fatalf("Error migrating table: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+
+	products := []*model.Product{
+		{Code: "TE-01", Name: "Té verde"},
+		{Code: "CAF-02", Name: "Cafetera italiana"},
+		{Code: "MOL-03", Name: "Molino", Description: "Para moler café en grano"},
+		{Code: "CAF-01", Name: "CAFÉ DE OLLA"},
+	}
+	for _, v := range products {
+		if err = db.Create(v).Error; err != nil {
+			t.Fatalf("Error inserting product: %v", err)
+		}
+	}
+
+	found, total, err := repo.GetList(&model.ProductFilter{Page: 1, PageSize: 10, SearchTerm: "cafe"})
+	if err != nil {
+		t.Fatalf("Error getting product list: %v", err)
+	}
+
+	if total != 3 || len(found) != 3 {
+		t.Fatalf("Expected 3 products about coffee, found %d of %d", len(found), total)
+	}
+
+	expected := []string{"CAF-01", "CAF-02", "MOL-03"}
+	for i, v := range found {
+		if v.Code != expected[i] || v.Score <= 0 {
+			t.Errorf("Expected product %s at position %d with a score, got %s with %v", expected[i], i, v.Code, v.Score)
+		}
+	}
+
+	found, _, err = repo.GetList(&model.ProductFilter{Page: 1, PageSize: 10, SearchTerm: "cafw olla"})
+	if err != nil {
+		t.Fatalf("Error getting product list: %v", err)
+	}
+
+	if len(found) != 1 || found[0].Code != "CAF-01" {
+		t.Errorf("Expected the misspelled search to find CAF-01, got %d products", len(found))
+	}
+}
+
+// Test_GetFacets tests that the GetList and GetFacets functions of the ProductRepository filter by price and attributes, and count the facets of the products that pass the filter.
+func Test_GetFacets(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Category{}, &model.Product{}, &model.ProductVariant{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+
+	clothes := &model.Category{Name: "Clothes"}
+	if err = db.Create(clothes).Error; err != nil {
+		t.Fatalf("Error inserting category: %v", err)
+	}
+
+	prices := []int64{5000, 19999, 30000, 250000}
+	for i, price := range prices {
+		product := &model.Product{Name: fmt.Sprintf("Product %d", i), Price: model.NewMoney(price, model.DefaultCurrency)}
+		if i < 3 {
+			product.Categories = []*model.Category{clothes}
+		}
+		if err = repo.Create(product); err != nil {
+			t.Fatalf("Error creating product: %v", err)
+		}
+
+		color := "red"
+		if i%2 == 1 {
+			color = "blue_100%"
+		}
+		variant := &model.ProductVariant{ProductID: product.ID, SKU: fmt.Sprintf("SKU-%d", i), Attributes: model.Attributes{"color": color, "size": "M"}}
+		if err = db.Create(variant).Error; err != nil {
+			t.Fatalf("Error inserting variant: %v", err)
+		}
+	}
+
+	maxPrice := model.NewMoney(30000, model.DefaultCurrency)
+	filter := &model.ProductFilter{Page: 1, PageSize: 10, Sort: []model.SortField{{Field: "id"}}, MaxPrice: &maxPrice, Attributes: map[string]string{"color": "red", "size": "M"}}
+
+	products, total, err := repo.GetList(filter)
+	if err != nil {
+		t.Fatalf("Error getting product list: %v", err)
+	}
+
+	if total != 2 || len(products) != 2 || products[0].Name != "Product 0" || products[1].Name != "Product 2" {
+		t.Fatalf("Expected the red products up to 300.00, found %d", total)
+	}
+
+	facets, err := repo.GetFacets(&model.ProductFilter{Attributes: map[string]string{"color": "blue_100%"}})
+	if err != nil {
+		t.Fatalf("Error getting facets: %v", err)
+	}
+
+	expectedPrices := []uint{0, 1, 0, 0, 1}
+	for i, v := range facets.Prices {
+		if v.Count != expectedPrices[i] {
+			t.Errorf("Expected %d products from %s, got %d", expectedPrices[i], v.Min, v.Count)
+		}
+	}
+
+	if len(facets.Categories) != 1 || facets.Categories[0].Name != "Clothes" || facets.Categories[0].Count != 1 {
+		t.Errorf("Expected 1 blue product in Clothes, got %+v", facets.Categories)
+	}
+
+	if len(facets.Created) == 0 || facets.Created[0].Count != 2 {
+		t.Errorf("Expected the 2 blue products to be created in the last days, got %+v", facets.Created)
+	}
+
+	if len(facets.Attributes) != 2 || facets.Attributes[0].Value != "blue_100%" || facets.Attributes[1].Name != "size" || facets.Attributes[1].Count != 2 {
+		t.Errorf("Expected the attributes of the blue products, got %+v", facets.Attributes)
+	}
+}
+
+// Test_GetListByCursor tests that the GetListByCursor function of the ProductRepository pages forwards and backwards through products with equal prices without skipping or repeating any.
+func Test_GetListByCursor(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{})
+	if err != nil {
+		t.Fatalf("Error migrating table: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+
+	for i := 0; i < 7; i++ {
+		product := &model.Product{Code: fmt.Sprintf("P-%d", i), Price: model.NewMoney(int64(i/2)*1000, model.DefaultCurrency)}
+		if err = db.Create(product).Error; err != nil {
+			t.Fatalf("Error inserting product: %v", err)
+		}
+	}
+
+	// Products by price descending, ties by ID.
+	expected := []string{"P-6", "P-4", "P-5", "P-2", "P-3", "P-0", "P-1"}
+	filter := &model.ProductFilter{PageSize: 3, Sort: []model.SortField{{Field: "price", Descending: true}}}
+
+	codes := make([]string, 0)
+	pages := make([]*model.ProductList, 0)
+	for {
+		list, err := repo.GetListByCursor(filter)
+		if err != nil {
+			t.Fatalf("Error getting product list: %v", err)
+		}
+
+		if list.Total != 7 {
+			t.Errorf("Expected 7 total products, found %d", list.Total)
+		}
+
+		for _, v := range list.Products {
+			codes = append(codes, v.Code)
+		}
+		pages = append(pages, list)
+
+		if list.NextCursor == nil {
+			break
+		}
+
+		// Cursors travel encoded, as in the responses.
+		if filter.Cursor, err = model.DecodeProductCursor(list.NextCursor.Encode()); err != nil {
+			t.Fatalf("Error decoding cursor: %v", err)
+		}
+	}
+
+	if strings.Join(codes, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected products %v, got %v", expected, codes)
+	}
+
+	if len(pages) != 3 || pages[0].PrevCursor != nil || pages[2].PrevCursor == nil {
+		t.Fatalf("Expected 3 pages with a previous page from the second on")
+	}
+
+	filter.Cursor, err = model.DecodeProductCursor(pages[2].PrevCursor.Encode())
+	if err != nil {
+		t.Fatalf("Error decoding cursor: %v", err)
+	}
+
+	list, err := repo.GetListByCursor(filter)
+	if err != nil {
+		t.Fatalf("Error getting previous page: %v", err)
+	}
+
+	if len(list.Products) != 3 || list.Products[0].Code != "P-2" || list.NextCursor == nil || list.PrevCursor == nil {
+		t.Errorf("Expected the previous page to be the second page, with cursors to both sides")
+	}
+
+	filter.Sort[0].Descending = false
+	if _, err = repo.GetListByCursor(filter); err == nil {
+		t.Errorf("Expected an error using a cursor with another order")
+	}
+}
+
+// Test_GetListSort tests that the GetList function of the ProductRepository sorts by several fields, breaks ties by ID and rejects fields that are not sortable.
+func Test_GetListSort(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{})
+	if err != nil {
+		t.Fatalf("Error migrating table: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+
+	products := []*model.Product{
+		{Code: "A", Name: "Taza", Price: model.NewMoney(5000, model.DefaultCurrency)},
+		{Code: "B", Name: "Plato", Price: model.NewMoney(9000, model.DefaultCurrency)},
+		{Code: "C", Name: "Vaso", Price: model.NewMoney(5000, model.DefaultCurrency)},
+		{Code: "D", Name: "Plato", Price: model.NewMoney(5000, model.DefaultCurrency)},
+		{Code: "E", Name: "Plato", Price: model.NewMoney(5000, model.DefaultCurrency)},
+	}
+	for _, v := range products {
+		if err = db.Create(v).Error; err != nil {
+			t.Fatalf("Error inserting product: %v", err)
+		}
+	}
+
+	sort, err := model.ParseSort("-price, name")
+	if err != nil {
+		t.Fatalf("Error parsing sort: %v", err)
+	}
+
+	found, _, err := repo.GetList(&model.ProductFilter{Page: 1, PageSize: 10, Sort: sort})
+	if err != nil {
+		t.Fatalf("Error getting product list: %v", err)
+	}
+
+	codes := make([]string, 0, len(found))
+	for _, v := range found {
+		codes = append(codes, v.Code)
+	}
+
+	if strings.Join(codes, ",") != "B,D,E,A,C" {
+		t.Errorf("Expected products B,D,E,A,C, got %v", codes)
+	}
+
+	for _, spec := range []string{"price; DROP TABLE products", "price,price", "stock"} {
+		sort, err = model.ParseSort(spec)
+		if err != nil {
+			t.Fatalf("Error parsing sort %q: %v", spec, err)
+		}
+
+		_, _, err = repo.GetList(&model.ProductFilter{Page: 1, PageSize: 10, Sort: sort})
+		if e, ok := err.(*utils.DBError); !ok || e.Status != http.StatusBadRequest || !strings.Contains(e.Error(), "createdAt") {
+			t.Errorf("Expected a bad request listing the sortable fields for %q, got %v", spec, err)
+		}
+	}
+
+	if _, err = model.ParseSort("price,,name"); err == nil {
+		t.Errorf("Expected an error parsing a sort with an empty field")
+	}
+}
+
+// Test_GetByCode tests the GetByCode function of the ProductRepository.
+func Test_GetByCode(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Category{}, &model.Product{}, &model.ProductVariant{})
+	if err != nil {
+		t.Fatalf("Error migrating table: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+
+	if err = repo.Create(&model.Product{Code: "CAF-01", Name: "Cafe"}); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+
+	product, err := repo.GetByCode("CAF-01")
+	if err != nil {
+		t.Fatalf("Error getting product by code: %v", err)
+	}
+
+	if product.Name != "Cafe" {
+		t.Errorf("Expected product Cafe, got %s", product.Name)
+	}
+
+	_, err = repo.GetByCode("CAF-02")
+	if e, ok := err.(*utils.DBError); !ok || e.Status != http.StatusNotFound {
+		t.Errorf("Expected a not found error for a missing code, got %v", err)
+	}
+}
+
+// Test_ForEachBatch tests that the ForEachBatch function of the ProductRepository reads every product that passes the filter once, in order and in batches.
+func Test_ForEachBatch(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Category{}, &model.Product{}, &model.ProductVariant{})
+	if err != nil {
+		t.Fatalf("Error migrating table: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+
+	for i := 0; i < 12; i++ {
+		product := &model.Product{Code: fmt.Sprintf("P-%02d", i), Price: model.NewMoney(int64(i%3)*1000, model.DefaultCurrency)}
+		if err = db.Create(product).Error; err != nil {
+			t.Fatalf("Error inserting product: %v", err)
+		}
+	}
+
+	maxPrice := model.NewMoney(1000, model.DefaultCurrency)
+	filter := &model.ProductFilter{MaxPrice: &maxPrice, Sort: []model.SortField{{Field: "price", Descending: true}}}
+
+	batches := 0
+	codes := make([]string, 0)
+	err = repo.ForEachBatch(filter, 3, func(products []*model.Product) error {
+		batches++
+		for _, v := range products {
+			codes = append(codes, v.Code)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error reading products in batches: %v", err)
+	}
+
+	expected := "P-01,P-04,P-07,P-10,P-00,P-03,P-06,P-09"
+	if strings.Join(codes, ",") != expected || batches != 3 {
+		t.Errorf("Expected products %s in 3 batches, got %v in %d", expected, codes, batches)
+	}
+}
+
+// Test_CreateDuplicateCode tests that the Create and Update functions of the ProductRepository reject codes of products that are not deleted and unknown categories.
+func Test_CreateDuplicateCode(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:?_foreign_keys=1"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Category{}, &model.Product{}, &model.ProductVariant{}, &model.ShoppingCart{}, &model.ItemCart{}, &model.StockReservation{})
+	if err != nil {
+		t.Fatalf("Error migrating table: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+
+	first := &model.Product{Code: "CAF-01", Name: "Cafe"}
+	if err = repo.Create(first); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+
+	err = repo.Create(&model.Product{Code: "CAF-01", Name: "Cafe de olla"})
+	if e, ok := err.(*utils.DBError); !ok || e.Status != http.StatusConflict || e.Code != utils.ErrProductCodeTaken {
+		t.Errorf("Expected a conflict for a duplicated code, got %v", err)
+	}
+
+	second := &model.Product{Code: "CAF-02", Name: "Cafe molido"}
+	if err = repo.Create(second); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+
+	second.Code = "CAF-01"
+	err = repo.Update(second)
+	if e, ok := err.(*utils.DBError); !ok || e.Status != http.StatusConflict {
+		t.Errorf("Expected a conflict updating to a duplicated code, got %v", err)
+	}
+
+	if err = repo.Delete(first.ID, nil); err != nil {
+		t.Fatalf("Error deleting product: %v", err)
+	}
+
+	if err = repo.Create(&model.Product{Code: "CAF-01", Name: "Cafe de olla"}); err != nil {
+		t.Errorf("Expected the code of a deleted product to be free, got %v", err)
+	}
+
+	err = repo.Create(&model.Product{Code: "CAF-03", Categories: []*model.Category{{Model: gorm.Model{ID: 99}}}})
+	if e, ok := err.(*utils.DBError); !ok || e.Status != http.StatusUnprocessableEntity || e.Code != utils.ErrProductCategoriesNotFound {
+		t.Errorf("Expected an unprocessable entity for an unknown category, got %v", err)
+	}
+}
+
+// Test_ApplySchedule tests that scheduled products are published and archived when due, and listed by status.
+func Test_ApplySchedule(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{})
+	if err != nil {
+		t.Fatalf("Error migrating table: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	products := map[string]*model.Product{
+		"due draft":      {Name: "Due draft", Status: model.ProductDraft, PublishAt: &past},
+		"later draft":    {Name: "Later draft", Status: model.ProductDraft, PublishAt: &future},
+		"expired draft":  {Name: "Expired draft", Status: model.ProductDraft, PublishAt: &past, UnpublishAt: &past},
+		"due active":     {Name: "Due active", Status: model.ProductActive, UnpublishAt: &past},
+		"later active":   {Name: "Later active", Status: model.ProductActive, UnpublishAt: &future},
+		"archived":       {Name: "Archived", Status: model.ProductArchived},
+		"without status": {Name: "Without status"},
+	}
+	for name, p := range products {
+		if err = db.Create(p).Error; err != nil {
+			t.Fatalf("Error inserting product %s: %v", name, err)
+		}
+	}
+
+	published, archived, err := repo.ApplySchedule(now)
+	if err != nil {
+		t.Fatalf("Error applying schedule: %v", err)
+	}
+
+	if published != 1 || archived != 1 {
+		t.Errorf("Expected 1 product published and 1 archived, found %d and %d", published, archived)
+	}
+
+	expected := map[string]model.ProductStatus{
+		"due draft": model.ProductActive, "later draft": model.ProductDraft, "expired draft": model.ProductDraft,
+		"due active": model.ProductArchived, "later active": model.ProductActive, "archived": model.ProductArchived,
+		"without status": model.ProductActive,
+	}
+	for name, status := range expected {
+		product, err := repo.GetByID(products[name].ID)
+		if err != nil {
+			t.Fatalf("Error getting product %s: %v", name, err)
+		}
+
+		if product.Status != status {
+			t.Errorf("Expected %s to be %s, found %s", name, status, product.Status)
+		}
+	}
+
+	due, err := repo.GetByID(products["due draft"].ID)
+	if err != nil {
+		t.Fatalf("Error getting product: %v", err)
+	}
+
+	if due.PublishAt != nil {
+		t.Errorf("Expected the publication of a published product to be cleared, found %s", due.PublishAt)
+	}
+
+	filter := &model.ProductFilter{Page: 1, PageSize: 10, Sort: []model.SortField{{Field: "id"}}, Statuses: []model.ProductStatus{model.ProductActive}}
+	_, total, err := repo.GetList(filter)
+	if err != nil {
+		t.Fatalf("Error getting product list: %v", err)
+	}
+
+	if total != 3 {
+		t.Errorf("Expected 3 active products, found %d", total)
+	}
+
+	filter.Statuses = nil
+	if _, total, err = repo.GetList(filter); err != nil || total != 7 {
+		t.Errorf("Expected 7 products in any status, found %d (%v)", total, err)
+	}
+}
+
+// Test_RestoreAndPurge tests that deleted products leave open carts, can be listed and restored, and are purged for good.
+func Test_RestoreAndPurge(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Category{}, &model.Product{}, &model.ProductVariant{}, &model.ProductPrice{}, &model.Promotion{},
+		&model.ShoppingCart{}, &model.CartCoupon{}, &model.ItemCart{}, &model.StockReservation{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+	cartRepo := NewShoppingCartRepository(db, time.Minute)
+
+	product := &model.Product{Code: "CAF-01", Name: "Cafe", Stock: 5}
+	if err = repo.Create(product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+
+	if err = db.Create(&model.ProductPrice{ProductID: product.ID, Currency: "USD", Amount: 100}).Error; err != nil {
+		t.Fatalf("Error inserting price: %v", err)
+	}
+
+	open, closed := &model.ShoppingCart{}, &model.ShoppingCart{}
+	for _, cart := range []*model.ShoppingCart{open, closed} {
+		cart.Items = []*model.ItemCart{{ProductID: product.ID, Count: 2}}
+		if err = cartRepo.Create(cart); err != nil {
+			t.Fatalf("Error creating shopping cart: %v", err)
+		}
+	}
+
+	if err = db.Model(closed).Update("checked_out_at", time.Now()).Error; err != nil {
+		t.Fatalf("Error checking out shopping cart: %v", err)
+	}
+
+	if err = repo.Delete(product.ID, nil); err != nil {
+		t.Fatalf("Error deleting product: %v", err)
+	}
+
+	var items, reservations int64
+	db.Model(&model.ItemCart{}).Where("product_id = ?", product.ID).Count(&items)
+	db.Model(&model.StockReservation{}).Where("product_id = ?", product.ID).Count(&reservations)
+	if items != 1 || reservations != 0 {
+		t.Errorf("Expected only the checked-out cart to keep the product and no reservations, found %d items and %d reservations", items, reservations)
+	}
+
+	cart, err := cartRepo.GetByID(closed.ID)
+	if err != nil {
+		t.Fatalf("Error getting shopping cart: %v", err)
+	}
+
+	if len(cart.Items) != 1 || cart.Items[0].Product == nil || !cart.Items[0].Product.IsDeleted() {
+		t.Errorf("Expected the checked-out cart to load the deleted product")
+	}
+
+	deleted, total, err := repo.GetDeletedList(1, 10)
+	if err != nil {
+		t.Fatalf("Error getting deleted products: %v", err)
+	}
+
+	if total != 1 || len(deleted) != 1 || deleted[0].ID != product.ID {
+		t.Fatalf("Expected the deleted product to be listed, found %d", total)
+	}
+
+	if err = repo.Restore(product.ID); err != nil {
+		t.Fatalf("Error restoring product: %v", err)
+	}
+
+	restored, err := repo.GetByID(product.ID)
+	if err != nil {
+		t.Fatalf("Expected the product to be restored, got %v", err)
+	}
+
+	if restored.Reserved != 0 {
+		t.Errorf("Expected no reserved units after the carts released them, found %d", restored.Reserved)
+	}
+
+	err = repo.Restore(product.ID)
+	if e, ok := err.(*utils.DBError); !ok || e.Status != http.StatusConflict {
+		t.Errorf("Expected a conflict restoring a product that is not deleted, got %v", err)
+	}
+
+	if err = repo.Delete(product.ID, nil); err != nil {
+		t.Fatalf("Error deleting product: %v", err)
+	}
+
+	if purged, err := repo.Purge(time.Now().Add(-time.Hour)); err != nil || purged != 0 {
+		t.Errorf("Expected no product deleted an hour ago to be purged, found %d (%v)", purged, err)
+	}
+
+	if purged, err := repo.Purge(time.Now().Add(time.Second)); err != nil || purged != 1 {
+		t.Errorf("Expected the deleted product to be purged, found %d (%v)", purged, err)
+	}
+
+	var products, prices int64
+	db.Unscoped().Model(&model.Product{}).Count(&products)
+	db.Unscoped().Model(&model.ProductPrice{}).Count(&prices)
+	db.Unscoped().Model(&model.ItemCart{}).Where("product_id = ?", product.ID).Count(&items)
+	if products != 0 || prices != 0 || items != 0 {
+		t.Errorf("Expected the product and its rows to be purged, found %d products, %d prices and %d items", products, prices, items)
+	}
+}
@@ -0,0 +1,130 @@
+// Package repository provides implementations for restoring and purging deleted product data.
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+// purgeBatchSize is the number of products a purge deletes per transaction.
+const purgeBatchSize = 500
+
+// GetDeletedList retrieves a page of the deleted products, the most recently
+// deleted first.
+func (r *ProductRepositoryImpl) GetDeletedList(page, pageSize int) ([]*model.Product, uint, error) {
+	var products []*model.Product
+	var total int64
+
+	query := r.db.Unscoped().Model(&model.Product{}).Where("products.deleted_at IS NOT NULL")
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, utils.NewError(utils.ErrDeletedProductCountFailed, err)
+	}
+
+	err := query.Preload("Categories").
+		Preload("Variants").
+		Order("products.deleted_at DESC, products.id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&products).Error
+	if err != nil {
+		return nil, 0, utils.NewError(utils.ErrDeletedProductListFailed, err)
+	}
+
+	return products, uint(total), nil
+}
+
+// Restore undoes the deletion of a product. The items carts lost when it was
+// deleted are not restored, and it fails if another product took its code.
+func (r *ProductRepositoryImpl) Restore(productID uint) error {
+	var product model.Product
+
+	err := r.db.Unscoped().Where("id = ?", productID).First(&product).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.NewError(utils.ErrProductNotFound, err)
+		}
+		return utils.NewError(utils.ErrProductRestoreFailed, err)
+	}
+
+	if !product.DeletedAt.Valid {
+		return utils.NewError(utils.ErrProductNotDeleted, fmt.Errorf("product %d is not deleted", productID))
+	}
+
+	err = r.db.Unscoped().Model(&model.Product{}).
+		Where("id = ? AND deleted_at IS NOT NULL", productID).
+		Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")}).Error
+	if err != nil {
+		return productWriteError(&product, utils.ErrProductRestoreFailed, err)
+	}
+
+	return nil
+}
+
+// Purge permanently deletes the products deleted before a moment, with their
+// variants, prices, category and promotion assignments and the items of the
+// carts that held them, and reports how many products were purged. Orders
+// keep their copies of the products.
+func (r *ProductRepositoryImpl) Purge(deletedBefore time.Time) (int64, error) {
+	var purged int64
+
+	for {
+		var ids []uint
+		err := r.db.Unscoped().Model(&model.Product{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Order("id").
+			Limit(purgeBatchSize).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return purged, utils.NewError(utils.ErrPurgeListFailed, err)
+		}
+
+		if len(ids) == 0 {
+			return purged, nil
+		}
+
+		if err = r.purgeProducts(ids); err != nil {
+			return purged, err
+		}
+		purged += int64(len(ids))
+	}
+}
+
+// purgeProducts permanently deletes products and every row that refers to
+// them in a transaction.
+func (r *ProductRepositoryImpl) purgeProducts(ids []uint) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	dependents := []interface{}{&model.StockReservation{}, &model.ItemCart{}, &model.ProductPrice{}, &model.ProductVariant{}}
+	for _, v := range dependents {
+		if err := tx.Unscoped().Where("product_id IN ?", ids).Delete(v).Error; err != nil {
+			tx.Rollback()
+			return utils.NewError(utils.ErrProductPurgeFailed, err)
+		}
+	}
+
+	for _, table := range []string{"product_categories", "promotion_products"} {
+		if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE product_id IN ?", table), ids).Error; err != nil {
+			tx.Rollback()
+			return utils.NewError(utils.ErrProductPurgeFailed, err)
+		}
+	}
+
+	if err := tx.Unscoped().Where("id IN ?", ids).Delete(&model.Product{}).Error; err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrProductPurgeFailed, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrProductPurgeFailed, err)
+	}
+
+	return nil
+}
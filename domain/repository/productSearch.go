@@ -0,0 +1,199 @@
+// Package repository provides implementations for searching product data in the database.
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/search"
+	"codifin-challenge/domain/utils"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"strings"
+)
+
+// ProductSearcher narrows a query of products down to the ones that match the
+// terms of a search, and scores their relevance as the search package does.
+type ProductSearcher interface {
+	// Match returns the query restricted to the matching products and the
+	// expression of their score.
+	Match(query *gorm.DB, terms []string) (*gorm.DB, clause.Expr, error)
+}
+
+// NewProductSearcher creates the searcher of the dialect of the database:
+// trigram search in postgres, word patterns in mysql, and scoring in process
+// everywhere else.
+func NewProductSearcher(db *gorm.DB) ProductSearcher {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return &TrigramSearcher{}
+	case "mysql":
+		return &PatternSearcher{}
+	}
+
+	return &InProcessSearcher{}
+}
+
+// searchFields lists the columns products are searched by and their weights.
+var searchFields = []struct {
+	column string
+	weight float64
+}{
+	{"code", search.CodeWeight},
+	{"name", search.NameWeight},
+	{"description", search.DescriptionWeight},
+}
+
+// TrigramSearcher scores products in postgres, folding them with the
+// search_fold function and comparing them with the pg_trgm similarity.
+type TrigramSearcher struct{}
+
+// Match restricts the query to the products every term matches.
+func (s *TrigramSearcher) Match(query *gorm.DB, terms []string) (*gorm.DB, clause.Expr, error) {
+	termScores := make([]string, 0, len(terms))
+	vars := make([]interface{}, 0)
+
+	for _, term := range terms {
+		fieldScores := make([]string, 0, len(searchFields))
+		for _, field := range searchFields {
+			folded := fmt.Sprintf("search_fold(COALESCE(products.%s, ''))", field.column)
+			fieldScores = append(fieldScores, fmt.Sprintf("%v * CASE"+
+				" WHEN ' ' || %s || ' ' LIKE ? THEN %v"+
+				" WHEN ' ' || %s || ' ' LIKE ? THEN %v"+
+				" ELSE %v * (SELECT COALESCE(MAX(similarity), 0) FROM"+
+				" (SELECT similarity(?, word) AS similarity FROM unnest(string_to_array(%s, ' ')) AS word) AS words"+
+				" WHERE similarity >= ?) END",
+				field.weight, folded, search.ExactScore, folded, search.PrefixScore, search.FuzzyFactor, folded))
+			vars = append(vars, "% "+term+" %", "% "+term+"%", term, search.FuzzyThreshold)
+		}
+		termScores = append(termScores, "GREATEST("+strings.Join(fieldScores, ", ")+")")
+	}
+
+	// Every term must match, so the lowest score of a term must be positive.
+	matched := clause.Expr{SQL: "LEAST(" + strings.Join(termScores, ", ") + ") > 0", Vars: vars}
+	score := clause.Expr{
+		SQL:  fmt.Sprintf("ROUND(CAST((%s) / %d AS numeric), 4)", strings.Join(termScores, " + "), len(terms)),
+		Vars: vars,
+	}
+
+	return query.Where(matched), score, nil
+}
+
+// PatternSearcher scores products in mysql by the words of their fields,
+// folded with REGEXP_REPLACE and compared under an accent insensitive
+// collation. MySQL has no trigram similarity, so terms only match the words
+// they are or start, without the fuzzy matches of misspellings.
+type PatternSearcher struct{}
+
+// Match restricts the query to the products every term matches.
+func (s *PatternSearcher) Match(query *gorm.DB, terms []string) (*gorm.DB, clause.Expr, error) {
+	termScores := make([]string, 0, len(terms))
+	vars := make([]interface{}, 0)
+
+	for _, term := range terms {
+		fieldScores := make([]string, 0, len(searchFields))
+		for _, field := range searchFields {
+			folded := fmt.Sprintf("CONCAT(' ', LOWER(REGEXP_REPLACE(COALESCE(products.%s, ''), '[^[:alnum:]]+', ' ')), ' ')", field.column)
+			fieldScores = append(fieldScores, fmt.Sprintf("%v * CASE"+
+				" WHEN %s LIKE ? COLLATE utf8mb4_0900_ai_ci THEN %v"+
+				" WHEN %s LIKE ? COLLATE utf8mb4_0900_ai_ci THEN %v"+
+				" ELSE 0 END",
+				field.weight, folded, search.ExactScore, folded, search.PrefixScore))
+			vars = append(vars, "% "+term+" %", "% "+term+"%")
+		}
+		termScores = append(termScores, "GREATEST("+strings.Join(fieldScores, ", ")+")")
+	}
+
+	// Every term must match, so the score of every term must be positive.
+	// LEAST takes at least two arguments in mysql, so they are compared one by one.
+	matched := clause.Expr{SQL: strings.Join(termScores, " > 0 AND ") + " > 0", Vars: vars}
+	score := clause.Expr{
+		SQL:  fmt.Sprintf("ROUND((%s) / %d, 4)", strings.Join(termScores, " + "), len(terms)),
+		Vars: vars,
+	}
+
+	return query.Where(matched), score, nil
+}
+
+// maxInProcessMatches is the number of best matches the InProcessSearcher
+// keeps, so their IDs and scores fit in the variables of a statement.
+const maxInProcessMatches = 2000
+
+// inProcessBatchSize is the number of candidates the InProcessSearcher reads
+// at a time.
+const inProcessBatchSize = 500
+
+// InProcessSearcher scores the products of the query in process, reading them
+// in batches and keeping only the best matches. It suits SQLite, which has no
+// trigram support and is meant for tests and development.
+type InProcessSearcher struct{}
+
+// inProcessMatch is the score of a product that matches a search.
+type inProcessMatch struct {
+	id    uint
+	score float64
+}
+
+// Match restricts the query to the products every term matches, up to the
+// maxInProcessMatches most relevant ones.
+func (s *InProcessSearcher) Match(query *gorm.DB, terms []string) (*gorm.DB, clause.Expr, error) {
+	var candidates []*model.Product
+	matches := make([]inProcessMatch, 0)
+
+	err := query.Session(&gorm.Session{}).
+		Select("products.id, products.code, products.name, products.description").
+		FindInBatches(&candidates, inProcessBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, v := range candidates {
+				score := search.Score(terms,
+					search.Field{Text: v.Code, Weight: search.CodeWeight},
+					search.Field{Text: v.Name, Weight: search.NameWeight},
+					search.Field{Text: v.Description, Weight: search.DescriptionWeight})
+				if score > 0 {
+					matches = append(matches, inProcessMatch{id: v.ID, score: score})
+				}
+			}
+
+			if len(matches) > 2*maxInProcessMatches {
+				matches = bestMatches(matches)
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return nil, clause.Expr{}, utils.NewError(utils.ErrProductSearchFailed, err)
+	}
+
+	matches = bestMatches(matches)
+	if len(matches) == 0 {
+		return query.Where("1 = 0"), clause.Expr{SQL: "0"}, nil
+	}
+
+	ids := make([]uint, 0, len(matches))
+	cases := make([]string, 0, len(matches))
+	vars := make([]interface{}, 0, 2*len(matches))
+	for _, v := range matches {
+		ids = append(ids, v.id)
+		cases = append(cases, "WHEN ? THEN ?")
+		vars = append(vars, v.id, v.score)
+	}
+
+	score := clause.Expr{SQL: "CASE products.id " + strings.Join(cases, " ") + " ELSE 0 END", Vars: vars}
+
+	return query.Where("products.id IN ?", ids), score, nil
+}
+
+// bestMatches returns the maxInProcessMatches matches with the highest score,
+// the first products first among equal scores.
+func bestMatches(matches []inProcessMatch) []inProcessMatch {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].id < matches[j].id
+	})
+
+	if len(matches) > maxInProcessMatches {
+		matches = matches[:maxInProcessMatches]
+	}
+
+	return matches
+}
@@ -0,0 +1,138 @@
+// Package repository provides implementations for sorting product data in the database.
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"encoding/json"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"time"
+)
+
+// sortField is a field products can be sorted by: its column and how to read
+// its value from a product and decode it from a cursor.
+type sortField struct {
+	column string
+	value  func(p *model.Product) interface{}
+	decode func(v interface{}) (interface{}, bool)
+}
+
+// sortFields maps the fields of model.ProductSortFields to their columns. Only
+// these columns are ever written into an ORDER BY.
+var sortFields = map[string]sortField{
+	"id":        {"products.id", func(p *model.Product) interface{} { return p.ID }, decodeCursorInt},
+	"code":      {"products.code", func(p *model.Product) interface{} { return p.Code }, decodeCursorString},
+	"name":      {"products.name", func(p *model.Product) interface{} { return p.Name }, decodeCursorString},
+	"price":     {"products.price_amount", func(p *model.Product) interface{} { return p.Price.Amount }, decodeCursorInt},
+	"createdAt": {"products.created_at", func(p *model.Product) interface{} { return p.CreatedAt }, decodeCursorTime},
+}
+
+// sortKey is a key products are ordered by. expr is its expression in
+// conditions and column the one it is ordered by, which may be an alias.
+type sortKey struct {
+	name   string
+	column string
+	expr   clause.Expr
+	desc   bool
+	field  sortField
+}
+
+// sortKeys returns the keys products are ordered by: the fields of the sort,
+// or the relevance of a search without one, with the ID as the last key so
+// every product has a distinct position.
+func sortKeys(sort []model.SortField, searching bool, score clause.Expr) ([]sortKey, error) {
+	keys := make([]sortKey, 0, len(sort)+1)
+	seen := make(map[string]bool)
+
+	for _, v := range sort {
+		field, ok := sortFields[v.Field]
+		if !ok || seen[v.Field] {
+			return nil, utils.NewError(utils.ErrUnsupportedSortField, fmt.Errorf("invalid or repeated sort field %s", v.Field),
+				v.Field, strings.Join(model.ProductSortFields, ", "))
+		}
+		seen[v.Field] = true
+
+		keys = append(keys, sortKey{name: v.Field, column: field.column, expr: clause.Expr{SQL: field.column}, desc: v.Descending, field: field})
+	}
+
+	if len(keys) == 0 && searching {
+		field := sortField{
+			value:  func(p *model.Product) interface{} { return p.Score },
+			decode: decodeCursorFloat,
+		}
+		keys = append(keys, sortKey{name: "score", column: "score", expr: score, desc: true, field: field})
+	}
+
+	if !seen["id"] {
+		field := sortFields["id"]
+		keys = append(keys, sortKey{name: "id", column: field.column, expr: clause.Expr{SQL: field.column}, field: field})
+	}
+
+	return keys, nil
+}
+
+// sortSignature identifies an ordering, such as price:desc,id:asc.
+func sortSignature(keys []sortKey) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		direction := "asc"
+		if key.desc {
+			direction = "desc"
+		}
+		parts = append(parts, key.name+":"+direction)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// orderBy orders the query by the keys, or in reverse to read the products
+// before a position.
+func orderBy(query *gorm.DB, keys []sortKey, reverse bool) *gorm.DB {
+	for _, key := range keys {
+		direction := "ASC"
+		if key.desc != reverse {
+			direction = "DESC"
+		}
+		query = query.Order(key.column + " " + direction)
+	}
+
+	return query
+}
+
+func decodeCursorInt(v interface{}) (interface{}, bool) {
+	number, ok := v.(json.Number)
+	if !ok {
+		return nil, false
+	}
+
+	value, err := number.Int64()
+	return value, err == nil
+}
+
+func decodeCursorFloat(v interface{}) (interface{}, bool) {
+	number, ok := v.(json.Number)
+	if !ok {
+		return nil, false
+	}
+
+	value, err := number.Float64()
+	return value, err == nil
+}
+
+func decodeCursorString(v interface{}) (interface{}, bool) {
+	value, ok := v.(string)
+	return value, ok
+}
+
+func decodeCursorTime(v interface{}) (interface{}, bool) {
+	text, ok := v.(string)
+	if !ok {
+		return nil, false
+	}
+
+	value, err := time.Parse(time.RFC3339Nano, text)
+	return value, err == nil
+}
@@ -0,0 +1,208 @@
+// Package repository provides implementations for interacting with promotion data in the database.
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+// PromotionRepository defines methods for interacting with promotion data.
+type PromotionRepository interface {
+	GetAll() ([]*model.Promotion, error)
+	GetByID(id uint) (*model.Promotion, error)
+	GetByCode(code string) (*model.Promotion, error)
+	ExistsCode(code string, exceptID uint) (bool, error)
+	Create(p *model.Promotion) error
+	Update(p *model.Promotion) error
+	Delete(id uint) error
+}
+
+// PromotionRepositoryImpl is an implementation of PromotionRepository.
+type PromotionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPromotionRepository creates a new instance of PromotionRepositoryImpl.
+func NewPromotionRepository(db *gorm.DB) *PromotionRepositoryImpl {
+	return &PromotionRepositoryImpl{db: db}
+}
+
+// GetAll retrieves every promotion with its product and category scope.
+func (r *PromotionRepositoryImpl) GetAll() ([]*model.Promotion, error) {
+	var promotions []*model.Promotion
+
+	err := r.db.Model(&model.Promotion{}).
+		Preload("Products").
+		Preload("Categories").
+		Order("id").
+		Find(&promotions).Error
+	if err != nil {
+		return nil, utils.NewError(utils.ErrPromotionListFailed, err)
+	}
+
+	return promotions, nil
+}
+
+// GetByID retrieves a promotion with its product and category scope by its ID.
+func (r *PromotionRepositoryImpl) GetByID(id uint) (*model.Promotion, error) {
+	return r.getBy("id = ?", id)
+}
+
+// GetByCode retrieves a promotion with its product and category scope by its coupon code.
+func (r *PromotionRepositoryImpl) GetByCode(code string) (*model.Promotion, error) {
+	return r.getBy("code = ?", code)
+}
+
+// getBy retrieves the promotion matching the condition.
+func (r *PromotionRepositoryImpl) getBy(query string, arg interface{}) (*model.Promotion, error) {
+	var promotion *model.Promotion
+
+	err := r.db.Model(&model.Promotion{}).
+		Preload("Products").
+		Preload("Categories").
+		Where(query, arg).
+		First(&promotion).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewError(utils.ErrCouponNotFound, err)
+		}
+		return nil, utils.NewError(utils.ErrPromotionReadFailed, err)
+	}
+
+	return promotion, nil
+}
+
+// ExistsCode reports whether a promotion other than exceptID already uses the
+// code. Deleted promotions keep their code, since past orders refer to it.
+func (r *PromotionRepositoryImpl) ExistsCode(code string, exceptID uint) (bool, error) {
+	var count int64
+
+	err := r.db.Unscoped().Model(&model.Promotion{}).
+		Where("code = ? AND id <> ?", code, exceptID).
+		Count(&count).Error
+	if err != nil {
+		return false, utils.NewError(utils.ErrPromotionCodeCheckFailed, err)
+	}
+
+	return count > 0, nil
+}
+
+// Create adds a new promotion to the database together with its scope.
+func (r *PromotionRepositoryImpl) Create(p *model.Promotion) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	err := tx.Omit("Products", "Categories").Create(p).Error
+	if err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrPromotionCreateFailed, err)
+	}
+
+	if err = r.replaceScope(tx, p); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrPromotionCreateFailed, err)
+	}
+
+	return nil
+}
+
+// Update updates an existing promotion in the database and replaces its
+// scope. The usage count is left untouched because checkouts change it
+// concurrently.
+func (r *PromotionRepositoryImpl) Update(p *model.Promotion) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	err := tx.Omit("UsageCount", "Products", "Categories").Save(p).Error
+	if err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrPromotionUpdateFailed, err)
+	}
+
+	if err = r.replaceScope(tx, p); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrPromotionUpdateFailed, err)
+	}
+
+	return nil
+}
+
+// replaceScope makes the products and categories of the promotion the only ones it applies to.
+func (r *PromotionRepositoryImpl) replaceScope(tx *gorm.DB, p *model.Promotion) error {
+	err := tx.Exec("DELETE FROM promotion_products WHERE promotion_id = ?", p.ID).Error
+	if err == nil {
+		err = tx.Exec("DELETE FROM promotion_categories WHERE promotion_id = ?", p.ID).Error
+	}
+
+	for i := 0; err == nil && i < len(p.Products); i++ {
+		err = tx.Exec("INSERT INTO promotion_products (promotion_id, product_id) VALUES (?, ?)", p.ID, p.Products[i].ID).Error
+	}
+
+	for i := 0; err == nil && i < len(p.Categories); i++ {
+		err = tx.Exec("INSERT INTO promotion_categories (promotion_id, category_id) VALUES (?, ?)", p.ID, p.Categories[i].ID).Error
+	}
+
+	if err != nil {
+		return utils.NewError(utils.ErrPromotionScopeFailed, err)
+	}
+
+	return nil
+}
+
+// Delete deletes a promotion from the database by its ID and removes it from
+// the carts it was applied to.
+func (r *PromotionRepositoryImpl) Delete(id uint) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	err := tx.Unscoped().Where("promotion_id = ?", id).Delete(&model.CartCoupon{}).Error
+	if err == nil {
+		err = tx.Delete(&model.Promotion{}, id).Error
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrPromotionDeleteFailed, err)
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrPromotionDeleteFailed, err)
+	}
+
+	return nil
+}
+
+// redeemPromotion counts one use of the promotion. The update only applies
+// while the usage limit is not reached, so concurrent checkouts can not redeem
+// a code more times than allowed.
+func redeemPromotion(tx *gorm.DB, promotionID uint, code string) error {
+	result := tx.Model(&model.Promotion{}).
+		Where("id = ? AND (usage_limit = 0 OR usage_count < usage_limit)", promotionID).
+		Update("usage_count", gorm.Expr("usage_count + 1"))
+	if result.Error != nil {
+		return utils.NewError(utils.ErrCouponRedeemFailed, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return utils.NewError(utils.ErrCouponExhausted, fmt.Errorf("promotion %d usage limit reached", promotionID), code)
+	}
+
+	return nil
+}
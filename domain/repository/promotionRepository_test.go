@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Test_CreatePromotion tests that the Create function of the PromotionRepository stores the promotion scope.
+func Test_CreatePromotion(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Category{}, &model.Product{}, &model.Promotion{}, &model.CartCoupon{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	repo := NewPromotionRepository(db)
+
+	product := &model.Product{Name: "Product", Price: model.NewMoney(1000, model.DefaultCurrency)}
+	category := &model.Category{Name: "Category"}
+	if err = db.Create(product).Error; err != nil {
+		t.Fatalf("Error inserting product: %v", err)
+	}
+	if err = db.Create(category).Error; err != nil {
+		t.Fatalf("Error inserting category: %v", err)
+	}
+
+	promotion := &model.Promotion{
+		Code:       "SAVE10",
+		Type:       model.PromotionPercentage,
+		Value:      10,
+		Products:   []*model.Product{product},
+		Categories: []*model.Category{category},
+	}
+	if err = repo.Create(promotion); err != nil {
+		t.Fatalf("Error creating promotion: %v", err)
+	}
+
+	created, err := repo.GetByCode("SAVE10")
+	if err != nil {
+		t.Fatalf("Error getting created promotion: %v", err)
+	}
+
+	if len(created.Products) != 1 || created.Products[0].ID != product.ID {
+		t.Errorf("Expected the promotion to apply to product %d, got %+v", product.ID, created.Products)
+	}
+
+	if len(created.Categories) != 1 || created.Categories[0].ID != category.ID {
+		t.Errorf("Expected the promotion to apply to category %d, got %+v", category.ID, created.Categories)
+	}
+
+	if err = repo.Delete(created.ID); err != nil {
+		t.Fatalf("Error deleting promotion: %v", err)
+	}
+
+	exists, err := repo.ExistsCode("SAVE10", 0)
+	if err != nil {
+		t.Fatalf("Error checking promotion code: %v", err)
+	}
+
+	if !exists {
+		t.Errorf("Expected the code of a deleted promotion to stay registered")
+	}
+}
+
+// Test_CheckoutRedeemsPromotion tests that Checkout can not redeem a coupon beyond its usage limit.
+func Test_CheckoutRedeemsPromotion(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{}, &model.ShoppingCart{}, &model.ItemCart{}, &model.StockReservation{}, &model.Promotion{}, &model.CartCoupon{}, &model.Order{}, &model.OrderLine{}, &model.OrderDiscount{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	cartRepo := NewShoppingCartRepository(db, time.Minute)
+	repo := NewOrderRepository(db)
+
+	promotion := &model.Promotion{Code: "ONCE", Type: model.PromotionFixedAmount, AmountOff: model.NewMoney(500, model.DefaultCurrency), UsageLimit: 1}
+	if err = db.Create(promotion).Error; err != nil {
+		t.Fatalf("Error inserting promotion: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		cart := &model.ShoppingCart{}
+		if err = cartRepo.Create(cart); err != nil {
+			t.Fatalf("Error creating shopping cart: %v", err)
+		}
+
+		if err = cartRepo.AddCoupon(cart.ID, promotion.ID, nil); err != nil {
+			t.Fatalf("Error applying coupon: %v", err)
+		}
+
+		err = cartRepo.AddCoupon(cart.ID, promotion.ID, nil)
+		if err == nil || utils.GetCustomError(err).Status != http.StatusConflict {
+			t.Errorf("Expected a conflict applying the coupon twice, got %v", err)
+		}
+
+		stored, err := cartRepo.GetByID(cart.ID)
+		if err != nil {
+			t.Fatalf("Error getting shopping cart: %v", err)
+		}
+
+		order := &model.Order{
+			ShoppingCartID: cart.ID,
+			Status:         model.OrderPending,
+			Discounts:      []*model.OrderDiscount{{PromotionID: promotion.ID, Code: promotion.Code, Amount: promotion.AmountOff}},
+		}
+		err = repo.Checkout(order, stored.Version)
+
+		if i == 0 && err != nil {
+			t.Fatalf("Error checking out shopping cart: %v", err)
+		}
+		if i == 1 && (err == nil || utils.GetCustomError(err).Status != http.StatusConflict) {
+			t.Errorf("Expected a conflict redeeming an exhausted coupon, got %v", err)
+		}
+	}
+
+	var redeemed model.Promotion
+	if err = db.First(&redeemed, promotion.ID).Error; err != nil {
+		t.Fatalf("Error getting promotion: %v", err)
+	}
+
+	if redeemed.UsageCount != 1 {
+		t.Errorf("Expected the coupon to be redeemed once, found %d", redeemed.UsageCount)
+	}
+}
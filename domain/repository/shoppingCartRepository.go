@@ -5,95 +5,221 @@ import (
 	"codifin-challenge/domain/model"
 	"codifin-challenge/domain/utils"
 	"errors"
+	"fmt"
 	"gorm.io/gorm"
-	"net/http"
+	"time"
 )
 
 // ShoppingCartRepository defines methods for interacting with shopping cart data.
 type ShoppingCartRepository interface {
 	Create(shoppingCart *model.ShoppingCart) error
-	AddItems(items []*model.ItemCart) error
+	AddItems(items []*model.ItemCart, version *uint) error
 	GetByID(shoppingCartID uint) (*model.ShoppingCart, error)
-	DeleteProducts(cartID uint, itemIds []uint) error
+	DeleteProducts(cartID uint, itemIds []uint, version *uint) error
+	ReleaseExpiredReservations() (int, error)
+	AddCoupon(cartID, promotionID uint, version *uint) error
+	RemoveCoupon(cartID, promotionID uint, version *uint) error
 }
 
 // ShoppingCartRepositoryImpl is an implementation of ShoppingCartRepository.
 type ShoppingCartRepositoryImpl struct {
-	db *gorm.DB
+	db             *gorm.DB
+	reservationTTL time.Duration
 }
 
 // NewShoppingCartRepository creates a new instance of ShoppingCartRepositoryImpl.
-func NewShoppingCartRepository(db *gorm.DB) *ShoppingCartRepositoryImpl {
-	return &ShoppingCartRepositoryImpl{db: db}
+// Items added to a cart reserve stock for reservationTTL.
+func NewShoppingCartRepository(db *gorm.DB, reservationTTL time.Duration) *ShoppingCartRepositoryImpl {
+	return &ShoppingCartRepositoryImpl{db: db, reservationTTL: reservationTTL}
 }
 
-// GetByID retrieves a shopping cart by its ID.
+// GetByID retrieves a shopping cart by its ID. The products of its items are
+// loaded even if they were deleted, as checked-out carts keep them.
 func (r *ShoppingCartRepositoryImpl) GetByID(shoppingCartID uint) (*model.ShoppingCart, error) {
 	var cart model.ShoppingCart
 
 	err := r.db.Model(&model.ShoppingCart{}).
-		Preload("Items.Product").
+		Preload("Items.Product", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Items.Product.Categories").
+		Preload("Items.Variant").
+		Preload("Coupons.Promotion.Products").
+		Preload("Coupons.Promotion.Categories").
 		Where("id = ?", shoppingCartID).
 		First(&cart).Error
 	if err != nil {
-		return nil, utils.ToUserError(http.StatusInternalServerError, "No fue posible crear un nuevo carrito debido a un error interno", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewError(utils.ErrCartNotFound, err)
+		}
+		return nil, utils.NewError(utils.ErrCartReadFailed, err)
 	}
 	return &cart, nil
 }
 
-// Create creates a new shopping cart in the database.
+// Create creates a new shopping cart in the database, reserving stock for its items.
 func (r *ShoppingCartRepositoryImpl) Create(shoppingCart *model.ShoppingCart) error {
-	err := r.db.Model(&model.ShoppingCart{}).
-		Create(&shoppingCart).Error
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	items := shoppingCart.Items
+	shoppingCart.Version = 1
+	err := tx.Omit("Items").Create(shoppingCart).Error
 	if err != nil {
-		return utils.ToUserError(http.StatusInternalServerError, "No fue posible crear un nuevo carrito debido a un error interno", err)
+		tx.Rollback()
+		return utils.NewError(utils.ErrCartCreateFailed, err)
+	}
+
+	for _, v := range items {
+		v.ShoppingCartID = shoppingCart.ID
+	}
+
+	if err = r.addItems(tx, items); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrCartCreateFailed, err)
 	}
 
 	return nil
 }
 
-// AddItems adds items to a shopping cart in the database.
-func (r *ShoppingCartRepositoryImpl) AddItems(items []*model.ItemCart) error {
+// AddItems adds items to a shopping cart in the database, reserving stock for
+// them, if their cart is at the version given.
+func (r *ShoppingCartRepositoryImpl) AddItems(items []*model.ItemCart, version *uint) error {
 	tx := r.db.Begin()
 	if tx.Error != nil {
-		return utils.ToUserError(http.StatusInternalServerError, "No fue posible iniciar transaccion debido a un error interno", tx.Error)
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	locked := make(map[uint]bool)
+	for _, v := range items {
+		if locked[v.ShoppingCartID] {
+			continue
+		}
+		if err := r.lockOpenCart(tx, v.ShoppingCartID, version); err != nil {
+			tx.Rollback()
+			return err
+		}
+		locked[v.ShoppingCartID] = true
+	}
+
+	if err := r.addItems(tx, items); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrCartAddFailed, err)
 	}
 
+	return nil
+}
+
+// addItems merges the items into the cart, one line per product variant, and
+// renews the stock reservation of each line for its new count.
+func (r *ShoppingCartRepositoryImpl) addItems(tx *gorm.DB, items []*model.ItemCart) error {
+	expiresAt := time.Now().Add(r.reservationTTL)
+
 	for _, v := range items {
+		if err := r.checkProduct(tx, v); err != nil {
+			return err
+		}
+
+		if err := r.checkVariant(tx, v); err != nil {
+			return err
+		}
+
 		var existingItem model.ItemCart
-		result := tx.Where("shopping_cart_id = ? AND product_id = ?", v.ShoppingCartID, v.ProductID).First(&existingItem)
+		result := whereVariant(tx.Where("shopping_cart_id = ? AND product_id = ?", v.ShoppingCartID, v.ProductID), v.VariantID).
+			First(&existingItem)
 		if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			tx.Rollback()
-			return utils.ToUserError(http.StatusInternalServerError, "No fue posible agregar productos al carrito debido a un error interno", result.Error)
+			return utils.NewError(utils.ErrCartAddFailed, result.Error)
+		}
+
+		count := existingItem.Count + v.Count
+		if err := reserveStock(tx, v.ShoppingCartID, v.ProductID, v.VariantID, count, expiresAt); err != nil {
+			return err
 		}
 
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			result = tx.Create(&v)
-			if result.Error != nil {
-				tx.Rollback()
-				return utils.ToUserError(http.StatusInternalServerError, "No fue posible agregar productos al carrito debido a un error interno", result.Error)
-			}
+			result = tx.Omit("Product", "Variant").Create(&v)
 		} else {
 			result = tx.Model(&existingItem).Update("count", gorm.Expr("count + ?", v.Count))
-			if result.Error != nil {
-				tx.Rollback()
-				return utils.ToUserError(http.StatusInternalServerError, "No fue posible agregar productos al carrito debido a un error interno", result.Error)
-			}
+		}
+
+		if result.Error != nil {
+			return utils.NewError(utils.ErrCartAddFailed, result.Error)
 		}
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		return utils.ToUserError(http.StatusInternalServerError, "No fue posible agregar productos al carrito debido a un error interno", err)
+	return nil
+}
+
+// checkProduct makes sure the product of an item is on sale: drafts are not
+// published yet and archived products are no longer sold.
+func (r *ShoppingCartRepositoryImpl) checkProduct(tx *gorm.DB, item *model.ItemCart) error {
+	var product model.Product
+	err := tx.Select("id, code, name, status").Where("id = ?", item.ProductID).First(&product).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.NewError(utils.ErrProductNotFound, err)
+		}
+		return utils.NewError(utils.ErrCartAddFailed, err)
+	}
+
+	switch product.Status {
+	case model.ProductDraft:
+		return utils.NewError(utils.ErrProductNotPublished, fmt.Errorf("product %d is a draft", product.ID), product.Name)
+	case model.ProductArchived:
+		return utils.NewError(utils.ErrProductArchived, fmt.Errorf("product %d is archived", product.ID), product.Name)
+	}
+
+	return nil
+}
+
+// checkVariant makes sure an item names one of the variants of its product
+// when the product is sold in variants, and none otherwise.
+func (r *ShoppingCartRepositoryImpl) checkVariant(tx *gorm.DB, item *model.ItemCart) error {
+	var variants int64
+	err := tx.Model(&model.ProductVariant{}).Where("product_id = ?", item.ProductID).Count(&variants).Error
+	if err != nil {
+		return utils.NewError(utils.ErrCartAddFailed, err)
+	}
+
+	if item.VariantID == nil {
+		if variants > 0 {
+			return utils.NewError(utils.ErrVariantRequired, fmt.Errorf("product %d requires a variant", item.ProductID))
+		}
+		return nil
+	}
+
+	var matches int64
+	err = tx.Model(&model.ProductVariant{}).Where("id = ? AND product_id = ?", *item.VariantID, item.ProductID).Count(&matches).Error
+	if err != nil {
+		return utils.NewError(utils.ErrCartAddFailed, err)
+	}
+
+	if matches == 0 {
+		return utils.NewError(utils.ErrVariantNotFound, fmt.Errorf("variant %d of product %d not found", *item.VariantID, item.ProductID))
 	}
 
 	return nil
 }
 
-// DeleteProducts deletes products from a shopping cart.
-func (r *ShoppingCartRepositoryImpl) DeleteProducts(cartID uint, itemIds []uint) error {
+// DeleteProducts deletes products, with all their variants, from a shopping
+// cart at the version given and releases their stock reservations.
+func (r *ShoppingCartRepositoryImpl) DeleteProducts(cartID uint, itemIds []uint, version *uint) error {
 	tx := r.db.Begin()
 	if tx.Error != nil {
-		return utils.ToUserError(http.StatusInternalServerError, "No fue posible iniciar transaccion debido a un error interno", tx.Error)
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	if err := r.lockOpenCart(tx, cartID, version); err != nil {
+		tx.Rollback()
+		return err
 	}
 
 	for _, itemId := range itemIds {
@@ -101,12 +227,156 @@ func (r *ShoppingCartRepositoryImpl) DeleteProducts(cartID uint, itemIds []uint)
 			Delete(&model.ItemCart{}).Error
 		if err != nil {
 			tx.Rollback()
-			return utils.ToUserError(http.StatusInternalServerError, "No fue posible eliminar productos del carrito debido a un error interno", err)
+			return utils.NewError(utils.ErrCartRemoveFailed, err)
+		}
+
+		if err = releaseProductReservations(tx, cartID, itemId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrCartDeleteFailed, err)
+	}
+
+	return nil
+}
+
+// ReleaseExpiredReservations returns the units of every expired reservation to
+// the available stock and reports how many reservations were released. The
+// items stay in their carts and are reserved again on checkout.
+func (r *ShoppingCartRepositoryImpl) ReleaseExpiredReservations() (int, error) {
+	now := time.Now()
+
+	var expired []*model.StockReservation
+	err := r.db.Where("expires_at <= ?", now).Find(&expired).Error
+	if err != nil {
+		return 0, utils.NewError(utils.ErrExpiredReservationsFailed, err)
+	}
+
+	released := 0
+	for _, reservation := range expired {
+		tx := r.db.Begin()
+		if tx.Error != nil {
+			return released, utils.NewError(utils.ErrTransactionFailed, tx.Error)
+		}
+
+		deleted, err := deleteReservation(tx, reservation, now)
+		if err != nil {
+			tx.Rollback()
+			return released, err
+		}
+
+		if err = tx.Commit().Error; err != nil {
+			return released, utils.NewError(utils.ErrStockReleaseFailed, err)
+		}
+
+		if deleted {
+			released++
 		}
 	}
 
+	return released, nil
+}
+
+// AddCoupon applies a promotion to an open shopping cart at the version given.
+// A promotion can be applied only once to the same cart.
+func (r *ShoppingCartRepositoryImpl) AddCoupon(cartID, promotionID uint, version *uint) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	if err := r.lockOpenCart(tx, cartID, version); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var applied int64
+	err := tx.Model(&model.CartCoupon{}).Where("shopping_cart_id = ? AND promotion_id = ?", cartID, promotionID).Count(&applied).Error
+	if err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrCouponApplyFailed, err)
+	}
+
+	if applied > 0 {
+		tx.Rollback()
+		return utils.NewError(utils.ErrCouponAlreadyApplied, fmt.Errorf("promotion %d already applied to cart %d", promotionID, cartID))
+	}
+
+	coupon := &model.CartCoupon{ShoppingCartID: cartID, PromotionID: promotionID}
+	if err = tx.Omit("Promotion").Create(coupon).Error; err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrCouponApplyFailed, err)
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrCouponApplyFailed, err)
+	}
+
+	return nil
+}
+
+// RemoveCoupon removes a promotion from an open shopping cart at the version
+// given.
+func (r *ShoppingCartRepositoryImpl) RemoveCoupon(cartID, promotionID uint, version *uint) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	if err := r.lockOpenCart(tx, cartID, version); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	result := tx.Unscoped().Where("shopping_cart_id = ? AND promotion_id = ?", cartID, promotionID).Delete(&model.CartCoupon{})
+	if result.Error != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrCouponRemoveFailed, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return utils.NewError(utils.ErrCouponNotApplied, fmt.Errorf("promotion %d not applied to cart %d", promotionID, cartID))
+	}
+
 	if err := tx.Commit().Error; err != nil {
-		return utils.ToUserError(http.StatusInternalServerError, "No fue posible eliminar el carrito debido a un error interno", err)
+		return utils.NewError(utils.ErrCouponRemoveFailed, err)
+	}
+
+	return nil
+}
+
+// lockOpenCart makes sure the cart exists, was not checked out yet and is at
+// the version given, if any, and moves it to its next version. The update
+// takes a row lock on the cart, so a concurrent checkout waits until the
+// running transaction finishes.
+func (r *ShoppingCartRepositoryImpl) lockOpenCart(tx *gorm.DB, cartID uint, version *uint) error {
+	query := tx.Model(&model.ShoppingCart{}).Where("id = ? AND checked_out_at IS NULL", cartID)
+	if version != nil {
+		query = query.Where("version = ?", *version)
+	}
+
+	result := query.Updates(map[string]interface{}{"updated_at": time.Now(), "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		return utils.NewError(utils.ErrCartCheckFailed, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		var cart model.ShoppingCart
+		err := tx.Where("id = ?", cartID).First(&cart).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.NewError(utils.ErrCartNotFound, err)
+		}
+		if err != nil {
+			return utils.NewError(utils.ErrCartCheckFailed, err)
+		}
+		if cart.CheckedOutAt != nil {
+			return utils.NewError(utils.ErrCartLocked, fmt.Errorf("shopping cart %d is checked out", cartID))
+		}
+		return utils.NewError(utils.ErrVersionConflict, fmt.Errorf("expected version %d, found %d", *version, cart.Version))
 	}
 
 	return nil
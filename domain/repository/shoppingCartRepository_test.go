@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"fmt"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test_AddItemsConcurrently tests that concurrent AddItems calls can not oversell a product.
+func Test_AddItemsConcurrently(t *testing.T) {
+	// A file database is shared by every connection of the pool, unlike an
+	// in-memory one, so the carts really compete for the product.
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=10000&_txlock=immediate", filepath.Join(t.TempDir(), "stock.db"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{}, &model.ShoppingCart{}, &model.ItemCart{}, &model.StockReservation{}, &model.Promotion{}, &model.CartCoupon{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	repo := NewShoppingCartRepository(db, time.Minute)
+
+	product := &model.Product{Name: "Product", Price: model.NewMoney(1000, model.DefaultCurrency), Stock: 5}
+	if err = db.Create(product).Error; err != nil {
+		t.Fatalf("Error inserting product: %v", err)
+	}
+
+	const buyers = 20
+	carts := make([]*model.ShoppingCart, buyers)
+	for i := range carts {
+		carts[i] = &model.ShoppingCart{}
+		if err = repo.Create(carts[i]); err != nil {
+			t.Fatalf("Error creating shopping cart: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for _, cart := range carts {
+		wg.Add(1)
+		go func(cartID uint) {
+			defer wg.Done()
+			err := repo.AddItems([]*model.ItemCart{{ShoppingCartID: cartID, ProductID: product.ID, Count: 1}}, nil)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				succeeded++
+			} else if utils.GetCustomError(err).Status != http.StatusConflict {
+				t.Errorf("Expected a conflict for the carts out of stock, got %v", err)
+			}
+		}(cart.ID)
+	}
+	wg.Wait()
+
+	if succeeded != 5 {
+		t.Errorf("Expected 5 carts to get the product, found %d", succeeded)
+	}
+
+	var reserved model.Product
+	if err = db.First(&reserved, product.ID).Error; err != nil {
+		t.Fatalf("Error getting product: %v", err)
+	}
+
+	if reserved.Reserved != 5 || reserved.Available() != 0 {
+		t.Errorf("Expected 5 reserved units and none available, found %d and %d", reserved.Reserved, reserved.Available())
+	}
+}
+
+// Test_ReleaseExpiredReservations tests the ReleaseExpiredReservations function of the ShoppingCartRepository.
+func Test_ReleaseExpiredReservations(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{}, &model.ShoppingCart{}, &model.ItemCart{}, &model.StockReservation{}, &model.Promotion{}, &model.CartCoupon{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	repo := NewShoppingCartRepository(db, -time.Minute)
+
+	product := &model.Product{Name: "Product", Price: model.NewMoney(1000, model.DefaultCurrency), Stock: 3}
+	if err = db.Create(product).Error; err != nil {
+		t.Fatalf("Error inserting product: %v", err)
+	}
+
+	cart := &model.ShoppingCart{Items: []*model.ItemCart{{ProductID: product.ID, Count: 3}}}
+	if err = repo.Create(cart); err != nil {
+		t.Fatalf("Error creating shopping cart: %v", err)
+	}
+
+	err = repo.AddItems([]*model.ItemCart{{ShoppingCartID: cart.ID, ProductID: product.ID, Count: 1}}, nil)
+	if err == nil || utils.GetCustomError(err).Status != http.StatusConflict {
+		t.Errorf("Expected a conflict adding more units than in stock, got %v", err)
+	}
+
+	released, err := repo.ReleaseExpiredReservations()
+	if err != nil {
+		t.Fatalf("Error releasing expired reservations: %v", err)
+	}
+
+	if released != 1 {
+		t.Errorf("Expected 1 released reservation, found %d", released)
+	}
+
+	var available model.Product
+	if err = db.First(&available, product.ID).Error; err != nil {
+		t.Fatalf("Error getting product: %v", err)
+	}
+
+	if available.Available() != 3 {
+		t.Errorf("Expected 3 available units, found %d", available.Available())
+	}
+}
+
+// Test_AddItemsByVariant tests that AddItems requires a variant for products sold in variants and merges counts per variant.
+func Test_AddItemsByVariant(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{}, &model.ShoppingCart{}, &model.ItemCart{}, &model.StockReservation{}, &model.Promotion{}, &model.CartCoupon{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	repo := NewShoppingCartRepository(db, time.Minute)
+
+	product := &model.Product{Name: "T-Shirt", Price: model.NewMoney(1000, model.DefaultCurrency)}
+	if err = db.Create(product).Error; err != nil {
+		t.Fatalf("Error inserting product: %v", err)
+	}
+
+	small := &model.ProductVariant{ProductID: product.ID, SKU: "TS-S", Stock: 2, Attributes: model.Attributes{"size": "S"}}
+	large := &model.ProductVariant{ProductID: product.ID, SKU: "TS-L", Stock: 5, Attributes: model.Attributes{"size": "L"}}
+	if err = db.Create([]*model.ProductVariant{small, large}).Error; err != nil {
+		t.Fatalf("Error inserting variants: %v", err)
+	}
+
+	cart := &model.ShoppingCart{}
+	if err = repo.Create(cart); err != nil {
+		t.Fatalf("Error creating shopping cart: %v", err)
+	}
+
+	err = repo.AddItems([]*model.ItemCart{{ShoppingCartID: cart.ID, ProductID: product.ID, Count: 1}}, nil)
+	if err == nil || utils.GetCustomError(err).Status != http.StatusBadRequest {
+		t.Errorf("Expected a bad request adding a product without its variant, got %v", err)
+	}
+
+	items := []*model.ItemCart{
+		{ShoppingCartID: cart.ID, ProductID: product.ID, VariantID: &small.ID, Count: 1},
+		{ShoppingCartID: cart.ID, ProductID: product.ID, VariantID: &large.ID, Count: 2},
+		{ShoppingCartID: cart.ID, ProductID: product.ID, VariantID: &small.ID, Count: 1},
+	}
+	if err = repo.AddItems(items, nil); err != nil {
+		t.Fatalf("Error adding items: %v", err)
+	}
+
+	err = repo.AddItems([]*model.ItemCart{{ShoppingCartID: cart.ID, ProductID: product.ID, VariantID: &small.ID, Count: 1}}, nil)
+	if err == nil || utils.GetCustomError(err).Status != http.StatusConflict {
+		t.Errorf("Expected a conflict adding more units of the variant than in stock, got %v", err)
+	}
+
+	cart, err = repo.GetByID(cart.ID)
+	if err != nil {
+		t.Fatalf("Error getting shopping cart: %v", err)
+	}
+
+	if len(cart.Items) != 2 {
+		t.Fatalf("Expected 2 cart lines, found %d", len(cart.Items))
+	}
+
+	for _, item := range cart.Items {
+		if item.Variant == nil {
+			t.Fatalf("Expected cart line to load its variant")
+		}
+		if item.Variant.SKU == "TS-S" && item.Count != 2 || item.Variant.SKU == "TS-L" && item.Count != 2 {
+			t.Errorf("Unexpected count %d for variant %s", item.Count, item.Variant.SKU)
+		}
+	}
+}
+
+// Test_AddItemsNotOnSale tests that drafts and archived products can not be added to a shopping cart.
+func Test_AddItemsNotOnSale(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{}, &model.ShoppingCart{}, &model.ItemCart{}, &model.StockReservation{}, &model.Promotion{}, &model.CartCoupon{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	repo := NewShoppingCartRepository(db, time.Minute)
+
+	draft := &model.Product{Name: "Draft", Status: model.ProductDraft, Stock: 5}
+	archived := &model.Product{Name: "Archived", Status: model.ProductArchived, Stock: 5}
+	if err = db.Create([]*model.Product{draft, archived}).Error; err != nil {
+		t.Fatalf("Error inserting products: %v", err)
+	}
+
+	cart := &model.ShoppingCart{}
+	if err = repo.Create(cart); err != nil {
+		t.Fatalf("Error creating shopping cart: %v", err)
+	}
+
+	cases := map[*model.Product]utils.ErrorCode{draft: utils.ErrProductNotPublished, archived: utils.ErrProductArchived}
+	for product, code := range cases {
+		err = repo.AddItems([]*model.ItemCart{{ShoppingCartID: cart.ID, ProductID: product.ID, Count: 1}}, nil)
+		if e, ok := err.(*utils.DBError); !ok || e.Status != http.StatusConflict || e.Code != code {
+			t.Errorf("Expected a conflict with code %s adding %s, got %v", code, product.Name, err)
+		}
+	}
+
+	var reserved int64
+	if err = db.Model(&model.StockReservation{}).Count(&reserved).Error; err != nil || reserved != 0 {
+		t.Errorf("Expected no reservations, found %d (%v)", reserved, err)
+	}
+}
+
+// Test_AddItemsAtVersion tests that changes to a shopping cart at the same
+// version can not both succeed.
+func Test_AddItemsAtVersion(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{}, &model.ShoppingCart{}, &model.ItemCart{}, &model.StockReservation{}, &model.Promotion{}, &model.CartCoupon{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	repo := NewShoppingCartRepository(db, time.Minute)
+
+	product := &model.Product{Name: "Product", Price: model.NewMoney(1000, model.DefaultCurrency), Stock: 5}
+	if err = db.Create(product).Error; err != nil {
+		t.Fatalf("Error inserting product: %v", err)
+	}
+
+	cart := &model.ShoppingCart{}
+	if err = repo.Create(cart); err != nil {
+		t.Fatalf("Error creating shopping cart: %v", err)
+	}
+
+	version := cart.Version
+	if err = repo.AddItems([]*model.ItemCart{{ShoppingCartID: cart.ID, ProductID: product.ID, Count: 1}}, &version); err != nil {
+		t.Fatalf("Error adding items at version %d: %v", version, err)
+	}
+
+	err = repo.AddItems([]*model.ItemCart{{ShoppingCartID: cart.ID, ProductID: product.ID, Count: 1}}, &version)
+	if e, ok := err.(*utils.DBError); !ok || e.Code != utils.ErrVersionConflict {
+		t.Errorf("Expected a version conflict adding items at version %d again, got %v", version, err)
+	}
+
+	stored, err := repo.GetByID(cart.ID)
+	if err != nil {
+		t.Fatalf("Error getting shopping cart: %v", err)
+	}
+	if len(stored.Items) != 1 || stored.Items[0].Count != 1 || stored.Version != version+1 {
+		t.Errorf("Expected 1 unit at version %d, got %d items at version %d", version+1, len(stored.Items), stored.Version)
+	}
+}
@@ -0,0 +1,198 @@
+// Package repository provides helpers to reserve and consume product stock inside a transaction.
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+// reserveStock makes the cart hold a reservation of quantity units of the
+// product, or of one of its variants, until expiresAt. Only the units missing
+// from the current reservation are taken from the available stock.
+func reserveStock(tx *gorm.DB, cartID, productID uint, variantID *uint, quantity uint, expiresAt time.Time) error {
+	var reservation model.StockReservation
+	err := whereVariant(tx.Where("shopping_cart_id = ? AND product_id = ?", cartID, productID), variantID).
+		First(&reservation).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.NewError(utils.ErrStockReserveFailed, err)
+	}
+
+	if quantity > reservation.Quantity {
+		if err = reserveUnits(tx, productID, variantID, quantity-reservation.Quantity); err != nil {
+			return err
+		}
+	} else if quantity < reservation.Quantity {
+		if err = releaseUnits(tx, productID, variantID, reservation.Quantity-quantity); err != nil {
+			return err
+		}
+	}
+
+	reservation.ShoppingCartID = cartID
+	reservation.ProductID = productID
+	reservation.VariantID = variantID
+	reservation.Quantity = quantity
+	reservation.ExpiresAt = expiresAt
+
+	if err = tx.Save(&reservation).Error; err != nil {
+		return utils.NewError(utils.ErrStockReserveFailed, err)
+	}
+
+	return nil
+}
+
+// releaseProductReservations removes the reservations held by the cart for
+// the product and all its variants, returning their units to the available stock.
+func releaseProductReservations(tx *gorm.DB, cartID, productID uint) error {
+	var reservations []*model.StockReservation
+	err := tx.Where("shopping_cart_id = ? AND product_id = ?", cartID, productID).
+		Find(&reservations).Error
+	if err != nil {
+		return utils.NewError(utils.ErrStockReleaseFailed, err)
+	}
+
+	return deleteReservations(tx, reservations)
+}
+
+// releaseReservation removes the reservation held by the cart for the product
+// or one of its variants, if any, returning its units to the available stock.
+func releaseReservation(tx *gorm.DB, cartID, productID uint, variantID *uint) error {
+	var reservations []*model.StockReservation
+	err := whereVariant(tx.Where("shopping_cart_id = ? AND product_id = ?", cartID, productID), variantID).
+		Find(&reservations).Error
+	if err != nil {
+		return utils.NewError(utils.ErrStockReleaseFailed, err)
+	}
+
+	return deleteReservations(tx, reservations)
+}
+
+// deleteReservations deletes every reservation, returning its units to the available stock.
+func deleteReservations(tx *gorm.DB, reservations []*model.StockReservation) error {
+	for _, reservation := range reservations {
+		if _, err := deleteReservation(tx, reservation, time.Time{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteReservation deletes a reservation and returns its units to the
+// available stock. When expiredBefore is set, the reservation is only deleted
+// if it was not renewed after that moment. It reports whether it was deleted.
+func deleteReservation(tx *gorm.DB, reservation *model.StockReservation, expiredBefore time.Time) (bool, error) {
+	query := tx.Unscoped().Where("id = ?", reservation.ID)
+	if !expiredBefore.IsZero() {
+		query = query.Where("expires_at <= ?", expiredBefore)
+	}
+
+	result := query.Delete(&model.StockReservation{})
+	if result.Error != nil {
+		return false, utils.NewError(utils.ErrStockReleaseFailed, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	return true, releaseUnits(tx, reservation.ProductID, reservation.VariantID, reservation.Quantity)
+}
+
+// consumeStock takes quantity units of the product, or of one of its
+// variants, out of the stock when a cart is checked out. The reservation held
+// by the cart is released first, so its units count as available for the cart itself.
+func consumeStock(tx *gorm.DB, cartID, productID uint, variantID *uint, quantity uint) error {
+	if err := releaseReservation(tx, cartID, productID, variantID); err != nil {
+		return err
+	}
+
+	stock, id := stockOwner(productID, variantID)
+	result := tx.Model(stock).
+		Where("id = ? AND stock >= reserved + ?", id, quantity).
+		Update("stock", gorm.Expr("stock - ?", quantity))
+
+	return checkStockUpdate(tx, result, productID, variantID)
+}
+
+// reserveUnits adds units to the reserved count of a product or variant. The
+// update only applies while enough units are available, so concurrent carts
+// competing for the last units can not oversell it.
+func reserveUnits(tx *gorm.DB, productID uint, variantID *uint, units uint) error {
+	stock, id := stockOwner(productID, variantID)
+	result := tx.Model(stock).
+		Where("id = ? AND stock >= reserved + ?", id, units).
+		Update("reserved", gorm.Expr("reserved + ?", units))
+
+	return checkStockUpdate(tx, result, productID, variantID)
+}
+
+// releaseUnits subtracts units from the reserved count of a product or variant.
+func releaseUnits(tx *gorm.DB, productID uint, variantID *uint, units uint) error {
+	stock, id := stockOwner(productID, variantID)
+	err := tx.Unscoped().Model(stock).
+		Where("id = ?", id).
+		Update("reserved", gorm.Expr("CASE WHEN reserved > ? THEN reserved - ? ELSE 0 END", units, units)).Error
+	if err != nil {
+		return utils.NewError(utils.ErrStockReleaseFailed, err)
+	}
+
+	return nil
+}
+
+// checkStockUpdate tells apart a missing product or variant from a lack of
+// stock when a conditional stock update did not affect any row.
+func checkStockUpdate(tx *gorm.DB, result *gorm.DB, productID uint, variantID *uint) error {
+	if result.Error != nil {
+		return utils.NewError(utils.ErrStockUpdateFailed, result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	var product model.Product
+	err := tx.Where("id = ?", productID).First(&product).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.NewError(utils.ErrProductNotFound, err)
+		}
+		return utils.NewError(utils.ErrStockUpdateFailed, err)
+	}
+
+	name, available := product.Name, product.Available()
+	if variantID != nil {
+		var variant model.ProductVariant
+		err = tx.Where("id = ? AND product_id = ?", *variantID, productID).First(&variant).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewError(utils.ErrVariantNotFound, err)
+			}
+			return utils.NewError(utils.ErrStockUpdateFailed, err)
+		}
+		name, available = fmt.Sprintf("%s (%s)", product.Name, variant.SKU), variant.Available()
+	}
+
+	return utils.NewError(utils.ErrInsufficientStock, fmt.Errorf("product %d has %d units available", productID, available), name, available)
+}
+
+// stockOwner returns the model that tracks the stock of an item, the variant
+// when one is set and the product otherwise, together with its ID.
+func stockOwner(productID uint, variantID *uint) (interface{}, uint) {
+	if variantID != nil {
+		return &model.ProductVariant{}, *variantID
+	}
+	return &model.Product{}, productID
+}
+
+// whereVariant restricts the query to rows of the given variant, or to rows
+// without a variant when it is nil.
+func whereVariant(query *gorm.DB, variantID *uint) *gorm.DB {
+	if variantID == nil {
+		return query.Where("variant_id IS NULL")
+	}
+	return query.Where("variant_id = ?", *variantID)
+}
@@ -0,0 +1,127 @@
+// Package repository provides implementations for interacting with tax rule data in the database.
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"errors"
+	"gorm.io/gorm"
+)
+
+// TaxRuleRepository defines methods for interacting with tax rule data.
+type TaxRuleRepository interface {
+	GetAll() ([]*model.TaxRule, error)
+	GetByID(id uint) (*model.TaxRule, error)
+	GetByRegion(region string) ([]*model.TaxRule, error)
+	ExistsRule(region, taxCategory string, exceptID uint) (bool, error)
+	ExistsCategory(taxCategory string) (bool, error)
+	Create(rule *model.TaxRule) error
+	Update(rule *model.TaxRule) error
+	Delete(id uint) error
+}
+
+// TaxRuleRepositoryImpl is an implementation of TaxRuleRepository.
+type TaxRuleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewTaxRuleRepository creates a new instance of TaxRuleRepositoryImpl.
+func NewTaxRuleRepository(db *gorm.DB) *TaxRuleRepositoryImpl {
+	return &TaxRuleRepositoryImpl{db: db}
+}
+
+// GetAll retrieves every tax rule ordered by region and tax category.
+func (r *TaxRuleRepositoryImpl) GetAll() ([]*model.TaxRule, error) {
+	var rules []*model.TaxRule
+
+	err := r.db.Model(&model.TaxRule{}).Order("region, tax_category").Find(&rules).Error
+	if err != nil {
+		return nil, utils.NewError(utils.ErrTaxRuleListFailed, err)
+	}
+
+	return rules, nil
+}
+
+// GetByID retrieves a tax rule by its ID.
+func (r *TaxRuleRepositoryImpl) GetByID(id uint) (*model.TaxRule, error) {
+	var rule *model.TaxRule
+
+	err := r.db.Model(&model.TaxRule{}).Where("id = ?", id).First(&rule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewError(utils.ErrTaxRuleNotFound, err)
+		}
+		return nil, utils.NewError(utils.ErrTaxRuleReadFailed, err)
+	}
+
+	return rule, nil
+}
+
+// GetByRegion retrieves the tax rules of a region.
+func (r *TaxRuleRepositoryImpl) GetByRegion(region string) ([]*model.TaxRule, error) {
+	var rules []*model.TaxRule
+
+	err := r.db.Model(&model.TaxRule{}).Where("region = ?", region).Find(&rules).Error
+	if err != nil {
+		return nil, utils.NewError(utils.ErrTaxRuleListFailed, err)
+	}
+
+	return rules, nil
+}
+
+// ExistsRule reports whether a rule other than exceptID already sets the rate of the tax category in the region.
+func (r *TaxRuleRepositoryImpl) ExistsRule(region, taxCategory string, exceptID uint) (bool, error) {
+	var count int64
+
+	err := r.db.Model(&model.TaxRule{}).
+		Where("region = ? AND tax_category = ? AND id <> ?", region, taxCategory, exceptID).
+		Count(&count).Error
+	if err != nil {
+		return false, utils.NewError(utils.ErrTaxRuleCheckFailed, err)
+	}
+
+	return count > 0, nil
+}
+
+// ExistsCategory reports whether any region has a rule for the tax category.
+func (r *TaxRuleRepositoryImpl) ExistsCategory(taxCategory string) (bool, error) {
+	var count int64
+
+	err := r.db.Model(&model.TaxRule{}).Where("tax_category = ?", taxCategory).Count(&count).Error
+	if err != nil {
+		return false, utils.NewError(utils.ErrTaxCategoryCheckFailed, err)
+	}
+
+	return count > 0, nil
+}
+
+// Create adds a new tax rule to the database.
+func (r *TaxRuleRepositoryImpl) Create(rule *model.TaxRule) error {
+	err := r.db.Create(rule).Error
+	if err != nil {
+		return utils.NewError(utils.ErrTaxRuleCreateFailed, err)
+	}
+
+	return nil
+}
+
+// Update updates an existing tax rule in the database.
+func (r *TaxRuleRepositoryImpl) Update(rule *model.TaxRule) error {
+	err := r.db.Save(rule).Error
+	if err != nil {
+		return utils.NewError(utils.ErrTaxRuleUpdateFailed, err)
+	}
+
+	return nil
+}
+
+// Delete deletes a tax rule from the database by its ID. The rule is removed
+// for good so the region and tax category can be configured again.
+func (r *TaxRuleRepositoryImpl) Delete(id uint) error {
+	err := r.db.Unscoped().Delete(&model.TaxRule{}, id).Error
+	if err != nil {
+		return utils.NewError(utils.ErrTaxRuleDeleteFailed, err)
+	}
+
+	return nil
+}
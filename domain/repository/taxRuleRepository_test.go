@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"testing"
+)
+
+// Test_GetByRegion tests the GetByRegion and ExistsRule functions of the TaxRuleRepository.
+func Test_GetByRegion(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.TaxRule{})
+	if err != nil {
+		t.Fatalf("Error migrating table: %v", err)
+	}
+
+	repo := NewTaxRuleRepository(db)
+
+	rules := []*model.TaxRule{
+		{Region: "MX", TaxCategory: model.TaxStandard, Rate: 0.16},
+		{Region: "MX", TaxCategory: model.TaxExempt, Rate: 0},
+		{Region: "MX-BORDER", TaxCategory: model.TaxStandard, Rate: 0.08},
+	}
+	for _, rule := range rules {
+		if err = repo.Create(rule); err != nil {
+			t.Fatalf("Error creating tax rule: %v", err)
+		}
+	}
+
+	border, err := repo.GetByRegion("MX-BORDER")
+	if err != nil {
+		t.Fatalf("Error getting tax rules: %v", err)
+	}
+
+	if len(border) != 1 || border[0].Rate != 0.08 {
+		t.Errorf("Expected the 8%% border rate, got %+v", border)
+	}
+
+	exists, err := repo.ExistsRule("MX", model.TaxExempt, 0)
+	if err != nil {
+		t.Fatalf("Error checking tax rule: %v", err)
+	}
+
+	if !exists {
+		t.Errorf("Expected the exempt rule of MX to exist")
+	}
+
+	if err = repo.Delete(rules[1].ID); err != nil {
+		t.Fatalf("Error deleting tax rule: %v", err)
+	}
+
+	if err = repo.Create(&model.TaxRule{Region: "MX", TaxCategory: model.TaxExempt}); err != nil {
+		t.Errorf("Expected a deleted tax rule to be configurable again, got %v", err)
+	}
+}
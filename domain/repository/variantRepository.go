@@ -0,0 +1,148 @@
+// Package repository provides implementations for interacting with product variant data in the database.
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"errors"
+	"gorm.io/gorm"
+)
+
+// VariantRepository defines methods for interacting with product variant data.
+type VariantRepository interface {
+	GetByID(productID, variantID uint) (*model.ProductVariant, error)
+	ExistsSKU(sku string, exceptID uint) (bool, error)
+	Create(v *model.ProductVariant) error
+	Update(v *model.ProductVariant) error
+	Delete(productID, variantID uint) error
+}
+
+// VariantRepositoryImpl is an implementation of VariantRepository.
+type VariantRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewVariantRepository creates a new instance of VariantRepositoryImpl.
+func NewVariantRepository(db *gorm.DB) *VariantRepositoryImpl {
+	return &VariantRepositoryImpl{db: db}
+}
+
+// GetByID retrieves a variant of a product by its ID.
+func (r *VariantRepositoryImpl) GetByID(productID, variantID uint) (*model.ProductVariant, error) {
+	var variant *model.ProductVariant
+
+	err := r.db.Model(&model.ProductVariant{}).
+		Where("id = ? AND product_id = ?", variantID, productID).
+		First(&variant).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewError(utils.ErrVariantNotFound, err)
+		}
+		return nil, utils.NewError(utils.ErrVariantReadFailed, err)
+	}
+
+	return variant, nil
+}
+
+// ExistsSKU reports whether a variant other than exceptID already uses the SKU.
+func (r *VariantRepositoryImpl) ExistsSKU(sku string, exceptID uint) (bool, error) {
+	var count int64
+
+	err := r.db.Model(&model.ProductVariant{}).
+		Where("sku = ? AND id <> ?", sku, exceptID).
+		Count(&count).Error
+	if err != nil {
+		return false, utils.NewError(utils.ErrVariantSKUCheckFailed, err)
+	}
+
+	return count > 0, nil
+}
+
+// Create adds a new variant to the database.
+func (r *VariantRepositoryImpl) Create(v *model.ProductVariant) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	if err := tx.Create(v).Error; err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrVariantCreateFailed, err)
+	}
+
+	if err := touchProduct(tx, v.ProductID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrVariantCreateFailed, err)
+	}
+
+	return nil
+}
+
+// Update updates an existing variant in the database. The reserved units are
+// left untouched because carts change them concurrently. A variant without a
+// price gets its price columns cleared, since saving a nil embedded price
+// would store an empty amount instead.
+func (r *VariantRepositoryImpl) Update(v *model.ProductVariant) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	inheritsPrice := v.Price == nil
+
+	if err := tx.Omit("Reserved").Save(v).Error; err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrVariantUpdateFailed, err)
+	}
+
+	if inheritsPrice {
+		err := tx.Model(&model.ProductVariant{}).
+			Where("id = ?", v.ID).
+			UpdateColumns(map[string]interface{}{"price_amount": gorm.Expr("NULL"), "price_currency": gorm.Expr("NULL")}).Error
+		if err != nil {
+			tx.Rollback()
+			return utils.NewError(utils.ErrVariantUpdateFailed, err)
+		}
+		v.Price = nil
+	}
+
+	if err := touchProduct(tx, v.ProductID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrVariantUpdateFailed, err)
+	}
+
+	return nil
+}
+
+// Delete deletes a variant of a product by its ID.
+func (r *VariantRepositoryImpl) Delete(productID, variantID uint) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return utils.NewError(utils.ErrTransactionFailed, tx.Error)
+	}
+
+	err := tx.Where("product_id = ?", productID).Delete(&model.ProductVariant{}, variantID).Error
+	if err != nil {
+		tx.Rollback()
+		return utils.NewError(utils.ErrVariantDeleteFailed, err)
+	}
+
+	if err = touchProduct(tx, productID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return utils.NewError(utils.ErrVariantDeleteFailed, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"codifin-challenge/domain/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"testing"
+)
+
+// Test_UpdateVariantPrice tests that the Update function of the VariantRepository can clear the price of a variant.
+func Test_UpdateVariantPrice(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	err = db.AutoMigrate(&model.Product{}, &model.ProductVariant{})
+	if err != nil {
+		t.Fatalf("Error migrating tables: %v", err)
+	}
+
+	repo := NewVariantRepository(db)
+
+	product := &model.Product{Name: "T-Shirt", Price: model.NewMoney(1999, model.DefaultCurrency)}
+	if err = db.Create(product).Error; err != nil {
+		t.Fatalf("Error inserting product: %v", err)
+	}
+
+	price := model.NewMoney(2450, model.DefaultCurrency)
+	variant := &model.ProductVariant{ProductID: product.ID, SKU: "TS-XL", Price: &price}
+	if err = repo.Create(variant); err != nil {
+		t.Fatalf("Error creating variant: %v", err)
+	}
+
+	created, err := repo.GetByID(product.ID, variant.ID)
+	if err != nil {
+		t.Fatalf("Error getting created variant: %v", err)
+	}
+
+	if created.Price == nil || *created.Price != price {
+		t.Errorf("Expected the variant price to be %s, got %+v", price, created.Price)
+	}
+
+	created.Price = nil
+	if err = repo.Update(created); err != nil {
+		t.Fatalf("Error updating variant: %v", err)
+	}
+
+	updated, err := repo.GetByID(product.ID, variant.ID)
+	if err != nil {
+		t.Fatalf("Error getting updated variant: %v", err)
+	}
+
+	if updated.Price != nil {
+		t.Errorf("Expected the variant to inherit the product price, got %+v", updated.Price)
+	}
+
+	if unitPrice := updated.UnitPrice(product); unitPrice != product.Price {
+		t.Errorf("Expected the unit price to be %s, got %s", product.Price, unitPrice)
+	}
+}
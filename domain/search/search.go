@@ -0,0 +1,178 @@
+// Package search provides the accent folding, trigram similarity and relevance
+// score of product searches.
+//
+// A search is split into folded terms, and every term must match the code,
+// name or description of a product. A term matches a field with the score of
+// its best word: 1 for the same word, 0.75 for a word it is a prefix of, and
+// half the trigram similarity for a word similar enough to be a typo. The
+// score of a term is the best weighted score of the fields, and the score of
+// a product the mean of the scores of the terms.
+package search
+
+import (
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Weights of the fields of a product, so matching the code counts more than
+// matching the description.
+const (
+	CodeWeight        = 1.0
+	NameWeight        = 0.8
+	DescriptionWeight = 0.5
+)
+
+// Scores of the ways a term matches a word.
+const (
+	ExactScore  = 1.0
+	PrefixScore = 0.75
+	FuzzyFactor = 0.5
+)
+
+// FuzzyThreshold is the trigram similarity a word needs to be taken as a
+// misspelling of a term.
+const FuzzyThreshold = 0.4
+
+// MaxTerms is the number of terms of a search that are taken into account.
+const MaxTerms = 8
+
+// ligatures folds the letters that do not decompose into a base letter and
+// accents.
+var ligatures = strings.NewReplacer("ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "đ", "d", "ł", "l", "þ", "th")
+
+// Field is a text of a product and the weight of its matches.
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+// Fold lowercases a text, removes its accents and replaces everything but
+// letters and digits with single spaces, so café, CAFE and cafe fold alike.
+func Fold(text string) string {
+	text = ligatures.Replace(strings.ToLower(text))
+
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn))), text)
+	if err != nil {
+		folded = text
+	}
+
+	return strings.Join(strings.FieldsFunc(folded, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	}), " ")
+}
+
+// Terms returns the distinct folded terms of a search, up to MaxTerms.
+func Terms(query string) []string {
+	terms := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, term := range strings.Fields(Fold(query)) {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+
+		terms = append(terms, term)
+		if len(terms) == MaxTerms {
+			break
+		}
+	}
+
+	return terms
+}
+
+// Trigrams returns the trigrams of the words of a folded text the way pg_trgm
+// does: every word is padded with two spaces before and one after it.
+func Trigrams(text string) map[string]bool {
+	trigrams := make(map[string]bool)
+
+	for _, word := range strings.Fields(text) {
+		padded := "  " + word + " "
+		for i := 0; i+3 <= len(padded); i++ {
+			trigrams[padded[i:i+3]] = true
+		}
+	}
+
+	return trigrams
+}
+
+// Similarity returns the share of trigrams two folded texts have in common,
+// like the similarity function of pg_trgm.
+func Similarity(a, b string) float64 {
+	ta, tb := Trigrams(a), Trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for trigram := range ta {
+		if tb[trigram] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// TermScore returns how well a folded term matches the best word of a folded
+// text, 0 if it does not match any.
+func TermScore(term, text string) float64 {
+	words := strings.Fields(text)
+
+	best := 0.0
+	for _, word := range words {
+		if word == term {
+			return ExactScore
+		}
+
+		if strings.HasPrefix(word, term) {
+			best = PrefixScore
+		}
+	}
+
+	if best > 0 {
+		return best
+	}
+
+	for _, word := range words {
+		if similarity := Similarity(term, word); similarity >= FuzzyThreshold && FuzzyFactor*similarity > best {
+			best = FuzzyFactor * similarity
+		}
+	}
+
+	return best
+}
+
+// Score returns the relevance of the fields of a product to the terms of a
+// search, rounded to four decimals, or 0 if any term matches no field.
+func Score(terms []string, fields ...Field) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+
+	folded := make([]string, len(fields))
+	for i, field := range fields {
+		folded[i] = Fold(field.Text)
+	}
+
+	total := 0.0
+	for _, term := range terms {
+		best := 0.0
+		for i, field := range fields {
+			if score := field.Weight * TermScore(term, folded[i]); score > best {
+				best = score
+			}
+		}
+
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+
+	return math.Round(total/float64(len(terms))*10000) / 10000
+}
@@ -0,0 +1,59 @@
+package search
+
+import "testing"
+
+// Test_Fold tests that texts differing in case, accents and punctuation fold alike.
+func Test_Fold(t *testing.T) {
+	cases := map[string]string{
+		"Café":             "cafe",
+		"  CAFÉ-Molido  ":  "cafe molido",
+		"Piñata Großartig": "pinata grossartig",
+		"SKU_001/Ñandú":    "sku 001 nandu",
+		"¡Qué rico está!":  "que rico esta",
+		"":                 "",
+	}
+
+	for text, expected := range cases {
+		if folded := Fold(text); folded != expected {
+			t.Errorf("Expected %q to fold to %q, got %q", text, expected, folded)
+		}
+	}
+}
+
+// Test_Similarity tests the trigram similarity against values computed by pg_trgm.
+func Test_Similarity(t *testing.T) {
+	if similarity := Similarity("word", "word"); similarity != 1 {
+		t.Errorf("Expected equal words to be fully similar, got %v", similarity)
+	}
+
+	// similarity('word', 'two words') is 0.36363637 in pg_trgm: 4 shared trigrams of 11.
+	if similarity := Similarity("word", "two words"); similarity != 4.0/11 {
+		t.Errorf("Expected a similarity of 4/11, got %v", similarity)
+	}
+
+	if similarity := Similarity("abc", "xyz"); similarity != 0 {
+		t.Errorf("Expected unrelated words not to be similar, got %v", similarity)
+	}
+}
+
+// Test_Score tests that exact words score more than prefixes, and prefixes more than typos.
+func Test_Score(t *testing.T) {
+	terms := Terms("cafe")
+
+	exact := Score(terms, Field{Text: "Café de olla", Weight: NameWeight})
+	prefix := Score(terms, Field{Text: "Cafetera italiana", Weight: NameWeight})
+	typo := Score(Terms("cafw"), Field{Text: "Café de olla", Weight: NameWeight})
+	missing := Score(terms, Field{Text: "Té verde", Weight: NameWeight})
+
+	if !(exact > prefix && prefix > typo && typo > 0) {
+		t.Errorf("Expected exact > prefix > typo > 0, got %v, %v and %v", exact, prefix, typo)
+	}
+
+	if missing != 0 {
+		t.Errorf("Expected an unrelated product not to match, got %v", missing)
+	}
+
+	if score := Score(Terms("cafe verde"), Field{Text: "Café de olla", Weight: NameWeight}); score != 0 {
+		t.Errorf("Expected every term to have to match, got %v", score)
+	}
+}
@@ -0,0 +1,192 @@
+// Package service provides implementations for interacting with category data.
+package service
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/repository"
+	"codifin-challenge/domain/utils"
+	"errors"
+	"fmt"
+)
+
+// CategoryService defines methods for interacting with category data.
+type CategoryService interface {
+	CategoryTree() ([]*model.Category, error)
+	CategoryByID(categoryID uint) (*model.Category, error)
+	CategoriesByIDs(categoryIDs []uint) ([]*model.Category, error)
+	CategoryIDs(categoryID uint, includeDescendants bool) ([]uint, error)
+	CreateCategory(c *model.Category) error
+	UpdateCategory(c *model.Category) error
+	DeleteCategory(categoryID uint) error
+}
+
+// CategoryServiceImpl is an implementation of CategoryService.
+type CategoryServiceImpl struct {
+	categoryRepo repository.CategoryRepository
+}
+
+// NewCategoryService creates a new instance of CategoryServiceImpl.
+func NewCategoryService(repo repository.CategoryRepository) *CategoryServiceImpl {
+	return &CategoryServiceImpl{categoryRepo: repo}
+}
+
+// CategoryTree retrieves every category in a single query and nests them
+// under their parents. It returns the root categories.
+func (s *CategoryServiceImpl) CategoryTree() ([]*model.Category, error) {
+	categories, err := s.categoryRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*model.Category, len(categories))
+	for _, v := range categories {
+		v.Children = make([]*model.Category, 0)
+		byID[v.ID] = v
+	}
+
+	roots := make([]*model.Category, 0)
+	for _, v := range categories {
+		if v.ParentID != nil {
+			if parent, ok := byID[*v.ParentID]; ok {
+				parent.Children = append(parent.Children, v)
+				continue
+			}
+		}
+		roots = append(roots, v)
+	}
+
+	return roots, nil
+}
+
+// CategoryByID retrieves a category with its direct children by its ID.
+func (s *CategoryServiceImpl) CategoryByID(categoryID uint) (*model.Category, error) {
+	return s.categoryRepo.GetByID(categoryID)
+}
+
+// CategoriesByIDs retrieves the categories with the given IDs, failing if any of them does not exist.
+func (s *CategoryServiceImpl) CategoriesByIDs(categoryIDs []uint) ([]*model.Category, error) {
+	unique := make([]uint, 0, len(categoryIDs))
+	seen := make(map[uint]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	categories, err := s.categoryRepo.GetByIDs(unique)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(categories) != len(unique) {
+		return nil, utils.NewError(utils.ErrCategoriesNotFound, fmt.Errorf("categories %v not found", unique))
+	}
+
+	return categories, nil
+}
+
+// CategoryIDs returns the ID of the category and, if requested, the IDs of all its descendants.
+func (s *CategoryServiceImpl) CategoryIDs(categoryID uint, includeDescendants bool) ([]uint, error) {
+	if !includeDescendants {
+		return []uint{categoryID}, nil
+	}
+
+	categories, err := s.categoryRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]uint)
+	found := false
+	for _, v := range categories {
+		if v.ID == categoryID {
+			found = true
+		}
+		if v.ParentID != nil {
+			children[*v.ParentID] = append(children[*v.ParentID], v.ID)
+		}
+	}
+
+	if !found {
+		return nil, utils.NewError(utils.ErrCategoryNotFound, fmt.Errorf("category %d not found", categoryID))
+	}
+
+	ids := []uint{categoryID}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+
+	return ids, nil
+}
+
+// CreateCategory creates a new category.
+func (s *CategoryServiceImpl) CreateCategory(c *model.Category) error {
+	if err := s.validateParent(c); err != nil {
+		return err
+	}
+
+	return s.categoryRepo.Create(c)
+}
+
+// UpdateCategory updates the name and parent of an existing category.
+func (s *CategoryServiceImpl) UpdateCategory(c *model.Category) error {
+	category, err := s.categoryRepo.GetByID(c.ID)
+	if err != nil {
+		return err
+	}
+
+	category.Name = c.Name
+	category.ParentID = c.ParentID
+
+	if err = s.validateParent(category); err != nil {
+		return err
+	}
+
+	if err = s.categoryRepo.Update(category); err != nil {
+		return err
+	}
+
+	*c = *category
+
+	return nil
+}
+
+// DeleteCategory deletes a category by its ID.
+func (s *CategoryServiceImpl) DeleteCategory(categoryID uint) error {
+	if _, err := s.categoryRepo.GetByID(categoryID); err != nil {
+		return err
+	}
+
+	return s.categoryRepo.Delete(categoryID)
+}
+
+// validateParent makes sure the parent of the category exists and is not the
+// category itself or one of its descendants.
+func (s *CategoryServiceImpl) validateParent(c *model.Category) error {
+	if c.ParentID == nil {
+		return nil
+	}
+
+	categories, err := s.categoryRepo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	parents := make(map[uint]*uint, len(categories))
+	for _, v := range categories {
+		parents[v.ID] = v.ParentID
+	}
+
+	if _, ok := parents[*c.ParentID]; !ok {
+		return utils.NewError(utils.ErrParentCategoryNotFound, fmt.Errorf("parent category %d not found", *c.ParentID))
+	}
+
+	for id := c.ParentID; id != nil; id = parents[*id] {
+		if c.ID != 0 && *id == c.ID {
+			return utils.NewError(utils.ErrCategoryCycle, errors.New("category hierarchy cycle"))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+// Package service provides implementations for interacting with order data.
+package service
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/repository"
+	"codifin-challenge/domain/utils"
+	"errors"
+	"fmt"
+)
+
+// OrderService defines methods for interacting with order data.
+type OrderService interface {
+	Checkout(cartID uint) (*model.Order, error)
+	FindOrder(orderID uint) (*model.Order, error)
+	ChangeStatus(orderID uint, status model.OrderStatus) (*model.Order, error)
+}
+
+// OrderServiceImpl is an implementation of OrderService.
+type OrderServiceImpl struct {
+	orderRepo   repository.OrderRepository
+	cartService ShoppingCartService
+}
+
+// NewOrderService creates a new instance of OrderServiceImpl.
+func NewOrderService(repo repository.OrderRepository, cartService ShoppingCartService) *OrderServiceImpl {
+	return &OrderServiceImpl{orderRepo: repo, cartService: cartService}
+}
+
+// Checkout turns a shopping cart into a pending order. Items, unit prices,
+// coupon discounts and taxes are frozen into the order, the coupons are
+// redeemed and the cart is locked against changes, as long as the cart is
+// still at the version the order was built from.
+func (s *OrderServiceImpl) Checkout(cartID uint) (*model.Order, error) {
+	summary, err := s.cartService.CartSummary(cartID, model.DefaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	if summary.Cart.IsLocked() {
+		return nil, utils.NewError(utils.ErrCartLocked, fmt.Errorf("shopping cart %d is checked out", cartID))
+	}
+
+	if summary.ItemCount == 0 {
+		return nil, utils.NewError(utils.ErrCartEmpty, errors.New("shopping cart is empty"))
+	}
+
+	order := &model.Order{
+		ShoppingCartID:   cartID,
+		Status:           model.OrderPending,
+		Lines:            make([]*model.OrderLine, 0, len(summary.Lines)),
+		Discounts:        make([]*model.OrderDiscount, 0, len(summary.Discounts)),
+		Region:           summary.Cart.Region,
+		ItemCount:        summary.ItemCount,
+		Subtotal:         summary.Subtotal,
+		DiscountTotal:    summary.DiscountTotal,
+		TaxTotal:         summary.TaxTotal,
+		Total:            summary.Total,
+		PricesIncludeTax: summary.PricesIncludeTax,
+	}
+
+	for _, line := range summary.Lines {
+		product, variant := line.Item.Product, line.Item.Variant
+		if product == nil || product.IsDeleted() || (line.Item.VariantID != nil && variant == nil) {
+			return nil, utils.NewError(utils.ErrCartProductGone, fmt.Errorf("product %d not found", line.Item.ProductID))
+		}
+
+		orderLine := &model.OrderLine{
+			ProductID: product.ID,
+			Code:      product.Code,
+			Name:      product.Name,
+			UnitPrice: line.UnitPrice,
+			Count:     line.Item.Count,
+			LineTotal: line.LineTotal,
+			Discount:  line.Discount,
+			TaxRate:   line.TaxRate,
+			Tax:       line.Tax,
+		}
+		if variant != nil {
+			orderLine.VariantID = &variant.ID
+			orderLine.SKU = variant.SKU
+		}
+
+		order.Lines = append(order.Lines, orderLine)
+	}
+
+	for _, discount := range summary.Discounts {
+		if discount.Amount.IsZero() {
+			continue
+		}
+
+		order.Discounts = append(order.Discounts, &model.OrderDiscount{
+			PromotionID: discount.Promotion.ID,
+			Code:        discount.Promotion.Code,
+			Amount:      discount.Amount,
+		})
+	}
+
+	if err = s.orderRepo.Checkout(order, summary.Cart.Version); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// FindOrder finds an order by its ID.
+func (s *OrderServiceImpl) FindOrder(orderID uint) (*model.Order, error) {
+	return s.orderRepo.GetByID(orderID)
+}
+
+// ChangeStatus moves an order to a new status, rejecting transitions that are
+// not allowed from its current status.
+func (s *OrderServiceImpl) ChangeStatus(orderID uint, status model.OrderStatus) (*model.Order, error) {
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !order.Status.CanTransitionTo(status) {
+		return nil, utils.NewError(utils.ErrOrderTransitionNotAllowed, fmt.Errorf("illegal order transition from %s to %s", order.Status, status),
+			order.Status, status)
+	}
+
+	if err = s.orderRepo.UpdateStatus(orderID, order.Status, status); err != nil {
+		return nil, err
+	}
+
+	order.Status = status
+
+	return order, nil
+}
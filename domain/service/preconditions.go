@@ -0,0 +1,17 @@
+// Package service provides helpers to check the preconditions of requests.
+package service
+
+import (
+	"codifin-challenge/domain/utils"
+	"fmt"
+)
+
+// checkVersion makes sure a resource is at the version a request expects, if
+// it expects one, so the request does not act on changes it has not seen.
+func checkVersion(current uint, expected *uint) error {
+	if expected == nil || *expected == current {
+		return nil
+	}
+
+	return utils.NewError(utils.ErrVersionConflict, fmt.Errorf("expected version %d, found %d", *expected, current))
+}
@@ -0,0 +1,132 @@
+// Package service provides implementations for interacting with exchange rate and price list data.
+package service
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/repository"
+	"codifin-challenge/domain/utils"
+	"fmt"
+	"strings"
+)
+
+// PriceService defines methods for interacting with exchange rate and price list data.
+type PriceService interface {
+	ExchangeRates() ([]*model.ExchangeRate, error)
+	SetExchangeRate(rate *model.ExchangeRate) error
+	DeleteExchangeRate(currency string) error
+	ProductPrices(productID uint) ([]*model.ProductPrice, error)
+	SetProductPrice(price *model.ProductPrice) error
+	DeleteProductPrice(productID uint, currency string) error
+	PriceBook(currency string, productIDs []uint) (*model.PriceBook, error)
+}
+
+// PriceServiceImpl is an implementation of PriceService.
+type PriceServiceImpl struct {
+	priceRepo   repository.PriceRepository
+	productRepo repository.ProductRepository
+}
+
+// NewPriceService creates a new instance of PriceServiceImpl.
+func NewPriceService(repo repository.PriceRepository, productRepo repository.ProductRepository) *PriceServiceImpl {
+	return &PriceServiceImpl{priceRepo: repo, productRepo: productRepo}
+}
+
+// ExchangeRates retrieves every exchange rate.
+func (s *PriceServiceImpl) ExchangeRates() ([]*model.ExchangeRate, error) {
+	return s.priceRepo.GetExchangeRates()
+}
+
+// SetExchangeRate creates or replaces the exchange rate of a currency other
+// than the default one.
+func (s *PriceServiceImpl) SetExchangeRate(rate *model.ExchangeRate) error {
+	if err := validateForeignCurrency(rate.Currency); err != nil {
+		return err
+	}
+
+	if rate.Rate <= 0 {
+		return utils.NewError(utils.ErrInvalidExchangeRate, fmt.Errorf("invalid exchange rate %v", rate.Rate))
+	}
+
+	return s.priceRepo.SaveExchangeRate(rate)
+}
+
+// DeleteExchangeRate deletes the exchange rate of a currency.
+func (s *PriceServiceImpl) DeleteExchangeRate(currency string) error {
+	return s.priceRepo.DeleteExchangeRate(currency)
+}
+
+// ProductPrices retrieves the list prices of an existing product.
+func (s *PriceServiceImpl) ProductPrices(productID uint) ([]*model.ProductPrice, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+
+	return s.priceRepo.GetProductPrices(productID)
+}
+
+// SetProductPrice creates or replaces the price of an existing product in the
+// price list of a currency other than the default one.
+func (s *PriceServiceImpl) SetProductPrice(price *model.ProductPrice) error {
+	if _, err := s.productRepo.GetByID(price.ProductID); err != nil {
+		return err
+	}
+
+	if err := validateForeignCurrency(price.Currency); err != nil {
+		return err
+	}
+
+	if price.Amount < 0 {
+		return utils.NewError(utils.ErrInvalidPrice, fmt.Errorf("negative list price %d", price.Amount))
+	}
+
+	return s.priceRepo.SaveProductPrice(price)
+}
+
+// DeleteProductPrice deletes the price of a product in a currency.
+func (s *PriceServiceImpl) DeleteProductPrice(productID uint, currency string) error {
+	return s.priceRepo.DeleteProductPrice(productID, currency)
+}
+
+// PriceBook returns the book that prices some products in a currency, the
+// default currency if empty. Other currencies need an exchange rate to price
+// the products that are not in their price list.
+func (s *PriceServiceImpl) PriceBook(currency string, productIDs []uint) (*model.PriceBook, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" || currency == model.DefaultCurrency {
+		return model.NewPriceBook(), nil
+	}
+
+	rate, err := s.priceRepo.GetExchangeRate(currency)
+	if err != nil {
+		if utils.GetCustomError(err).Code == utils.ErrExchangeRateNotFound {
+			return nil, utils.NewError(utils.ErrCurrencyNotAvailable, err, currency)
+		}
+		return nil, err
+	}
+
+	prices, err := s.priceRepo.GetListPrices(currency, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	book := &model.PriceBook{Currency: currency, Rate: rate.Rate, Prices: make(map[uint]model.Money, len(prices))}
+	for _, v := range prices {
+		book.Prices[v.ProductID] = v.Price()
+	}
+
+	return book, nil
+}
+
+// validateForeignCurrency makes sure the currency is an ISO 4217 code other
+// than the default currency, whose prices are the prices of the products.
+func validateForeignCurrency(currency string) error {
+	if !model.IsCurrencyCode(currency) {
+		return utils.NewError(utils.ErrInvalidCurrency, fmt.Errorf("invalid currency %q", currency), currency)
+	}
+
+	if currency == model.DefaultCurrency {
+		return utils.NewError(utils.ErrDefaultCurrencyPrice, fmt.Errorf("default currency %s in price list", currency), model.DefaultCurrency)
+	}
+
+	return nil
+}
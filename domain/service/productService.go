@@ -2,110 +2,533 @@
 package service
 
 import (
+	"codifin-challenge/domain/i18n"
 	"codifin-challenge/domain/model"
 	"codifin-challenge/domain/repository"
+	"codifin-challenge/domain/search"
 	"codifin-challenge/domain/utils"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // ProductService defines methods for interacting with product data.
 type ProductService interface {
-	ProductsList(page, pageSize int, searchTerm, orderBy string, ascending bool) ([]*model.Product, uint, error)
-	ProductByID(productID uint) (*model.Product, error)
+	ProductsList(filter *model.ProductFilter) (*model.ProductList, error)
+	ProductByID(productID uint, currency string, statuses []model.ProductStatus) (*model.Product, error)
+	ProductByCode(code string, currency string, statuses []model.ProductStatus) (*model.Product, error)
 	CreateProduct(p *model.Product) error
-	UpdateProduct(productID uint, updates map[string]interface{}) error
-	DeleteProduct(productID uint) error
+	ImportProducts(source ProductSource, dryRun bool) (*model.ImportReport, error)
+	ExportProducts(filter *model.ProductFilter, fn func([]*model.Product) error) error
+	UpdateProduct(productID uint, patch ProductPatch, version *uint) (*model.Product, error)
+	DeleteProduct(productID uint, version *uint) error
+	ApplySchedule() (published int64, archived int64, err error)
+	DeletedProductsList(page, pageSize int) ([]*model.Product, uint, error)
+	RestoreProduct(productID uint) error
+	PurgeDeletedProducts(olderThanDays int) (int64, error)
+}
+
+// exportBatchSize is the number of products exports read at a time.
+const exportBatchSize = 500
+
+// ProductSource reads the rows of a product import one at a time. Next
+// returns io.EOF after the last row.
+type ProductSource interface {
+	Next() (*model.ImportRow, error)
+}
+
+// ProductPatch is a change to a product, such as a JSON Merge Patch or a JSON
+// Patch of its document. Apply returns a copy of the product with the change
+// applied, leaving the product as it is.
+type ProductPatch interface {
+	Apply(p *model.Product) (*model.Product, error)
 }
 
 // ProductServiceImpl is an implementation of ProductService.
 type ProductServiceImpl struct {
-	productRepo repository.ProductRepository
+	productRepo     repository.ProductRepository
+	categoryService CategoryService
+	taxService      TaxService
+	priceService    PriceService
 }
 
 // NewProductService creates a new instance of ProductServiceImpl.
-func NewProductService(repo repository.ProductRepository) *ProductServiceImpl {
-	return &ProductServiceImpl{productRepo: repo}
+func NewProductService(repo repository.ProductRepository, categoryService CategoryService, taxService TaxService, priceService PriceService) *ProductServiceImpl {
+	return &ProductServiceImpl{productRepo: repo, categoryService: categoryService, taxService: taxService, priceService: priceService}
 }
 
-// ProductsList retrieves a list of products with pagination.
-func (s *ProductServiceImpl) ProductsList(page, pageSize int, searchTerm, orderBy string, ascending bool) ([]*model.Product, uint, error) {
-	return s.productRepo.GetList(page, pageSize, searchTerm, orderBy, ascending)
+// ProductsList retrieves a list of products with pagination, priced in the
+// currency of the filter, and the facets of all the products that pass the
+// filter. A category filter matches the category itself and, if requested,
+// all its descendants. Searches are ordered by relevance and other lists by
+// price unless the filter says otherwise.
+func (s *ProductServiceImpl) ProductsList(filter *model.ProductFilter) (*model.ProductList, error) {
+	if err := validatePaging(filter); err != nil {
+		return nil, err
+	}
+
+	if err := s.prepareFilter(filter, model.SortField{Field: "price"}); err != nil {
+		return nil, err
+	}
+
+	list, err := s.page(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.localize(list.Products, filter.Currency); err != nil {
+		return nil, err
+	}
+
+	if list.Facets, err = s.productRepo.GetFacets(filter); err != nil {
+		return nil, err
+	}
+
+	return list, nil
 }
 
-// ProductByID retrieves a product by its ID.
-func (s *ProductServiceImpl) ProductByID(productID uint) (*model.Product, error) {
-	return s.productRepo.GetByID(productID)
+// ExportProducts calls fn with every product that passes the filter, in
+// batches and at their catalog price, as ProductsList filters them. Products
+// are sorted by ID unless the filter says otherwise.
+func (s *ProductServiceImpl) ExportProducts(filter *model.ProductFilter, fn func([]*model.Product) error) error {
+	if err := s.prepareFilter(filter, model.SortField{Field: "id"}); err != nil {
+		return err
+	}
+
+	return s.productRepo.ForEachBatch(filter, exportBatchSize, fn)
 }
 
-// CreateProduct creates a new product.
-func (s *ProductServiceImpl) CreateProduct(p *model.Product) error {
-	return s.productRepo.Create(p)
+// prepareFilter validates a filter, sorts it by a default field when it is
+// neither sorted nor a search, and resolves its categories.
+func (s *ProductServiceImpl) prepareFilter(filter *model.ProductFilter, defaultSort model.SortField) error {
+	if err := validateFilter(filter); err != nil {
+		return err
+	}
+
+	if len(filter.Sort) == 0 && len(search.Terms(filter.SearchTerm)) == 0 {
+		filter.Sort = []model.SortField{defaultSort}
+	}
+
+	if filter.CategoryID != 0 {
+		categoryIDs, err := s.categoryService.CategoryIDs(filter.CategoryID, filter.IncludeDescendants)
+		if err != nil {
+			return err
+		}
+		filter.CategoryIDs = categoryIDs
+	}
+
+	return nil
 }
 
-// DeleteProduct deletes a product by its ID.
-func (s *ProductServiceImpl) DeleteProduct(productID uint) error {
-	return s.productRepo.Delete(productID)
+// page retrieves the page of products of the filter, by cursor when it has no
+// page number.
+func (s *ProductServiceImpl) page(filter *model.ProductFilter) (*model.ProductList, error) {
+	if filter.Page == 0 {
+		return s.productRepo.GetListByCursor(filter)
+	}
+
+	products, total, err := s.productRepo.GetList(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ProductList{Products: products, Total: total}, nil
+}
+
+// validatePaging makes sure the filter pages products either by page or by
+// cursor.
+func validatePaging(filter *model.ProductFilter) error {
+	if filter.Page < 0 || filter.PageSize < 1 {
+		return utils.NewError(utils.ErrInvalidPage, fmt.Errorf("invalid page %d of size %d", filter.Page, filter.PageSize))
+	}
+
+	if filter.Page > 0 && filter.Cursor != nil {
+		return utils.NewError(utils.ErrPageWithCursor, fmt.Errorf("page %d requested with a cursor", filter.Page))
+	}
+
+	return nil
+}
+
+// validateFilter makes sure the price bounds are catalog amounts and the
+// bounds of the filter do not exclude every product.
+func validateFilter(filter *model.ProductFilter) error {
+	for _, bound := range []*model.Money{filter.MinPrice, filter.MaxPrice} {
+		if bound != nil && !isCatalogAmount(*bound) {
+			return utils.NewError(utils.ErrInvalidPriceFilter, fmt.Errorf("invalid price bound %s %s", bound, bound.Currency), model.DefaultCurrency)
+		}
+	}
+
+	if filter.MinPrice != nil && filter.MaxPrice != nil && filter.MinPrice.Amount > filter.MaxPrice.Amount {
+		return utils.NewError(utils.ErrPriceRangeInverted, fmt.Errorf("min price %s above max price %s", filter.MinPrice, filter.MaxPrice))
+	}
+
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return utils.NewError(utils.ErrDateRangeInverted, fmt.Errorf("created after %s is not before %s", filter.CreatedAfter, filter.CreatedBefore))
+	}
+
+	return nil
 }
 
-// UpdateProduct updates an existing product.
-func (s *ProductServiceImpl) UpdateProduct(productID uint, updates map[string]interface{}) error {
+// ProductByID retrieves a product by its ID priced in a currency, the default
+// currency if empty. Products with none of the statuses given are not found,
+// unless no status is given.
+func (s *ProductServiceImpl) ProductByID(productID uint, currency string, statuses []model.ProductStatus) (*model.Product, error) {
 	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasStatus(product, statuses) {
+		return nil, utils.NewError(utils.ErrProductNotFound, fmt.Errorf("product %d is %s", productID, product.Status))
+	}
+
+	if err = s.localize([]*model.Product{product}, currency); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// ProductByCode retrieves the product with a code priced in a currency, the
+// default currency if empty. Products with none of the statuses given are not
+// found, unless no status is given.
+func (s *ProductServiceImpl) ProductByCode(code string, currency string, statuses []model.ProductStatus) (*model.Product, error) {
+	product, err := s.productRepo.GetByCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasStatus(product, statuses) {
+		return nil, utils.NewError(utils.ErrProductCodeNotFound, fmt.Errorf("product %s is %s", code, product.Status))
+	}
+
+	if err = s.localize([]*model.Product{product}, currency); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// hasStatus tells whether a product has any of the statuses, which every
+// product has if none is given.
+func hasStatus(p *model.Product, statuses []model.ProductStatus) bool {
+	if len(statuses) == 0 {
+		return true
+	}
+
+	for _, v := range statuses {
+		if p.Status == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// localize prices the products and their variants in a currency.
+func (s *ProductServiceImpl) localize(products []*model.Product, currency string) error {
+	ids := make([]uint, 0, len(products))
+	for _, v := range products {
+		ids = append(ids, v.ID)
+	}
+
+	book, err := s.priceService.PriceBook(currency, ids)
 	if err != nil {
 		return err
 	}
 
-	for field, value := range updates {
-		if err = s.assignUpdates(product, field, value); err != nil {
-			return err
+	for _, v := range products {
+		book.Localize(v)
+	}
+
+	return nil
+}
+
+// CreateProduct creates a new product assigned to existing categories. Products
+// without a tax category are taxed at the standard rate.
+func (s *ProductServiceImpl) CreateProduct(p *model.Product) error {
+	if err := s.prepareProduct(p); err != nil {
+		return err
+	}
+
+	return s.productRepo.Create(p)
+}
+
+// prepareProduct validates the price, tax category and status of a product,
+// taxing it at the standard rate without a tax category, and loads its
+// categories. Products without a status are active, or drafts when their
+// publication is scheduled for later.
+func (s *ProductServiceImpl) prepareProduct(p *model.Product) error {
+	if p.Status == "" {
+		p.Status = model.ProductActive
+		if p.PublishAt != nil && p.PublishAt.After(time.Now()) {
+			p.Status = model.ProductDraft
+		}
+	}
+
+	if err := validateStatus(p); err != nil {
+		return err
+	}
+
+	p.Price = p.Price.OrDefaultCurrency()
+	if !isCatalogAmount(p.Price) {
+		return utils.NewError(utils.ErrInvalidProductPrice, fmt.Errorf("invalid product price %s %s", p.Price, p.Price.Currency), model.DefaultCurrency)
+	}
+
+	if p.TaxCategory == "" {
+		p.TaxCategory = model.TaxStandard
+	}
+
+	if err := s.taxService.ValidateTaxCategory(p.TaxCategory); err != nil {
+		return err
+	}
+
+	return s.loadCategories(p)
+}
+
+// validateStatus makes sure a product has one of the product statuses and is
+// not scheduled to be withdrawn before it is published.
+func validateStatus(p *model.Product) error {
+	if !p.Status.IsValid() {
+		statuses := make([]string, 0, len(model.ProductStatuses))
+		for _, v := range model.ProductStatuses {
+			statuses = append(statuses, string(v))
 		}
+
+		return utils.NewError(utils.ErrInvalidProductStatus, fmt.Errorf("invalid product status %q", p.Status), p.Status, strings.Join(statuses, ", "))
 	}
 
-	return s.productRepo.Update(product)
+	if p.PublishAt != nil && p.UnpublishAt != nil && !p.UnpublishAt.After(*p.PublishAt) {
+		return utils.NewError(utils.ErrInvalidPublishWindow, fmt.Errorf("unpublish at %s is not after publish at %s", p.UnpublishAt, p.PublishAt))
+	}
+
+	return nil
 }
 
-// assignUpdates assign and validate updates the fields of a product.
-func (s *ProductServiceImpl) assignUpdates(product *model.Product, field string, value interface{}) error {
-	typeMsg := fmt.Sprintf("Field: %s, Value type: %T, Value: %v\n", field, value, value)
-	var err error
-	var message string
-	switch field {
-	case "code":
-		if v, ok := value.(string); ok {
-			product.Code = v
-		} else {
-			message = fmt.Sprintf("El valor para el codigo de producto es invalido")
-			err = fmt.Errorf("invalid type for 'code' field: %s", typeMsg)
+// ImportProducts creates or updates, by code, the products of the rows of a
+// source, validated as CreateProduct does. Rows that fail validation are
+// rejected and the import goes on; internal errors stop it, keeping the rows
+// already imported. A dry run validates every row without saving any.
+func (s *ProductServiceImpl) ImportProducts(source ProductSource, dryRun bool) (*model.ImportReport, error) {
+	report := &model.ImportReport{DryRun: dryRun, Rows: make([]*model.ImportResult, 0)}
+	imported := make(map[string]int)
+
+	for {
+		row, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return report, nil
 		}
-	case "name":
-		if v, ok := value.(string); ok {
-			product.Name = v
-		} else {
-			message = fmt.Sprintf("El valor para el nombre de producto es invalido")
-			err = fmt.Errorf("invalid type for 'name' field: %s", typeMsg)
+		if err != nil {
+			return nil, err
 		}
-	case "price":
-		if v, ok := value.(float64); ok {
-			product.Price = v
-		} else {
-			message = fmt.Sprintf("El valor para el precio del producto es invalido")
-			err = fmt.Errorf("invalid type for 'price', %s", typeMsg)
+
+		result, err := s.importRow(row, imported, dryRun)
+		if err != nil {
+			return nil, err
 		}
-	case "imageURL":
-		if v, ok := value.(string); ok {
-			product.ImageURL = v
+		report.Add(result)
+	}
+}
+
+// importRow imports the product of a row unless its code was already imported
+// by a previous row, as recorded in imported by the line of that row.
+func (s *ProductServiceImpl) importRow(row *model.ImportRow, imported map[string]int, dryRun bool) (*model.ImportResult, error) {
+	result := &model.ImportResult{Line: row.Line, Status: model.ImportRejected}
+	if row.Err != nil {
+		return rejectRow(result, row.Err)
+	}
+
+	p := row.Product
+	result.Code = p.Code
+
+	if p.Code == "" {
+		result.Reason = i18n.NewMessage("IMPORT_CODE_REQUIRED")
+		return result, nil
+	}
+
+	if line, ok := imported[p.Code]; ok {
+		result.Reason = i18n.NewMessage("IMPORT_DUPLICATE_CODE", p.Code, line)
+		return result, nil
+	}
+
+	existing, err := s.productRepo.GetByCode(p.Code)
+	if err != nil && utils.GetCustomError(err).Code != utils.ErrProductCodeNotFound {
+		return nil, err
+	}
+
+	if existing != nil {
+		mergeImport(existing, p, row.Fields)
+		p = existing
+		result.Status = model.ImportUpdated
+	} else {
+		result.Status = model.ImportCreated
+	}
+
+	if err = s.prepareProduct(p); err != nil {
+		return rejectRow(result, err)
+	}
+
+	if !dryRun {
+		if existing != nil {
+			err = s.productRepo.Update(p)
 		} else {
-			message = fmt.Sprintf("El valor para la imagen de producto es invalido")
-			err = fmt.Errorf("invalid type for 'imageURL' field: %s", typeMsg)
+			err = s.productRepo.Create(p)
+		}
+		if err != nil {
+			return rejectRow(result, err)
+		}
+	}
+
+	result.ProductID = p.ID
+	imported[p.Code] = row.Line
+
+	return result, nil
+}
+
+// rejectRow rejects a row for an error of its data, or returns the error if
+// it is an internal one.
+func rejectRow(result *model.ImportResult, err error) (*model.ImportResult, error) {
+	customErr := utils.GetCustomError(err)
+	if customErr.Status >= http.StatusInternalServerError {
+		return nil, err
+	}
+
+	result.Status = model.ImportRejected
+	result.Reason = customErr
+
+	return result, nil
+}
+
+// mergeImport assigns to a product the fields an imported row has.
+func mergeImport(product, imported *model.Product, fields map[string]bool) {
+	for field := range fields {
+		switch field {
+		case "name":
+			product.Name = imported.Name
+		case "description":
+			product.Description = imported.Description
+		case "price":
+			product.Price = imported.Price
+		case "imageURL":
+			product.ImageURL = imported.ImageURL
+		case "taxCategory":
+			product.TaxCategory = imported.TaxCategory
+		case "stock":
+			product.Stock = imported.Stock
+		case "categoryIDs":
+			product.Categories = imported.Categories
+		case "status":
+			product.Status = imported.Status
+		case "publishAt":
+			product.PublishAt = imported.PublishAt
+		case "unpublishAt":
+			product.UnpublishAt = imported.UnpublishAt
+		}
+	}
+}
+
+// DeleteProduct deletes a product by its ID, if it is at the version given.
+// The version is checked by the delete itself, so a change made after it was
+// read is not deleted over.
+func (s *ProductServiceImpl) DeleteProduct(productID uint, version *uint) error {
+	if version != nil {
+		product, err := s.productRepo.GetByID(productID)
+		if err != nil {
+			return err
+		}
+
+		if err = checkVersion(product.Version, version); err != nil {
+			return err
 		}
-	default:
-		message = fmt.Sprintf("El campo %s no existe en el modelo producto", field)
-		err = fmt.Errorf("'%s' field doesn't exist in product model", field)
 	}
 
+	return s.productRepo.Delete(productID, version)
+}
+
+// DeletedProductsList retrieves a page of the deleted products, the most
+// recently deleted first.
+func (s *ProductServiceImpl) DeletedProductsList(page, pageSize int) ([]*model.Product, uint, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, 0, utils.NewError(utils.ErrInvalidPage, fmt.Errorf("invalid page %d of size %d", page, pageSize))
+	}
+
+	return s.productRepo.GetDeletedList(page, pageSize)
+}
+
+// RestoreProduct undoes the deletion of a product.
+func (s *ProductServiceImpl) RestoreProduct(productID uint) error {
+	return s.productRepo.Restore(productID)
+}
+
+// PurgeDeletedProducts permanently deletes the products deleted more than a
+// number of days ago, and reports how many were purged.
+func (s *ProductServiceImpl) PurgeDeletedProducts(olderThanDays int) (int64, error) {
+	if olderThanDays < 0 {
+		return 0, utils.NewError(utils.ErrNegativePurgeAge, fmt.Errorf("invalid purge age of %d days", olderThanDays))
+	}
+
+	return s.productRepo.Purge(time.Now().AddDate(0, 0, -olderThanDays))
+}
+
+// ApplySchedule activates and archives the products whose scheduled
+// publication or withdrawal is due.
+func (s *ProductServiceImpl) ApplySchedule() (int64, int64, error) {
+	return s.productRepo.ApplySchedule(time.Now())
+}
+
+// UpdateProduct applies a patch to an existing product, if it is at the
+// version given, validates the result as CreateProduct does and returns it at
+// its new version.
+func (s *ProductServiceImpl) UpdateProduct(productID uint, patch ProductPatch, version *uint) (*model.Product, error) {
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkVersion(product.Version, version); err != nil {
+		return nil, err
+	}
+
+	patched, err := patch.Apply(product)
 	if err != nil {
-		return utils.ToUserError(http.StatusBadRequest, message, err)
+		return nil, err
+	}
+
+	if err = s.prepareProduct(patched); err != nil {
+		return nil, err
+	}
+
+	if err = s.productRepo.Update(patched); err != nil {
+		return nil, err
 	}
 
+	return patched, nil
+}
+
+// loadCategories replaces the categories of the product, which may only carry
+// their IDs, with the stored categories, failing if any of them does not exist.
+func (s *ProductServiceImpl) loadCategories(p *model.Product) error {
+	ids := make([]uint, 0, len(p.Categories))
+	for _, v := range p.Categories {
+		ids = append(ids, v.ID)
+	}
+
+	categories, err := s.categoryService.CategoriesByIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	p.Categories = categories
+
 	return nil
 }
+
+// isCatalogAmount reports whether an amount can be a catalog price: not
+// negative and in the default currency. Amounts without a currency are taken
+// as amounts of the default currency.
+func isCatalogAmount(m model.Money) bool {
+	return !m.IsNegative() && (m.Currency == "" || m.Currency == model.DefaultCurrency)
+}
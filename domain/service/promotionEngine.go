@@ -0,0 +1,221 @@
+// Package service provides the evaluation of promotions over shopping carts.
+package service
+
+import (
+	"codifin-challenge/domain/i18n"
+	"codifin-challenge/domain/model"
+	"sort"
+	"time"
+)
+
+// promotionScope holds the products and categories, descendants included, a promotion applies to.
+type promotionScope struct {
+	all        bool
+	products   map[uint]bool
+	categories map[uint]bool
+}
+
+// includes reports whether the product of a cart item is in the scope.
+func (s *promotionScope) includes(product *model.Product) bool {
+	if product == nil {
+		return false
+	}
+	if s.all || s.products[product.ID] {
+		return true
+	}
+	for _, c := range product.Categories {
+		if s.categories[c.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluatePromotion computes the discount a promotion takes off the priced
+// lines of a cart at the given instant. Promotions that do not apply have a
+// zero amount and the reason why.
+func evaluatePromotion(p *model.Promotion, scope *promotionScope, lines []*model.CartLine, at time.Time) *model.CartDiscount {
+	discount := &model.CartDiscount{Promotion: p}
+
+	if !p.IsActive(at) {
+		discount.Reason = i18n.NewMessage("DISCOUNT_COUPON_INACTIVE")
+		return discount
+	}
+
+	if p.IsExhausted() {
+		discount.Reason = i18n.NewMessage("DISCOUNT_COUPON_EXHAUSTED")
+		return discount
+	}
+
+	eligible := make([]*model.CartLine, 0, len(lines))
+	subtotal := model.NewMoney(0, p.Threshold.Currency)
+	for _, line := range lines {
+		if p.Type == model.PromotionFreeItemOver && p.FreeProductID != nil && line.Item.ProductID == *p.FreeProductID {
+			continue
+		}
+		if scope.includes(line.Item.Product) {
+			eligible = append(eligible, line)
+			subtotal = subtotal.Add(line.LineTotal)
+		}
+	}
+
+	if len(eligible) == 0 {
+		discount.Reason = i18n.NewMessage("DISCOUNT_NO_ELIGIBLE_PRODUCTS")
+		return discount
+	}
+
+	if subtotal.Cmp(p.Threshold) < 0 {
+		discount.Reason = i18n.NewMessage("DISCOUNT_BELOW_THRESHOLD", p.Threshold, p.Threshold.Currency)
+		return discount
+	}
+
+	switch p.Type {
+	case model.PromotionPercentage:
+		discount.Amount, discount.Lines = percentageDiscount(p, eligible, subtotal)
+	case model.PromotionFixedAmount:
+		discount.Amount, discount.Lines = p.AmountOff.Min(subtotal), eligible
+	case model.PromotionBuyXGetY:
+		discount.Amount, discount.Lines = buyXGetYDiscount(p, eligible)
+		if discount.Amount.IsZero() {
+			discount.Reason = i18n.NewMessage("DISCOUNT_MISSING_QUANTITY", p.BuyQuantity+p.GetQuantity)
+		}
+	case model.PromotionFreeItemOver:
+		discount.Amount, discount.Lines = freeItemDiscount(p, lines)
+		if discount.Amount.IsZero() {
+			discount.Reason = i18n.NewMessage("DISCOUNT_MISSING_FREE_PRODUCT")
+		}
+	}
+
+	return discount
+}
+
+// percentageDiscount takes the percentage off the eligible lines, rounded as
+// Money.MulRate does. With a per cart limit only the most expensive units up
+// to the limit are discounted.
+func percentageDiscount(p *model.Promotion, eligible []*model.CartLine, subtotal model.Money) (model.Money, []*model.CartLine) {
+	if p.PerCartLimit == 0 {
+		return subtotal.MulRate(p.Value / 100), eligible
+	}
+
+	sorted := sortedLines(eligible)
+	for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+		sorted[i], sorted[j] = sorted[j], sorted[i]
+	}
+
+	base, touched := firstUnits(sorted, p.PerCartLimit)
+
+	return base.MulRate(p.Value / 100), touched
+}
+
+// buyXGetYDiscount makes the cheapest units free: for every BuyQuantity plus
+// GetQuantity eligible units, GetQuantity of them cost nothing.
+func buyXGetYDiscount(p *model.Promotion, eligible []*model.CartLine) (model.Money, []*model.CartLine) {
+	sorted := sortedLines(eligible)
+
+	total := uint(0)
+	for _, line := range sorted {
+		total += line.Item.Count
+	}
+
+	free := total / (p.BuyQuantity + p.GetQuantity) * p.GetQuantity
+	if p.PerCartLimit > 0 && free > p.PerCartLimit {
+		free = p.PerCartLimit
+	}
+
+	return firstUnits(sorted, free)
+}
+
+// freeItemDiscount makes GetQuantity units of the free product cost nothing,
+// one unit if GetQuantity is not set. The free product has to be in the cart.
+func freeItemDiscount(p *model.Promotion, lines []*model.CartLine) (model.Money, []*model.CartLine) {
+	gift := make([]*model.CartLine, 0)
+	for _, line := range lines {
+		if p.FreeProductID != nil && line.Item.ProductID == *p.FreeProductID {
+			gift = append(gift, line)
+		}
+	}
+
+	free := p.GetQuantity
+	if free == 0 {
+		free = 1
+	}
+	if p.PerCartLimit > 0 && free > p.PerCartLimit {
+		free = p.PerCartLimit
+	}
+
+	return firstUnits(sortedLines(gift), free)
+}
+
+// sortedLines copies the lines ordered by unit price, cheapest first, so rules
+// limited to some units can pick the cheapest or most expensive ones.
+func sortedLines(lines []*model.CartLine) []*model.CartLine {
+	sorted := make([]*model.CartLine, len(lines))
+	copy(sorted, lines)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].UnitPrice.Amount < sorted[j].UnitPrice.Amount
+	})
+
+	return sorted
+}
+
+// firstUnits adds up the price of the first n units of the lines and returns
+// the lines those units belong to.
+func firstUnits(lines []*model.CartLine, n uint) (model.Money, []*model.CartLine) {
+	amount := model.Money{}
+	touched := make([]*model.CartLine, 0)
+	for _, line := range lines {
+		if n == 0 {
+			break
+		}
+		count := line.Item.Count
+		if count > n {
+			count = n
+		}
+		amount = amount.Add(line.UnitPrice.Mul(count))
+		touched = append(touched, line)
+		n -= count
+	}
+	return amount, touched
+}
+
+// allocateDiscount spreads a discount over its lines in proportion to their
+// totals with Money.Allocate, so the shares add up to the discount.
+func allocateDiscount(discount *model.CartDiscount) {
+	if discount.Amount.IsZero() {
+		return
+	}
+
+	weights := make([]int64, 0, len(discount.Lines))
+	for _, line := range discount.Lines {
+		weights = append(weights, line.LineTotal.Amount)
+	}
+
+	for i, share := range discount.Amount.Allocate(weights) {
+		discount.Lines[i].Discount = discount.Lines[i].Discount.Add(share)
+	}
+}
+
+// applyDiscounts evaluates the coupons of the summarized cart, spreads their
+// amounts over the discounted lines and lowers the total. The discounts
+// together never exceed the subtotal.
+func applyDiscounts(summary *model.CartSummary, scopes map[uint]*promotionScope, at time.Time) {
+	summary.Discounts = make([]*model.CartDiscount, 0, len(summary.Cart.Coupons))
+
+	remaining := summary.Subtotal
+	for _, coupon := range summary.Cart.Coupons {
+		if coupon.Promotion == nil {
+			continue
+		}
+
+		discount := evaluatePromotion(coupon.Promotion, scopes[coupon.PromotionID], summary.Lines, at)
+		discount.Amount = discount.Amount.Min(remaining)
+		remaining = remaining.Sub(discount.Amount)
+		allocateDiscount(discount)
+
+		summary.Discounts = append(summary.Discounts, discount)
+		summary.DiscountTotal = summary.DiscountTotal.Add(discount.Amount)
+	}
+
+	summary.Total = summary.Subtotal.Sub(summary.DiscountTotal)
+}
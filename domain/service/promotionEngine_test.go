@@ -0,0 +1,324 @@
+package service
+
+import (
+	"codifin-challenge/domain/i18n"
+	"codifin-challenge/domain/model"
+	"testing"
+	"time"
+)
+
+var promotionTime = time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+func mxn(amount int64) model.Money {
+	return model.NewMoney(amount, model.DefaultCurrency)
+}
+
+// promotionLines returns the lines of a cart with 2 units of product 1 at
+// 10.00, 3 of product 2, in category 7, at 5.00 and 1 of product 3 at 3.33,
+// 38.33 in all.
+func promotionLines() []*model.CartLine {
+	line := func(productID uint, unitPrice int64, count uint, categories ...*model.Category) *model.CartLine {
+		product := &model.Product{Categories: categories}
+		product.ID = productID
+		return &model.CartLine{
+			Item:      &model.ItemCart{ProductID: productID, Product: product, Count: count},
+			UnitPrice: mxn(unitPrice),
+			LineTotal: mxn(unitPrice).Mul(count),
+		}
+	}
+
+	category := &model.Category{}
+	category.ID = 7
+
+	return []*model.CartLine{line(1, 1000, 2), line(2, 500, 3, category), line(3, 333, 1)}
+}
+
+func reasonKey(reason i18n.Localizable) string {
+	if message, ok := reason.(*i18n.Message); ok {
+		return message.Key
+	}
+	return ""
+}
+
+func timeAt(d time.Duration) *time.Time {
+	at := promotionTime.Add(d)
+	return &at
+}
+
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+// Test_applyDiscounts tests the discount of every promotion rule, its conditions and limits, and how the discounts of
+// stacked coupons are capped and spread over the lines.
+func Test_applyDiscounts(t *testing.T) {
+	all := &promotionScope{all: true}
+	onlyProduct := func(id uint) *promotionScope {
+		return &promotionScope{products: map[uint]bool{id: true}}
+	}
+	category := &promotionScope{categories: map[uint]bool{7: true}}
+
+	cases := []struct {
+		name       string
+		promotions []*model.Promotion
+		scopes     []*promotionScope
+		amounts    []int64
+		reasons    []string
+		lines      [3]int64
+	}{
+		{
+			name:       "percentage spread in proportion to the lines",
+			promotions: []*model.Promotion{{Type: model.PromotionPercentage, Value: 10}},
+			scopes:     []*promotionScope{all},
+			amounts:    []int64{383},
+			reasons:    []string{""},
+			lines:      [3]int64{200, 150, 33},
+		},
+		{
+			name:       "percentage on the most expensive units up to the per cart limit",
+			promotions: []*model.Promotion{{Type: model.PromotionPercentage, Value: 50, PerCartLimit: 2}},
+			scopes:     []*promotionScope{all},
+			amounts:    []int64{1000},
+			reasons:    []string{""},
+			lines:      [3]int64{1000, 0, 0},
+		},
+		{
+			name:       "fixed amount on the products of its scope",
+			promotions: []*model.Promotion{{Type: model.PromotionFixedAmount, AmountOff: mxn(500)}},
+			scopes:     []*promotionScope{onlyProduct(2)},
+			amounts:    []int64{500},
+			reasons:    []string{""},
+			lines:      [3]int64{0, 500, 0},
+		},
+		{
+			name:       "fixed amount capped at the eligible subtotal",
+			promotions: []*model.Promotion{{Type: model.PromotionFixedAmount, AmountOff: mxn(5000)}},
+			scopes:     []*promotionScope{all},
+			amounts:    []int64{3833},
+			reasons:    []string{""},
+			lines:      [3]int64{2000, 1500, 333},
+		},
+		{
+			name:       "buy x get y makes the cheapest units free",
+			promotions: []*model.Promotion{{Type: model.PromotionBuyXGetY, BuyQuantity: 2, GetQuantity: 1}},
+			scopes:     []*promotionScope{all},
+			amounts:    []int64{833},
+			reasons:    []string{""},
+			lines:      [3]int64{0, 682, 151},
+		},
+		{
+			name:       "buy x get y up to the per cart limit",
+			promotions: []*model.Promotion{{Type: model.PromotionBuyXGetY, BuyQuantity: 2, GetQuantity: 1, PerCartLimit: 1}},
+			scopes:     []*promotionScope{all},
+			amounts:    []int64{333},
+			reasons:    []string{""},
+			lines:      [3]int64{0, 0, 333},
+		},
+		{
+			name:       "buy x get y without enough units",
+			promotions: []*model.Promotion{{Type: model.PromotionBuyXGetY, BuyQuantity: 5, GetQuantity: 2}},
+			scopes:     []*promotionScope{all},
+			amounts:    []int64{0},
+			reasons:    []string{"DISCOUNT_MISSING_QUANTITY"},
+		},
+		{
+			name: "free item over a threshold the free product does not count towards",
+			promotions: []*model.Promotion{{Type: model.PromotionFreeItemOver, FreeProductID: uintPtr(3),
+				Threshold: mxn(3500)}},
+			scopes:  []*promotionScope{all},
+			amounts: []int64{333},
+			reasons: []string{""},
+			lines:   [3]int64{0, 0, 333},
+		},
+		{
+			name: "free item below the threshold",
+			promotions: []*model.Promotion{{Type: model.PromotionFreeItemOver, FreeProductID: uintPtr(3),
+				Threshold: mxn(3501)}},
+			scopes:  []*promotionScope{all},
+			amounts: []int64{0},
+			reasons: []string{"DISCOUNT_BELOW_THRESHOLD"},
+		},
+		{
+			name:       "free item not in the cart",
+			promotions: []*model.Promotion{{Type: model.PromotionFreeItemOver, FreeProductID: uintPtr(9)}},
+			scopes:     []*promotionScope{all},
+			amounts:    []int64{0},
+			reasons:    []string{"DISCOUNT_MISSING_FREE_PRODUCT"},
+		},
+		{
+			name:       "threshold met exactly by the lines of a category",
+			promotions: []*model.Promotion{{Type: model.PromotionPercentage, Value: 10, Threshold: mxn(1500)}},
+			scopes:     []*promotionScope{category},
+			amounts:    []int64{150},
+			reasons:    []string{""},
+			lines:      [3]int64{0, 150, 0},
+		},
+		{
+			name:       "threshold not met by the lines of a category",
+			promotions: []*model.Promotion{{Type: model.PromotionPercentage, Value: 10, Threshold: mxn(1501)}},
+			scopes:     []*promotionScope{category},
+			amounts:    []int64{0},
+			reasons:    []string{"DISCOUNT_BELOW_THRESHOLD"},
+		},
+		{
+			name:       "no eligible products",
+			promotions: []*model.Promotion{{Type: model.PromotionPercentage, Value: 10}},
+			scopes:     []*promotionScope{onlyProduct(9)},
+			amounts:    []int64{0},
+			reasons:    []string{"DISCOUNT_NO_ELIGIBLE_PRODUCTS"},
+		},
+		{
+			name:       "usage limit reached",
+			promotions: []*model.Promotion{{Type: model.PromotionPercentage, Value: 10, UsageLimit: 5, UsageCount: 5}},
+			scopes:     []*promotionScope{all},
+			amounts:    []int64{0},
+			reasons:    []string{"DISCOUNT_COUPON_EXHAUSTED"},
+		},
+		{
+			name:       "usage limit not reached",
+			promotions: []*model.Promotion{{Type: model.PromotionFixedAmount, AmountOff: mxn(100), UsageLimit: 5, UsageCount: 4}},
+			scopes:     []*promotionScope{onlyProduct(1)},
+			amounts:    []int64{100},
+			reasons:    []string{""},
+			lines:      [3]int64{100, 0, 0},
+		},
+		{
+			name: "validity window",
+			promotions: []*model.Promotion{
+				{Type: model.PromotionFixedAmount, AmountOff: mxn(100), StartsAt: timeAt(time.Second)},
+				{Type: model.PromotionFixedAmount, AmountOff: mxn(100), EndsAt: timeAt(0)},
+				{Type: model.PromotionFixedAmount, AmountOff: mxn(100), StartsAt: timeAt(0), EndsAt: timeAt(time.Second)},
+			},
+			scopes:  []*promotionScope{all, all, onlyProduct(1)},
+			amounts: []int64{0, 0, 100},
+			reasons: []string{"DISCOUNT_COUPON_INACTIVE", "DISCOUNT_COUPON_INACTIVE", ""},
+			lines:   [3]int64{100, 0, 0},
+		},
+		{
+			name: "stacked coupons never exceed the subtotal",
+			promotions: []*model.Promotion{
+				{Type: model.PromotionPercentage, Value: 10},
+				{Type: model.PromotionFixedAmount, AmountOff: mxn(3500)},
+			},
+			scopes:  []*promotionScope{all, all},
+			amounts: []int64{383, 3450},
+			reasons: []string{"", ""},
+			lines:   [3]int64{2000, 1500, 333},
+		},
+	}
+
+	for _, v := range cases {
+		lines := promotionLines()
+		summary := &model.CartSummary{Cart: &model.ShoppingCart{}, Lines: lines, Subtotal: mxn(3833)}
+
+		scopes := make(map[uint]*promotionScope)
+		for i, p := range v.promotions {
+			p.ID = uint(i + 1)
+			summary.Cart.Coupons = append(summary.Cart.Coupons, &model.CartCoupon{PromotionID: p.ID, Promotion: p})
+			scopes[p.ID] = v.scopes[i]
+		}
+
+		applyDiscounts(summary, scopes, promotionTime)
+
+		if len(summary.Discounts) != len(v.amounts) {
+			t.Fatalf("%s: expected %d discounts, got %d", v.name, len(v.amounts), len(summary.Discounts))
+		}
+
+		total := int64(0)
+		for i, discount := range summary.Discounts {
+			if discount.Amount.Amount != v.amounts[i] || reasonKey(discount.Reason) != v.reasons[i] {
+				t.Errorf("%s: expected discount %d to be %d (%q), got %d (%q)", v.name, i, v.amounts[i], v.reasons[i],
+					discount.Amount.Amount, reasonKey(discount.Reason))
+			}
+			total += v.amounts[i]
+		}
+
+		for i, line := range lines {
+			if line.Discount.Amount != v.lines[i] {
+				t.Errorf("%s: expected line %d to be discounted %d, got %d", v.name, i, v.lines[i], line.Discount.Amount)
+			}
+		}
+
+		if summary.DiscountTotal.Amount != total || summary.Total.Amount != 3833-total {
+			t.Errorf("%s: expected a discount of %d and a total of %d, got %v and %v", v.name, total, 3833-total,
+				summary.DiscountTotal, summary.Total)
+		}
+	}
+}
+
+// Test_applyDiscountsWithoutPromotion tests that coupons whose promotion is not loaded are skipped.
+func Test_applyDiscountsWithoutPromotion(t *testing.T) {
+	summary := &model.CartSummary{
+		Cart:     &model.ShoppingCart{Coupons: []*model.CartCoupon{{PromotionID: 1}}},
+		Lines:    promotionLines(),
+		Subtotal: mxn(3833),
+	}
+
+	applyDiscounts(summary, map[uint]*promotionScope{}, promotionTime)
+
+	if len(summary.Discounts) != 0 || summary.Total.Amount != 3833 {
+		t.Errorf("Expected no discounts, got %d and a total of %v", len(summary.Discounts), summary.Total)
+	}
+}
+
+// Test_allocateDiscount tests that the shares of a discount follow the line totals, rounded half away from zero, and
+// that the last line takes the remainder.
+func Test_allocateDiscount(t *testing.T) {
+	cases := []struct {
+		amount   int64
+		totals   []int64
+		expected []int64
+	}{
+		{100, []int64{100, 100, 100}, []int64{33, 33, 34}},
+		{200, []int64{100, 100, 100}, []int64{67, 67, 66}},
+		{1, []int64{50, 50}, []int64{1, 0}},
+		{999, []int64{1000}, []int64{999}},
+		{0, []int64{100, 100}, []int64{0, 0}},
+	}
+
+	for _, v := range cases {
+		discount := &model.CartDiscount{Amount: mxn(v.amount)}
+		for _, total := range v.totals {
+			discount.Lines = append(discount.Lines, &model.CartLine{LineTotal: mxn(total), Discount: mxn(0)})
+		}
+
+		allocateDiscount(discount)
+
+		sum := int64(0)
+		for i, line := range discount.Lines {
+			if line.Discount.Amount != v.expected[i] {
+				t.Errorf("Expected %d over %v to give %v, got %d at %d", v.amount, v.totals, v.expected, line.Discount.Amount, i)
+			}
+			sum += line.Discount.Amount
+		}
+
+		if sum != v.amount {
+			t.Errorf("Expected the shares of %d to add up to it, got %d", v.amount, sum)
+		}
+	}
+}
+
+// Test_firstUnits tests that the first units of the lines are counted across lines and stop at the limit.
+func Test_firstUnits(t *testing.T) {
+	lines := sortedLines(promotionLines())
+
+	cases := []struct {
+		units   uint
+		amount  int64
+		touched int
+	}{
+		{0, 0, 0},
+		{1, 333, 1},
+		{3, 1333, 2},
+		{6, 3833, 3},
+		{10, 3833, 3},
+	}
+
+	for _, v := range cases {
+		amount, touched := firstUnits(lines, v.units)
+		if amount.Amount != v.amount || len(touched) != v.touched {
+			t.Errorf("Expected the first %d units to cost %d over %d lines, got %d over %d", v.units, v.amount, v.touched,
+				amount.Amount, len(touched))
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Package service provides implementations for interacting with promotion data.
+package service
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/repository"
+	"codifin-challenge/domain/utils"
+	"errors"
+	"fmt"
+)
+
+// PromotionService defines methods for interacting with promotion data.
+type PromotionService interface {
+	PromotionsList() ([]*model.Promotion, error)
+	PromotionByID(promotionID uint) (*model.Promotion, error)
+	CreatePromotion(p *model.Promotion) error
+	UpdatePromotion(p *model.Promotion) error
+	DeletePromotion(promotionID uint) error
+}
+
+// PromotionServiceImpl is an implementation of PromotionService.
+type PromotionServiceImpl struct {
+	promotionRepo   repository.PromotionRepository
+	productRepo     repository.ProductRepository
+	categoryService CategoryService
+}
+
+// NewPromotionService creates a new instance of PromotionServiceImpl.
+func NewPromotionService(repo repository.PromotionRepository, productRepo repository.ProductRepository, categoryService CategoryService) *PromotionServiceImpl {
+	return &PromotionServiceImpl{promotionRepo: repo, productRepo: productRepo, categoryService: categoryService}
+}
+
+// PromotionsList retrieves every promotion.
+func (s *PromotionServiceImpl) PromotionsList() ([]*model.Promotion, error) {
+	return s.promotionRepo.GetAll()
+}
+
+// PromotionByID retrieves a promotion by its ID.
+func (s *PromotionServiceImpl) PromotionByID(promotionID uint) (*model.Promotion, error) {
+	return s.promotionRepo.GetByID(promotionID)
+}
+
+// CreatePromotion creates a new promotion scoped to existing products and categories.
+func (s *PromotionServiceImpl) CreatePromotion(p *model.Promotion) error {
+	if err := s.validatePromotion(p); err != nil {
+		return err
+	}
+
+	return s.promotionRepo.Create(p)
+}
+
+// UpdatePromotion replaces the rule, limits and scope of an existing promotion.
+// The usage count of the promotion is kept.
+func (s *PromotionServiceImpl) UpdatePromotion(p *model.Promotion) error {
+	promotion, err := s.promotionRepo.GetByID(p.ID)
+	if err != nil {
+		return err
+	}
+
+	p.CreatedAt = promotion.CreatedAt
+	p.UsageCount = promotion.UsageCount
+
+	if err = s.validatePromotion(p); err != nil {
+		return err
+	}
+
+	return s.promotionRepo.Update(p)
+}
+
+// DeletePromotion deletes a promotion by its ID.
+func (s *PromotionServiceImpl) DeletePromotion(promotionID uint) error {
+	if _, err := s.promotionRepo.GetByID(promotionID); err != nil {
+		return err
+	}
+
+	return s.promotionRepo.Delete(promotionID)
+}
+
+// validatePromotion makes sure the promotion has a unique code, a rule with
+// consistent values and a scope made of existing products and categories.
+func (s *PromotionServiceImpl) validatePromotion(p *model.Promotion) error {
+	if p.Code == "" {
+		return utils.NewError(utils.ErrPromotionCodeRequired, errors.New("empty promotion code"))
+	}
+
+	if err := validateRule(p); err != nil {
+		return err
+	}
+
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return utils.NewError(utils.ErrInvalidPromotionWindow, errors.New("promotion ends before it starts"))
+	}
+
+	exists, err := s.promotionRepo.ExistsCode(p.Code, p.ID)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return utils.NewError(utils.ErrPromotionCodeTaken, fmt.Errorf("duplicated promotion code %s", p.Code), p.Code)
+	}
+
+	if p.FreeProductID != nil {
+		if _, err = s.productRepo.GetByID(*p.FreeProductID); err != nil {
+			return utils.NewError(utils.ErrFreeProductNotFound, err)
+		}
+	}
+
+	for _, v := range p.Products {
+		if _, err = s.productRepo.GetByID(v.ID); err != nil {
+			return utils.NewError(utils.ErrProductsNotFound, err)
+		}
+	}
+
+	if len(p.Categories) > 0 {
+		ids := make([]uint, 0, len(p.Categories))
+		for _, v := range p.Categories {
+			ids = append(ids, v.ID)
+		}
+
+		p.Categories, err = s.categoryService.CategoriesByIDs(ids)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRule checks the values each promotion type needs.
+func validateRule(p *model.Promotion) error {
+	invalid := func(code utils.ErrorCode, args ...interface{}) error {
+		return utils.NewError(code, fmt.Errorf("invalid %s promotion %s", p.Type, p.Code), args...)
+	}
+
+	p.Threshold = p.Threshold.OrDefaultCurrency()
+	p.AmountOff = p.AmountOff.OrDefaultCurrency()
+
+	if !isCatalogAmount(p.Threshold) {
+		return invalid(utils.ErrInvalidPromotionThreshold)
+	}
+
+	switch p.Type {
+	case model.PromotionPercentage:
+		if p.Value <= 0 || p.Value > 100 {
+			return invalid(utils.ErrInvalidDiscountPercentage)
+		}
+	case model.PromotionFixedAmount:
+		if !isCatalogAmount(p.AmountOff) || p.AmountOff.IsZero() {
+			return invalid(utils.ErrInvalidDiscountAmount, model.DefaultCurrency)
+		}
+	case model.PromotionBuyXGetY:
+		if p.BuyQuantity == 0 || p.GetQuantity == 0 {
+			return invalid(utils.ErrInvalidPromotionQuantities)
+		}
+	case model.PromotionFreeItemOver:
+		if p.FreeProductID == nil {
+			return invalid(utils.ErrFreeProductRequired)
+		}
+	default:
+		return invalid(utils.ErrInvalidPromotionType)
+	}
+
+	return nil
+}
@@ -4,42 +4,231 @@ package service
 import (
 	"codifin-challenge/domain/model"
 	"codifin-challenge/domain/repository"
+	"codifin-challenge/domain/utils"
+	"fmt"
+	"time"
 )
 
 // ShoppingCartService defines methods for interacting with shopping cart data.
 type ShoppingCartService interface {
 	CreateShoppingCart(shoppingCart *model.ShoppingCart) error
-	AddItemsToShoppingCart(items []*model.ItemCart) error
+	AddItemsToShoppingCart(cartID uint, items []*model.ItemCart, version *uint) error
 	FindCart(cartID uint) (*model.ShoppingCart, error)
-	RemoveItemsFromShoppingCart(cartId uint, items []uint) error
+	CartSummary(cartID uint, currency string) (*model.CartSummary, error)
+	RemoveItemsFromShoppingCart(cartId uint, items []uint, version *uint) error
+	ReleaseExpiredReservations() (int, error)
+	ApplyCoupon(cartID uint, code string, version *uint) error
+	RemoveCoupon(cartID uint, code string, version *uint) error
 }
 
 // ShoppingCartServiceImpl is an implementation of ShoppingCartService.
 type ShoppingCartServiceImpl struct {
 	shoppingCartRepo repository.ShoppingCartRepository
+	promotionRepo    repository.PromotionRepository
+	categoryService  CategoryService
+	taxService       TaxService
+	priceService     PriceService
 }
 
 // NewShoppingCartService creates a new instance of ShoppingCartServiceImpl.
-func NewShoppingCartService(repo repository.ShoppingCartRepository) *ShoppingCartServiceImpl {
-	return &ShoppingCartServiceImpl{shoppingCartRepo: repo}
+func NewShoppingCartService(repo repository.ShoppingCartRepository, promotionRepo repository.PromotionRepository, categoryService CategoryService, taxService TaxService, priceService PriceService) *ShoppingCartServiceImpl {
+	return &ShoppingCartServiceImpl{shoppingCartRepo: repo, promotionRepo: promotionRepo, categoryService: categoryService, taxService: taxService, priceService: priceService}
 }
 
-// CreateShoppingCart creates a new shopping cart.
+// CreateShoppingCart creates a new shopping cart taxed with the rules of its
+// region, or of the default region if it has none.
 func (s *ShoppingCartServiceImpl) CreateShoppingCart(shoppingCart *model.ShoppingCart) error {
+	if shoppingCart.Region == "" {
+		shoppingCart.Region = s.taxService.DefaultRegion()
+	}
+
+	if _, err := s.taxService.RegionRates(shoppingCart.Region); err != nil {
+		return err
+	}
+
 	return s.shoppingCartRepo.Create(shoppingCart)
 }
 
-// AddItemsToShoppingCart adds items to a shopping cart.
-func (s *ShoppingCartServiceImpl) AddItemsToShoppingCart(items []*model.ItemCart) error {
-	return s.shoppingCartRepo.AddItems(items)
+// AddItemsToShoppingCart adds items to a shopping cart, if it is at the
+// version given.
+func (s *ShoppingCartServiceImpl) AddItemsToShoppingCart(cartID uint, items []*model.ItemCart, version *uint) error {
+	for _, v := range items {
+		v.ShoppingCartID = cartID
+	}
+
+	return s.shoppingCartRepo.AddItems(items, version)
+}
+
+// RemoveItemsFromShoppingCart removes items from a shopping cart, if it is at
+// the version given.
+func (s *ShoppingCartServiceImpl) RemoveItemsFromShoppingCart(cartId uint, items []uint, version *uint) error {
+	return s.shoppingCartRepo.DeleteProducts(cartId, items, version)
+}
+
+// ReleaseExpiredReservations returns the stock held by expired cart reservations.
+func (s *ShoppingCartServiceImpl) ReleaseExpiredReservations() (int, error) {
+	return s.shoppingCartRepo.ReleaseExpiredReservations()
+}
+
+// ApplyCoupon applies the promotion of a coupon code to a shopping cart. The
+// promotion has to be in its validity window and below its usage limit.
+func (s *ShoppingCartServiceImpl) ApplyCoupon(cartID uint, code string, version *uint) error {
+	promotion, err := s.promotionRepo.GetByCode(code)
+	if err != nil {
+		return err
+	}
+
+	if !promotion.IsActive(time.Now()) {
+		return utils.NewError(utils.ErrCouponInactive, fmt.Errorf("promotion %d is not active", promotion.ID), code)
+	}
+
+	if promotion.IsExhausted() {
+		return utils.NewError(utils.ErrCouponExhausted, fmt.Errorf("promotion %d usage limit reached", promotion.ID), code)
+	}
+
+	return s.shoppingCartRepo.AddCoupon(cartID, promotion.ID, version)
 }
 
-// RemoveItemsFromShoppingCart removes items from a shopping cart.
-func (s *ShoppingCartServiceImpl) RemoveItemsFromShoppingCart(cartId uint, items []uint) error {
-	return s.shoppingCartRepo.DeleteProducts(cartId, items)
+// RemoveCoupon removes the promotion of a coupon code from a shopping cart.
+func (s *ShoppingCartServiceImpl) RemoveCoupon(cartID uint, code string, version *uint) error {
+	promotion, err := s.promotionRepo.GetByCode(code)
+	if err != nil {
+		return err
+	}
+
+	return s.shoppingCartRepo.RemoveCoupon(cartID, promotion.ID, version)
 }
 
 // FindCart finds a shopping cart by its ID.
 func (s *ShoppingCartServiceImpl) FindCart(cartID uint) (*model.ShoppingCart, error) {
 	return s.shoppingCartRepo.GetByID(cartID)
 }
+
+// CartSummary finds a shopping cart by its ID and computes its line totals,
+// subtotal, item count, coupon discounts, taxes and grand total in a
+// currency, the default currency if empty. The amounts of the promotions are
+// converted to the currency.
+func (s *ShoppingCartServiceImpl) CartSummary(cartID uint, currency string) (*model.CartSummary, error) {
+	cart, err := s.shoppingCartRepo.GetByID(cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := s.cartPriceBook(cart, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := s.summarize(cart, book)
+
+	scopes, err := s.promotionScopes(cart.Coupons)
+	if err != nil {
+		return nil, err
+	}
+
+	applyDiscounts(summary, scopes, time.Now())
+
+	rates, err := s.taxService.RegionRates(cart.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	applyTaxes(summary, rates)
+	summary.PricesIncludeTax = s.taxService.PricesIncludeTax()
+
+	return summary, nil
+}
+
+// promotionScopes resolves the products and categories, with their
+// descendants, that the promotions of the coupons apply to.
+func (s *ShoppingCartServiceImpl) promotionScopes(coupons []*model.CartCoupon) (map[uint]*promotionScope, error) {
+	scopes := make(map[uint]*promotionScope, len(coupons))
+
+	for _, coupon := range coupons {
+		p := coupon.Promotion
+		if p == nil {
+			continue
+		}
+
+		scope := &promotionScope{
+			all:        len(p.Products) == 0 && len(p.Categories) == 0,
+			products:   make(map[uint]bool, len(p.Products)),
+			categories: make(map[uint]bool, len(p.Categories)),
+		}
+
+		for _, v := range p.Products {
+			scope.products[v.ID] = true
+		}
+
+		for _, v := range p.Categories {
+			ids, err := s.categoryService.CategoryIDs(v.ID, true)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range ids {
+				scope.categories[id] = true
+			}
+		}
+
+		scopes[coupon.PromotionID] = scope
+	}
+
+	return scopes, nil
+}
+
+// cartPriceBook returns the book that prices the products of the cart in a
+// currency and converts the amounts of its promotions to that currency.
+func (s *ShoppingCartServiceImpl) cartPriceBook(cart *model.ShoppingCart, currency string) (*model.PriceBook, error) {
+	ids := make([]uint, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		ids = append(ids, item.ProductID)
+	}
+
+	book, err := s.priceService.PriceBook(currency, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, coupon := range cart.Coupons {
+		if coupon.Promotion != nil {
+			coupon.Promotion.AmountOff = book.Convert(coupon.Promotion.AmountOff)
+			coupon.Promotion.Threshold = book.Convert(coupon.Promotion.Threshold)
+		}
+	}
+
+	return book, nil
+}
+
+// summarize prices every item of the cart with the current price of its
+// variant or product in the currency of the book. Items whose product or
+// variant no longer exists, or was deleted, are priced at zero.
+func (s *ShoppingCartServiceImpl) summarize(cart *model.ShoppingCart, book *model.PriceBook) *model.CartSummary {
+	summary := &model.CartSummary{
+		Cart:     cart,
+		Lines:    make([]*model.CartLine, 0, len(cart.Items)),
+		Subtotal: model.NewMoney(0, book.Currency),
+	}
+
+	for _, item := range cart.Items {
+		book.Localize(item.Product)
+		book.LocalizeVariant(item.Variant)
+
+		sold := item.Product != nil && !item.Product.IsDeleted()
+
+		line := &model.CartLine{Item: item, UnitPrice: model.NewMoney(0, book.Currency)}
+		if item.VariantID != nil && item.Variant != nil && sold {
+			line.UnitPrice = item.Variant.UnitPrice(item.Product)
+		} else if item.VariantID == nil && sold {
+			line.UnitPrice = item.Product.Price
+		}
+		line.LineTotal = line.UnitPrice.Mul(item.Count)
+
+		summary.Lines = append(summary.Lines, line)
+		summary.ItemCount += item.Count
+		summary.Subtotal = summary.Subtotal.Add(line.LineTotal)
+	}
+
+	summary.Total = summary.Subtotal
+
+	return summary
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"codifin-challenge/domain/model"
+	"gorm.io/gorm"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// priceServiceStub hands out a fixed price book and records what it was asked for.
+type priceServiceStub struct {
+	PriceService
+	book       *model.PriceBook
+	currency   string
+	productIDs []uint
+}
+
+func (s *priceServiceStub) PriceBook(currency string, productIDs []uint) (*model.PriceBook, error) {
+	s.currency, s.productIDs = currency, productIDs
+	return s.book, nil
+}
+
+// summaryCart returns a cart with a product sold at its price, the variants
+// of a product with and without a price of their own, a deleted product, a
+// missing variant and a product that no longer exists.
+func summaryCart() *model.ShoppingCart {
+	product := func(id uint, price int64) *model.Product {
+		return &model.Product{Model: gorm.Model{ID: id}, Price: mxn(price), TaxCategory: model.TaxStandard}
+	}
+	variantID := func(id uint) *uint { return &id }
+
+	variantPrice := mxn(15000)
+	shirt := product(2, 8000)
+	deleted := product(3, 5000)
+	deleted.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	return &model.ShoppingCart{
+		Model:  gorm.Model{ID: 1},
+		Region: "MX",
+		Items: []*model.ItemCart{
+			{ProductID: 1, Product: product(1, 10000), Count: 2},
+			{ProductID: 2, Product: shirt, VariantID: variantID(21), Variant: &model.ProductVariant{Model: gorm.Model{ID: 21}, Price: &variantPrice}, Count: 1},
+			{ProductID: 2, Product: shirt, VariantID: variantID(22), Variant: &model.ProductVariant{Model: gorm.Model{ID: 22}}, Count: 3},
+			{ProductID: 3, Product: deleted, Count: 4},
+			{ProductID: 4, Product: product(4, 2000), VariantID: variantID(41), Count: 1},
+			{ProductID: 5, Count: 2},
+		},
+	}
+}
+
+// Test_summarize tests that every item is priced with its variant or product in the currency of the book, that items
+// whose product or variant is gone are priced at zero, and the subtotal and item count.
+func Test_summarize(t *testing.T) {
+	cases := []struct {
+		name     string
+		book     *model.PriceBook
+		units    []int64
+		subtotal int64
+	}{
+		{
+			name:     "default currency",
+			book:     model.NewPriceBook(),
+			units:    []int64{10000, 15000, 8000, 0, 0, 0},
+			subtotal: 20000 + 15000 + 24000,
+		},
+		{
+			name:     "converted and listed prices",
+			book:     &model.PriceBook{Currency: "USD", Rate: 0.05, Prices: map[uint]model.Money{1: model.NewMoney(600, "USD")}},
+			units:    []int64{600, 750, 400, 0, 0, 0},
+			subtotal: 1200 + 750 + 1200,
+		},
+	}
+
+	for _, v := range cases {
+		service := NewShoppingCartService(nil, nil, nil, nil, nil)
+		summary := service.summarize(summaryCart(), v.book)
+
+		if len(summary.Lines) != len(v.units) {
+			t.Fatalf("%s: expected %d lines, got %d", v.name, len(v.units), len(summary.Lines))
+		}
+
+		for i, line := range summary.Lines {
+			if line.UnitPrice != model.NewMoney(v.units[i], v.book.Currency) ||
+				line.LineTotal != model.NewMoney(v.units[i]*int64(line.Item.Count), v.book.Currency) {
+				t.Errorf("%s: expected line %d to cost %d each, got %v and %v in all", v.name, i, v.units[i], line.UnitPrice, line.LineTotal)
+			}
+		}
+
+		if summary.Subtotal != model.NewMoney(v.subtotal, v.book.Currency) || summary.Total != summary.Subtotal {
+			t.Errorf("%s: expected a subtotal of %d, got %v and a total of %v", v.name, v.subtotal, summary.Subtotal, summary.Total)
+		}
+
+		if summary.ItemCount != 13 {
+			t.Errorf("%s: expected 13 items, got %d", v.name, summary.ItemCount)
+		}
+	}
+}
+
+// Test_CartSummary tests that the summary of a cart is priced with the book of the requested currency for its
+// products, and then taxed.
+func Test_CartSummary(t *testing.T) {
+	prices := &priceServiceStub{book: &model.PriceBook{Currency: "USD", Rate: 0.05, Prices: map[uint]model.Money{}}}
+	rules := &taxRuleRepoStub{rules: []*model.TaxRule{{Region: "MX", TaxCategory: model.TaxStandard, Rate: 0.16}}}
+	carts := &cartRepoStub{carts: map[uint]*model.ShoppingCart{1: summaryCart()}}
+	service := NewShoppingCartService(carts, nil, nil, NewTaxService(rules, "MX", true), prices)
+
+	summary, err := service.CartSummary(1, "USD")
+	if err != nil {
+		t.Fatalf("Error summarizing cart: %v", err)
+	}
+
+	if prices.currency != "USD" || !reflect.DeepEqual(prices.productIDs, []uint{1, 2, 2, 3, 4, 5}) {
+		t.Errorf("Expected a USD book for the products of the cart, got %s for %v", prices.currency, prices.productIDs)
+	}
+
+	subtotal := int64(1000 + 750 + 1200)
+	if summary.Subtotal.Amount != subtotal || summary.Subtotal.Currency != "USD" || summary.ItemCount != 13 {
+		t.Errorf("Expected a subtotal of %d USD for 13 items, got %v for %d", subtotal, summary.Subtotal, summary.ItemCount)
+	}
+
+	if summary.TaxTotal.Amount != 160+120+192 || summary.Total.Amount != subtotal+160+120+192 || !summary.PricesIncludeTax {
+		t.Errorf("Expected the lines to be taxed, got %v of taxes and a total of %v", summary.TaxTotal, summary.Total)
+	}
+
+	if _, err = service.CartSummary(2, "USD"); err == nil {
+		t.Errorf("Expected a missing cart to fail")
+	}
+}
@@ -0,0 +1,189 @@
+// Package service provides implementations for interacting with tax rule data.
+package service
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/repository"
+	"codifin-challenge/domain/utils"
+	"errors"
+	"fmt"
+)
+
+// TaxService defines methods for interacting with tax rule data.
+type TaxService interface {
+	TaxRules() ([]*model.TaxRule, error)
+	TaxRuleByID(ruleID uint) (*model.TaxRule, error)
+	CreateTaxRule(rule *model.TaxRule) error
+	UpdateTaxRule(rule *model.TaxRule) error
+	DeleteTaxRule(ruleID uint) error
+	RegionRates(region string) (map[string]float64, error)
+	ValidateTaxCategory(taxCategory string) error
+	DefaultRegion() string
+	PricesIncludeTax() bool
+}
+
+// TaxServiceImpl is an implementation of TaxService.
+type TaxServiceImpl struct {
+	taxRuleRepo      repository.TaxRuleRepository
+	defaultRegion    string
+	pricesIncludeTax bool
+}
+
+// NewTaxService creates a new instance of TaxServiceImpl. Carts created
+// without a region are taxed with the rules of defaultRegion, and
+// pricesIncludeTax tells whether prices are displayed with their taxes.
+func NewTaxService(repo repository.TaxRuleRepository, defaultRegion string, pricesIncludeTax bool) *TaxServiceImpl {
+	return &TaxServiceImpl{taxRuleRepo: repo, defaultRegion: defaultRegion, pricesIncludeTax: pricesIncludeTax}
+}
+
+// TaxRules retrieves every tax rule.
+func (s *TaxServiceImpl) TaxRules() ([]*model.TaxRule, error) {
+	return s.taxRuleRepo.GetAll()
+}
+
+// TaxRuleByID retrieves a tax rule by its ID.
+func (s *TaxServiceImpl) TaxRuleByID(ruleID uint) (*model.TaxRule, error) {
+	return s.taxRuleRepo.GetByID(ruleID)
+}
+
+// CreateTaxRule creates the rate of a tax category in a region.
+func (s *TaxServiceImpl) CreateTaxRule(rule *model.TaxRule) error {
+	if err := s.validateRule(rule); err != nil {
+		return err
+	}
+
+	return s.taxRuleRepo.Create(rule)
+}
+
+// UpdateTaxRule replaces the region, tax category and rate of an existing rule.
+func (s *TaxServiceImpl) UpdateTaxRule(rule *model.TaxRule) error {
+	stored, err := s.taxRuleRepo.GetByID(rule.ID)
+	if err != nil {
+		return err
+	}
+
+	stored.Region = rule.Region
+	stored.TaxCategory = rule.TaxCategory
+	stored.Rate = rule.Rate
+
+	if err = s.validateRule(stored); err != nil {
+		return err
+	}
+
+	if err = s.taxRuleRepo.Update(stored); err != nil {
+		return err
+	}
+
+	*rule = *stored
+
+	return nil
+}
+
+// DeleteTaxRule deletes a tax rule by its ID.
+func (s *TaxServiceImpl) DeleteTaxRule(ruleID uint) error {
+	if _, err := s.taxRuleRepo.GetByID(ruleID); err != nil {
+		return err
+	}
+
+	return s.taxRuleRepo.Delete(ruleID)
+}
+
+// RegionRates returns the rate of every tax category in a region, failing if
+// the region has no rules.
+func (s *TaxServiceImpl) RegionRates(region string) (map[string]float64, error) {
+	rules, err := s.taxRuleRepo.GetByRegion(region)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rules) == 0 {
+		return nil, utils.NewError(utils.ErrTaxRegionNotFound, fmt.Errorf("region %s has no tax rules", region), region)
+	}
+
+	rates := make(map[string]float64, len(rules))
+	for _, v := range rules {
+		rates[v.TaxCategory] = v.Rate
+	}
+
+	return rates, nil
+}
+
+// ValidateTaxCategory makes sure some region has a rule for the tax category.
+func (s *TaxServiceImpl) ValidateTaxCategory(taxCategory string) error {
+	exists, err := s.taxRuleRepo.ExistsCategory(taxCategory)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return utils.NewError(utils.ErrTaxCategoryNotFound, fmt.Errorf("tax category %s has no rules", taxCategory), taxCategory)
+	}
+
+	return nil
+}
+
+// DefaultRegion returns the region of the carts created without one.
+func (s *TaxServiceImpl) DefaultRegion() string {
+	return s.defaultRegion
+}
+
+// PricesIncludeTax reports whether prices are displayed with their taxes.
+func (s *TaxServiceImpl) PricesIncludeTax() bool {
+	return s.pricesIncludeTax
+}
+
+// validateRule makes sure the rule has a region, a tax category and a rate
+// between 0 and 1, and that no other rule sets the same rate.
+func (s *TaxServiceImpl) validateRule(rule *model.TaxRule) error {
+	if rule.Region == "" || rule.TaxCategory == "" {
+		return utils.NewError(utils.ErrTaxRuleKeyRequired, errors.New("empty tax rule region or category"))
+	}
+
+	if rule.Rate < 0 || rule.Rate > 1 {
+		return utils.NewError(utils.ErrInvalidTaxRate, fmt.Errorf("invalid tax rate %v", rule.Rate))
+	}
+
+	exists, err := s.taxRuleRepo.ExistsRule(rule.Region, rule.TaxCategory, rule.ID)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return utils.NewError(utils.ErrTaxRuleTaken, fmt.Errorf("duplicated tax rule %s/%s", rule.Region, rule.TaxCategory), rule.TaxCategory, rule.Region)
+	}
+
+	return nil
+}
+
+// rateFor returns the rate of a tax category, falling back to the standard
+// rate of the region when the category has no rule of its own there.
+func rateFor(rates map[string]float64, taxCategory string) float64 {
+	if rate, ok := rates[taxCategory]; ok {
+		return rate
+	}
+	return rates[model.TaxStandard]
+}
+
+// applyTaxes charges the tax of every line on its discounted total, rounding
+// each line as Money.MulRate does, and computes the grand total of the cart.
+func applyTaxes(summary *model.CartSummary, rates map[string]float64) {
+	summary.TaxTotal = model.NewMoney(0, summary.Subtotal.Currency)
+
+	for _, line := range summary.Lines {
+		taxCategory := model.TaxStandard
+		if line.Item.Product != nil {
+			taxCategory = line.Item.Product.TaxCategory
+		}
+
+		base := line.LineTotal.Sub(line.Discount)
+		if base.IsNegative() {
+			base.Amount = 0
+		}
+
+		line.TaxRate = rateFor(rates, taxCategory)
+		line.Tax = base.MulRate(line.TaxRate)
+		summary.TaxTotal = summary.TaxTotal.Add(line.Tax)
+	}
+
+	summary.Total = summary.Subtotal.Sub(summary.DiscountTotal).Add(summary.TaxTotal)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/repository"
+	"codifin-challenge/domain/utils"
+	"errors"
+	"testing"
+)
+
+var mexicanRates = map[string]float64{model.TaxStandard: 0.16, model.TaxExempt: 0}
+
+// taxRuleRepoStub finds the rules of a region among a fixed list.
+type taxRuleRepoStub struct {
+	repository.TaxRuleRepository
+	rules []*model.TaxRule
+}
+
+func (r *taxRuleRepoStub) GetByRegion(region string) ([]*model.TaxRule, error) {
+	rules := make([]*model.TaxRule, 0)
+	for _, v := range r.rules {
+		if v.Region == region {
+			rules = append(rules, v)
+		}
+	}
+	return rules, nil
+}
+
+// cartRepoStub keeps the carts it is asked to create and finds the carts it
+// was given.
+type cartRepoStub struct {
+	repository.ShoppingCartRepository
+	created []*model.ShoppingCart
+	carts   map[uint]*model.ShoppingCart
+}
+
+func (r *cartRepoStub) GetByID(id uint) (*model.ShoppingCart, error) {
+	cart, ok := r.carts[id]
+	if !ok {
+		return nil, utils.NewError(utils.ErrCartNotFound, errors.New("cart not found"))
+	}
+	return cart, nil
+}
+
+func (r *cartRepoStub) Create(cart *model.ShoppingCart) error {
+	r.created = append(r.created, cart)
+	return nil
+}
+
+// Test_rateFor tests that categories without a rule of their own in a region are taxed at its standard rate.
+func Test_rateFor(t *testing.T) {
+	cases := []struct {
+		rates       map[string]float64
+		taxCategory string
+		expected    float64
+	}{
+		{mexicanRates, model.TaxStandard, 0.16},
+		{mexicanRates, model.TaxExempt, 0},
+		{mexicanRates, "books", 0.16},
+		{map[string]float64{"books": 0.05}, "books", 0.05},
+		{map[string]float64{"books": 0.05}, "food", 0},
+	}
+
+	for _, v := range cases {
+		if rate := rateFor(v.rates, v.taxCategory); rate != v.expected {
+			t.Errorf("Expected %s to be taxed at %v with %v, got %v", v.taxCategory, v.expected, v.rates, rate)
+		}
+	}
+}
+
+// Test_applyTaxes tests that every line is taxed at the rate of its category on its discounted total, rounded line by
+// line, and that the total adds the taxes to the discounted subtotal.
+func Test_applyTaxes(t *testing.T) {
+	type line struct {
+		taxCategory string
+		total       int64
+		discount    int64
+	}
+
+	cases := []struct {
+		name     string
+		rates    map[string]float64
+		lines    []line
+		taxes    []int64
+		expected int64
+	}{
+		{
+			name:     "rounded per line",
+			rates:    mexicanRates,
+			lines:    []line{{model.TaxStandard, 5, 0}, {model.TaxStandard, 5, 0}, {model.TaxStandard, 5, 0}},
+			taxes:    []int64{1, 1, 1},
+			expected: 18,
+		},
+		{
+			name:     "exempt categories and categories without a rule",
+			rates:    mexicanRates,
+			lines:    []line{{model.TaxExempt, 1000, 0}, {"books", 1000, 0}, {"", 1000, 0}},
+			taxes:    []int64{0, 160, 160},
+			expected: 3320,
+		},
+		{
+			name:     "taxed after the discounts",
+			rates:    mexicanRates,
+			lines:    []line{{model.TaxStandard, 1000, 250}, {model.TaxExempt, 500, 100}, {model.TaxStandard, 100, 150}},
+			taxes:    []int64{120, 0, 0},
+			expected: 1600 - 500 + 120,
+		},
+		{
+			name:     "rates of another region",
+			rates:    map[string]float64{model.TaxStandard: 0.08, model.TaxExempt: 0},
+			lines:    []line{{model.TaxStandard, 1250, 0}, {model.TaxStandard, 1, 0}},
+			taxes:    []int64{100, 0},
+			expected: 1351,
+		},
+	}
+
+	for _, v := range cases {
+		summary := &model.CartSummary{Subtotal: mxn(0), DiscountTotal: mxn(0)}
+		for _, l := range v.lines {
+			item := &model.ItemCart{Count: 1}
+			if l.taxCategory != "" {
+				item.Product = &model.Product{TaxCategory: l.taxCategory}
+			}
+
+			summary.Lines = append(summary.Lines, &model.CartLine{Item: item, LineTotal: mxn(l.total), Discount: mxn(l.discount)})
+			summary.Subtotal = summary.Subtotal.Add(mxn(l.total))
+			summary.DiscountTotal = summary.DiscountTotal.Add(mxn(l.discount))
+		}
+
+		applyTaxes(summary, v.rates)
+
+		taxTotal := int64(0)
+		for i, l := range summary.Lines {
+			if l.Tax.Amount != v.taxes[i] || l.TaxRate != rateFor(v.rates, v.lines[i].taxCategory) {
+				t.Errorf("%s: expected line %d to be taxed %d, got %d at %v", v.name, i, v.taxes[i], l.Tax.Amount, l.TaxRate)
+			}
+			taxTotal += v.taxes[i]
+		}
+
+		if summary.TaxTotal.Amount != taxTotal || summary.Total.Amount != v.expected {
+			t.Errorf("%s: expected taxes of %d and a total of %d, got %v and %v", v.name, taxTotal, v.expected,
+				summary.TaxTotal, summary.Total)
+		}
+	}
+}
+
+// Test_CreateShoppingCartRegion tests that carts without a region are taxed in the default region, and that regions
+// without rules are rejected.
+func Test_CreateShoppingCartRegion(t *testing.T) {
+	rules := &taxRuleRepoStub{rules: []*model.TaxRule{
+		{Region: "MX", TaxCategory: model.TaxStandard, Rate: 0.16},
+		{Region: "MX-BORDER", TaxCategory: model.TaxStandard, Rate: 0.08},
+	}}
+	carts := &cartRepoStub{}
+	service := NewShoppingCartService(carts, nil, nil, NewTaxService(rules, "MX", false), nil)
+
+	cases := []struct {
+		region   string
+		expected string
+	}{
+		{"", "MX"},
+		{"MX-BORDER", "MX-BORDER"},
+	}
+
+	for _, v := range cases {
+		cart := &model.ShoppingCart{Region: v.region}
+		if err := service.CreateShoppingCart(cart); err != nil {
+			t.Fatalf("Error creating cart in %q: %v", v.region, err)
+		}
+
+		if cart.Region != v.expected {
+			t.Errorf("Expected a cart created in %q to be taxed in %s, got %s", v.region, v.expected, cart.Region)
+		}
+	}
+
+	err := service.CreateShoppingCart(&model.ShoppingCart{Region: "US"})
+
+	var e *utils.DBError
+	if !errors.As(err, &e) || e.Code != utils.ErrTaxRegionNotFound {
+		t.Errorf("Expected a region without rules to be rejected, got %v", err)
+	}
+
+	if len(carts.created) != len(cases) {
+		t.Errorf("Expected %d carts to be created, got %d", len(cases), len(carts.created))
+	}
+}
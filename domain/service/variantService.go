@@ -0,0 +1,100 @@
+// Package service provides implementations for interacting with product variant data.
+package service
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/repository"
+	"codifin-challenge/domain/utils"
+	"fmt"
+)
+
+// VariantService defines methods for interacting with product variant data.
+type VariantService interface {
+	CreateVariant(v *model.ProductVariant) error
+	UpdateVariant(v *model.ProductVariant) error
+	DeleteVariant(productID, variantID uint) error
+}
+
+// VariantServiceImpl is an implementation of VariantService.
+type VariantServiceImpl struct {
+	variantRepo repository.VariantRepository
+	productRepo repository.ProductRepository
+}
+
+// NewVariantService creates a new instance of VariantServiceImpl.
+func NewVariantService(repo repository.VariantRepository, productRepo repository.ProductRepository) *VariantServiceImpl {
+	return &VariantServiceImpl{variantRepo: repo, productRepo: productRepo}
+}
+
+// CreateVariant adds a new variant to an existing product.
+func (s *VariantServiceImpl) CreateVariant(v *model.ProductVariant) error {
+	if _, err := s.productRepo.GetByID(v.ProductID); err != nil {
+		return err
+	}
+
+	if err := s.validateVariant(v); err != nil {
+		return err
+	}
+
+	return s.variantRepo.Create(v)
+}
+
+// UpdateVariant replaces the SKU, price, stock and attributes of a variant.
+func (s *VariantServiceImpl) UpdateVariant(v *model.ProductVariant) error {
+	variant, err := s.variantRepo.GetByID(v.ProductID, v.ID)
+	if err != nil {
+		return err
+	}
+
+	variant.SKU = v.SKU
+	variant.Price = v.Price
+	variant.Stock = v.Stock
+	variant.Attributes = v.Attributes
+
+	if err = s.validateVariant(variant); err != nil {
+		return err
+	}
+
+	if err = s.variantRepo.Update(variant); err != nil {
+		return err
+	}
+
+	*v = *variant
+
+	return nil
+}
+
+// DeleteVariant deletes a variant of a product.
+func (s *VariantServiceImpl) DeleteVariant(productID, variantID uint) error {
+	if _, err := s.variantRepo.GetByID(productID, variantID); err != nil {
+		return err
+	}
+
+	return s.variantRepo.Delete(productID, variantID)
+}
+
+// validateVariant makes sure the variant has a SKU not used by another variant.
+func (s *VariantServiceImpl) validateVariant(v *model.ProductVariant) error {
+	if v.SKU == "" {
+		return utils.NewError(utils.ErrVariantSKURequired, fmt.Errorf("empty variant sku"))
+	}
+
+	if v.Price != nil {
+		*v.Price = v.Price.OrDefaultCurrency()
+	}
+
+	if v.Price != nil && !isCatalogAmount(*v.Price) {
+		return utils.NewError(utils.ErrInvalidVariantPrice, fmt.Errorf("invalid variant price %s %s", v.Price, v.Price.Currency))
+	}
+
+	exists, err := s.variantRepo.ExistsSKU(v.SKU, v.ID)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return utils.NewError(utils.ErrVariantSKUTaken, fmt.Errorf("duplicated variant sku %s", v.SKU), v.SKU)
+	}
+
+	return nil
+}
@@ -1,31 +1,82 @@
 package utils
 
+import (
+	"codifin-challenge/domain/i18n"
+	"fmt"
+	"strings"
+)
+
+// DBError is an error of the catalog. Code tells clients what went wrong and
+// Status is the HTTP status of its responses, taken from the catalog. Args
+// fill in the message of the code, which explains it to the user in their
+// language, and DevelopMessage is what caused it, which may expose internals
+// and is only shown to developers.
 type DBError struct {
-	Code           int
-	UserMessage    string
+	Status         int
+	Code           ErrorCode
+	Args           []interface{}
 	DevelopMessage error
+	// Fields are the fields of the request that break a validation rule.
+	Fields []*FieldError
+}
+
+// FieldError is a validation rule a field of a request breaks. Field is the
+// path of the field in the JSON body, such as items[0].count.
+type FieldError struct {
+	Field   string
+	Rule    string
+	Message i18n.Localizable
 }
 
+// Error returns the message of the error in the default language.
 func (e *DBError) Error() string {
-	return e.UserMessage
+	return e.Localize(i18n.DefaultLanguage())
 }
 
-func ToUserError(code int, userMsg string, err error) *DBError {
+// Localize returns the message of the error in a language.
+func (e *DBError) Localize(language string) string {
+	return i18n.Translate(language, string(e.Code), e.Args...)
+}
+
+// NewError returns the error of the catalog with the code given, caused by
+// err. The arguments fill in the message of the code. Codes missing from the
+// catalog are internal errors.
+func NewError(code ErrorCode, err error, args ...interface{}) *DBError {
+	status, ok := errorCatalog[code]
+	if !ok {
+		code, status, args = ErrInternalError, errorCatalog[ErrInternalError], nil
+	}
+
 	return &DBError{
+		Status:         status,
 		Code:           code,
-		UserMessage:    userMsg,
+		Args:           args,
 		DevelopMessage: err,
 	}
 }
 
+// ToValidationError returns the error of a request whose fields break
+// validation rules. The messages name their fields, so they are listed in the
+// user message too.
+func ToValidationError(fields []*FieldError) *DBError {
+	names := make([]string, 0, len(fields))
+	messages := make(i18n.List, 0, len(fields))
+	for _, v := range fields {
+		names = append(names, v.Field)
+		messages = append(messages, v.Message)
+	}
+
+	e := NewError(ErrValidationFailed, fmt.Errorf("invalid fields: %s", strings.Join(names, ", ")), messages)
+	e.Fields = fields
+	return e
+}
+
+// GetCustomError returns the error as an error of the catalog. Errors from
+// outside the catalog are internal errors, so their text is not shown to users.
 func GetCustomError(err error) *DBError {
-	if _, ok := err.(*DBError); ok {
-		return err.(*DBError)
-	} else {
-		return &DBError{
-			Code:           500,
-			UserMessage:    err.Error(),
-			DevelopMessage: err,
-		}
+	if customErr, ok := err.(*DBError); ok {
+		return customErr
 	}
+
+	return NewError(ErrInternalError, err)
 }
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"codifin-challenge/domain/i18n"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// Test_NewError tests that errors take their status from the catalog and their
+// message from the bundles, and that errors outside the catalog do not show
+// their text.
+func Test_NewError(t *testing.T) {
+	e := NewError(ErrProductCodeTaken, errors.New("duplicated key"), "CAF-01")
+	if e.Status != http.StatusConflict || e.Error() != "Ya existe un producto con el codigo CAF-01" {
+		t.Errorf("Expected a conflict for the code CAF-01, got %d %s", e.Status, e.Error())
+	}
+	if message := e.Localize("en"); message != "A product with the code CAF-01 already exists" {
+		t.Errorf("Expected the message in english, got %s", message)
+	}
+
+	if e = NewError("UNKNOWN", nil); e.Code != ErrInternalError || e.Status != http.StatusInternalServerError {
+		t.Errorf("Expected an unknown code to be an internal error, got %s %d", e.Code, e.Status)
+	}
+
+	e = GetCustomError(errors.New("pq: relation products does not exist"))
+	if e.Code != ErrInternalError || e.Error() != i18n.Translate(i18n.DefaultLanguage(), string(ErrInternalError)) {
+		t.Errorf("Expected the text of an unknown error to be hidden, got %s", e.Error())
+	}
+}
+
+// Test_errorCatalog tests that every error has an error status and a message in
+// every language, and that every language has the same messages.
+func Test_errorCatalog(t *testing.T) {
+	languages := i18n.Languages()
+	for code, status := range errorCatalog {
+		if status < http.StatusBadRequest {
+			t.Errorf("Expected %s to have an error status, got %d", code, status)
+		}
+		for _, language := range languages {
+			if message := i18n.Translate(language, string(code)); message == string(code) {
+				t.Errorf("Expected %s to have a message in %s", code, language)
+			}
+		}
+	}
+
+	keys := make(map[string]bool)
+	for _, k := range i18n.Keys(i18n.DefaultLanguage()) {
+		keys[k] = true
+	}
+	for _, language := range languages {
+		found := i18n.Keys(language)
+		if len(found) != len(keys) {
+			t.Errorf("Expected %s to have %d messages, got %d", language, len(keys), len(found))
+		}
+		for _, k := range found {
+			if !keys[k] {
+				t.Errorf("Expected %s not to have the message %s", language, k)
+			}
+		}
+	}
+}
@@ -0,0 +1,400 @@
+package utils
+
+import "net/http"
+
+// ErrorCode is the stable code of an error of the catalog. Clients tell errors
+// apart by their code, since their messages may change.
+type ErrorCode string
+
+// General errors.
+const (
+	ErrInternalError      ErrorCode = "INTERNAL_ERROR"
+	ErrTransactionFailed  ErrorCode = "TRANSACTION_FAILED"
+	ErrValidationFailed   ErrorCode = "VALIDATION_FAILED"
+	ErrMalformedBody      ErrorCode = "MALFORMED_BODY"
+	ErrVersionConflict    ErrorCode = "VERSION_CONFLICT"
+	ErrInvalidPage        ErrorCode = "INVALID_PAGE"
+	ErrInvalidCursor      ErrorCode = "INVALID_CURSOR"
+	ErrCursorSortMismatch ErrorCode = "CURSOR_SORT_MISMATCH"
+	ErrPageWithCursor     ErrorCode = "PAGE_WITH_CURSOR"
+)
+
+// Categories.
+const (
+	ErrCategoryNotFound       ErrorCode = "CATEGORY_NOT_FOUND"
+	ErrCategoriesNotFound     ErrorCode = "CATEGORIES_NOT_FOUND"
+	ErrParentCategoryNotFound ErrorCode = "PARENT_CATEGORY_NOT_FOUND"
+	ErrCategoryCycle          ErrorCode = "CATEGORY_CYCLE"
+	ErrCategoryHasChildren    ErrorCode = "CATEGORY_HAS_CHILDREN"
+	ErrCategoryListFailed     ErrorCode = "CATEGORY_LIST_FAILED"
+	ErrCategoryReadFailed     ErrorCode = "CATEGORY_READ_FAILED"
+	ErrCategoryCreateFailed   ErrorCode = "CATEGORY_CREATE_FAILED"
+	ErrCategoryUpdateFailed   ErrorCode = "CATEGORY_UPDATE_FAILED"
+	ErrCategoryDeleteFailed   ErrorCode = "CATEGORY_DELETE_FAILED"
+)
+
+// Products.
+const (
+	ErrProductNotFound           ErrorCode = "PRODUCT_NOT_FOUND"
+	ErrProductCodeNotFound       ErrorCode = "PRODUCT_CODE_NOT_FOUND"
+	ErrProductCodeTaken          ErrorCode = "PRODUCT_CODE_TAKEN"
+	ErrConcurrentModification    ErrorCode = "CONCURRENT_MODIFICATION"
+	ErrMissingReference          ErrorCode = "MISSING_REFERENCE"
+	ErrProductCategoriesNotFound ErrorCode = "PRODUCT_CATEGORIES_NOT_FOUND"
+	ErrProductNotDeleted         ErrorCode = "PRODUCT_NOT_DELETED"
+	ErrInvalidProductPrice       ErrorCode = "INVALID_PRODUCT_PRICE"
+	ErrInvalidProductStatus      ErrorCode = "INVALID_PRODUCT_STATUS"
+	ErrInvalidPublishWindow      ErrorCode = "INVALID_PUBLISH_WINDOW"
+	ErrInvalidPriceFilter        ErrorCode = "INVALID_PRICE_FILTER"
+	ErrInvalidMinPrice           ErrorCode = "INVALID_MIN_PRICE"
+	ErrInvalidMaxPrice           ErrorCode = "INVALID_MAX_PRICE"
+	ErrPriceRangeInverted        ErrorCode = "PRICE_RANGE_INVERTED"
+	ErrInvalidCreatedAfter       ErrorCode = "INVALID_CREATED_AFTER"
+	ErrInvalidCreatedBefore      ErrorCode = "INVALID_CREATED_BEFORE"
+	ErrDateRangeInverted         ErrorCode = "DATE_RANGE_INVERTED"
+	ErrInvalidSort               ErrorCode = "INVALID_SORT"
+	ErrUnsupportedSortField      ErrorCode = "UNSUPPORTED_SORT_FIELD"
+	ErrInvalidPurgeAge           ErrorCode = "INVALID_PURGE_AGE"
+	ErrNegativePurgeAge          ErrorCode = "NEGATIVE_PURGE_AGE"
+	ErrUnsupportedPatchType      ErrorCode = "UNSUPPORTED_PATCH_TYPE"
+	ErrMalformedPatch            ErrorCode = "MALFORMED_PATCH"
+	ErrPatchTooLarge             ErrorCode = "PATCH_TOO_LARGE"
+	ErrInvalidPatchOperations    ErrorCode = "INVALID_PATCH_OPERATIONS"
+	ErrPatchTestFailed           ErrorCode = "PATCH_TEST_FAILED"
+	ErrPatchNotApplicable        ErrorCode = "PATCH_NOT_APPLICABLE"
+	ErrPatchResultNotObject      ErrorCode = "PATCH_RESULT_NOT_OBJECT"
+	ErrProductCountFailed        ErrorCode = "PRODUCT_COUNT_FAILED"
+	ErrProductListFailed         ErrorCode = "PRODUCT_LIST_FAILED"
+	ErrProductSearchFailed       ErrorCode = "PRODUCT_SEARCH_FAILED"
+	ErrPriceFacetsFailed         ErrorCode = "PRICE_FACETS_FAILED"
+	ErrCategoryFacetsFailed      ErrorCode = "CATEGORY_FACETS_FAILED"
+	ErrDateFacetsFailed          ErrorCode = "DATE_FACETS_FAILED"
+	ErrAttributeFacetsFailed     ErrorCode = "ATTRIBUTE_FACETS_FAILED"
+	ErrProductReadFailed         ErrorCode = "PRODUCT_READ_FAILED"
+	ErrProductCreateFailed       ErrorCode = "PRODUCT_CREATE_FAILED"
+	ErrProductUpdateFailed       ErrorCode = "PRODUCT_UPDATE_FAILED"
+	ErrProductVersionFailed      ErrorCode = "PRODUCT_VERSION_FAILED"
+	ErrProductCategoriesFailed   ErrorCode = "PRODUCT_CATEGORIES_FAILED"
+	ErrProductDeleteFailed       ErrorCode = "PRODUCT_DELETE_FAILED"
+	ErrProductPublishFailed      ErrorCode = "PRODUCT_PUBLISH_FAILED"
+	ErrProductArchiveFailed      ErrorCode = "PRODUCT_ARCHIVE_FAILED"
+	ErrDeletedProductCountFailed ErrorCode = "DELETED_PRODUCT_COUNT_FAILED"
+	ErrDeletedProductListFailed  ErrorCode = "DELETED_PRODUCT_LIST_FAILED"
+	ErrProductRestoreFailed      ErrorCode = "PRODUCT_RESTORE_FAILED"
+	ErrPurgeListFailed           ErrorCode = "PURGE_LIST_FAILED"
+	ErrProductPurgeFailed        ErrorCode = "PRODUCT_PURGE_FAILED"
+)
+
+// Product imports and exports.
+const (
+	ErrUnsupportedExportFormat  ErrorCode = "UNSUPPORTED_EXPORT_FORMAT"
+	ErrInvalidExportColumn      ErrorCode = "INVALID_EXPORT_COLUMN"
+	ErrUnsupportedImportFormat  ErrorCode = "UNSUPPORTED_IMPORT_FORMAT"
+	ErrImportFileMissing        ErrorCode = "IMPORT_FILE_MISSING"
+	ErrImportHeaderUnreadable   ErrorCode = "IMPORT_HEADER_UNREADABLE"
+	ErrUnknownImportColumn      ErrorCode = "UNKNOWN_IMPORT_COLUMN"
+	ErrImportFileUnreadable     ErrorCode = "IMPORT_FILE_UNREADABLE"
+	ErrInvalidCSVLine           ErrorCode = "INVALID_CSV_LINE"
+	ErrCSVColumnCountMismatch   ErrorCode = "CSV_COLUMN_COUNT_MISMATCH"
+	ErrInvalidJSONLine          ErrorCode = "INVALID_JSON_LINE"
+	ErrInvalidProductData       ErrorCode = "INVALID_PRODUCT_DATA"
+	ErrUnknownImportField       ErrorCode = "UNKNOWN_IMPORT_FIELD"
+	ErrInvalidImportPrice       ErrorCode = "INVALID_IMPORT_PRICE"
+	ErrInvalidImportStock       ErrorCode = "INVALID_IMPORT_STOCK"
+	ErrInvalidImportCategories  ErrorCode = "INVALID_IMPORT_CATEGORIES"
+	ErrInvalidImportPublishAt   ErrorCode = "INVALID_IMPORT_PUBLISH_AT"
+	ErrInvalidImportUnpublishAt ErrorCode = "INVALID_IMPORT_UNPUBLISH_AT"
+)
+
+// Variants and stock.
+const (
+	ErrVariantNotFound       ErrorCode = "VARIANT_NOT_FOUND"
+	ErrVariantSKURequired    ErrorCode = "VARIANT_SKU_REQUIRED"
+	ErrInvalidVariantPrice   ErrorCode = "INVALID_VARIANT_PRICE"
+	ErrVariantSKUTaken       ErrorCode = "VARIANT_SKU_TAKEN"
+	ErrInsufficientStock     ErrorCode = "INSUFFICIENT_STOCK"
+	ErrVariantReadFailed     ErrorCode = "VARIANT_READ_FAILED"
+	ErrVariantSKUCheckFailed ErrorCode = "VARIANT_SKU_CHECK_FAILED"
+	ErrVariantCreateFailed   ErrorCode = "VARIANT_CREATE_FAILED"
+	ErrVariantUpdateFailed   ErrorCode = "VARIANT_UPDATE_FAILED"
+	ErrVariantDeleteFailed   ErrorCode = "VARIANT_DELETE_FAILED"
+	ErrStockReserveFailed    ErrorCode = "STOCK_RESERVE_FAILED"
+	ErrStockReleaseFailed    ErrorCode = "STOCK_RELEASE_FAILED"
+	ErrStockUpdateFailed     ErrorCode = "STOCK_UPDATE_FAILED"
+)
+
+// Prices and currencies.
+const (
+	ErrExchangeRateNotFound     ErrorCode = "EXCHANGE_RATE_NOT_FOUND"
+	ErrProductPriceNotFound     ErrorCode = "PRODUCT_PRICE_NOT_FOUND"
+	ErrInvalidExchangeRate      ErrorCode = "INVALID_EXCHANGE_RATE"
+	ErrInvalidPrice             ErrorCode = "INVALID_PRICE"
+	ErrCurrencyNotAvailable     ErrorCode = "CURRENCY_NOT_AVAILABLE"
+	ErrInvalidCurrency          ErrorCode = "INVALID_CURRENCY"
+	ErrDefaultCurrencyPrice     ErrorCode = "DEFAULT_CURRENCY_PRICE"
+	ErrExchangeRateListFailed   ErrorCode = "EXCHANGE_RATE_LIST_FAILED"
+	ErrExchangeRateReadFailed   ErrorCode = "EXCHANGE_RATE_READ_FAILED"
+	ErrExchangeRateSaveFailed   ErrorCode = "EXCHANGE_RATE_SAVE_FAILED"
+	ErrExchangeRateDeleteFailed ErrorCode = "EXCHANGE_RATE_DELETE_FAILED"
+	ErrProductPriceListFailed   ErrorCode = "PRODUCT_PRICE_LIST_FAILED"
+	ErrPriceListFailed          ErrorCode = "PRICE_LIST_FAILED"
+	ErrProductPriceSaveFailed   ErrorCode = "PRODUCT_PRICE_SAVE_FAILED"
+	ErrProductPriceDeleteFailed ErrorCode = "PRODUCT_PRICE_DELETE_FAILED"
+)
+
+// Taxes.
+const (
+	ErrTaxRuleNotFound        ErrorCode = "TAX_RULE_NOT_FOUND"
+	ErrTaxRegionNotFound      ErrorCode = "TAX_REGION_NOT_FOUND"
+	ErrTaxCategoryNotFound    ErrorCode = "TAX_CATEGORY_NOT_FOUND"
+	ErrTaxRuleKeyRequired     ErrorCode = "TAX_RULE_KEY_REQUIRED"
+	ErrInvalidTaxRate         ErrorCode = "INVALID_TAX_RATE"
+	ErrTaxRuleTaken           ErrorCode = "TAX_RULE_TAKEN"
+	ErrTaxRuleListFailed      ErrorCode = "TAX_RULE_LIST_FAILED"
+	ErrTaxRuleReadFailed      ErrorCode = "TAX_RULE_READ_FAILED"
+	ErrTaxRuleCheckFailed     ErrorCode = "TAX_RULE_CHECK_FAILED"
+	ErrTaxCategoryCheckFailed ErrorCode = "TAX_CATEGORY_CHECK_FAILED"
+	ErrTaxRuleCreateFailed    ErrorCode = "TAX_RULE_CREATE_FAILED"
+	ErrTaxRuleUpdateFailed    ErrorCode = "TAX_RULE_UPDATE_FAILED"
+	ErrTaxRuleDeleteFailed    ErrorCode = "TAX_RULE_DELETE_FAILED"
+)
+
+// Promotions and coupons.
+const (
+	ErrCouponNotFound             ErrorCode = "COUPON_NOT_FOUND"
+	ErrPromotionCodeRequired      ErrorCode = "PROMOTION_CODE_REQUIRED"
+	ErrInvalidPromotionWindow     ErrorCode = "INVALID_PROMOTION_WINDOW"
+	ErrFreeProductNotFound        ErrorCode = "FREE_PRODUCT_NOT_FOUND"
+	ErrProductsNotFound           ErrorCode = "PRODUCTS_NOT_FOUND"
+	ErrInvalidPromotionType       ErrorCode = "INVALID_PROMOTION_TYPE"
+	ErrInvalidPromotionThreshold  ErrorCode = "INVALID_PROMOTION_THRESHOLD"
+	ErrInvalidDiscountPercentage  ErrorCode = "INVALID_DISCOUNT_PERCENTAGE"
+	ErrInvalidDiscountAmount      ErrorCode = "INVALID_DISCOUNT_AMOUNT"
+	ErrInvalidPromotionQuantities ErrorCode = "INVALID_PROMOTION_QUANTITIES"
+	ErrFreeProductRequired        ErrorCode = "FREE_PRODUCT_REQUIRED"
+	ErrPromotionCodeTaken         ErrorCode = "PROMOTION_CODE_TAKEN"
+	ErrCouponInactive             ErrorCode = "COUPON_INACTIVE"
+	ErrCouponExhausted            ErrorCode = "COUPON_EXHAUSTED"
+	ErrCouponAlreadyApplied       ErrorCode = "COUPON_ALREADY_APPLIED"
+	ErrCouponNotApplied           ErrorCode = "COUPON_NOT_APPLIED"
+	ErrPromotionListFailed        ErrorCode = "PROMOTION_LIST_FAILED"
+	ErrPromotionReadFailed        ErrorCode = "PROMOTION_READ_FAILED"
+	ErrPromotionCodeCheckFailed   ErrorCode = "PROMOTION_CODE_CHECK_FAILED"
+	ErrPromotionCreateFailed      ErrorCode = "PROMOTION_CREATE_FAILED"
+	ErrPromotionUpdateFailed      ErrorCode = "PROMOTION_UPDATE_FAILED"
+	ErrPromotionScopeFailed       ErrorCode = "PROMOTION_SCOPE_FAILED"
+	ErrPromotionDeleteFailed      ErrorCode = "PROMOTION_DELETE_FAILED"
+	ErrCouponRedeemFailed         ErrorCode = "COUPON_REDEEM_FAILED"
+	ErrCouponApplyFailed          ErrorCode = "COUPON_APPLY_FAILED"
+	ErrCouponRemoveFailed         ErrorCode = "COUPON_REMOVE_FAILED"
+)
+
+// Shopping carts.
+const (
+	ErrCartNotFound              ErrorCode = "CART_NOT_FOUND"
+	ErrCartLocked                ErrorCode = "CART_LOCKED"
+	ErrCartChanged               ErrorCode = "CART_CHANGED"
+	ErrCartEmpty                 ErrorCode = "CART_EMPTY"
+	ErrCartProductGone           ErrorCode = "CART_PRODUCT_GONE"
+	ErrProductNotPublished       ErrorCode = "PRODUCT_NOT_PUBLISHED"
+	ErrProductArchived           ErrorCode = "PRODUCT_ARCHIVED"
+	ErrVariantRequired           ErrorCode = "VARIANT_REQUIRED"
+	ErrCartReadFailed            ErrorCode = "CART_READ_FAILED"
+	ErrCartCreateFailed          ErrorCode = "CART_CREATE_FAILED"
+	ErrCartAddFailed             ErrorCode = "CART_ADD_FAILED"
+	ErrCartRemoveFailed          ErrorCode = "CART_REMOVE_FAILED"
+	ErrCartDeleteFailed          ErrorCode = "CART_DELETE_FAILED"
+	ErrCartCheckFailed           ErrorCode = "CART_CHECK_FAILED"
+	ErrCartCheckoutFailed        ErrorCode = "CART_CHECKOUT_FAILED"
+	ErrExpiredReservationsFailed ErrorCode = "EXPIRED_RESERVATIONS_FAILED"
+)
+
+// Orders.
+const (
+	ErrOrderNotFound             ErrorCode = "ORDER_NOT_FOUND"
+	ErrUnknownOrderAction        ErrorCode = "UNKNOWN_ORDER_ACTION"
+	ErrOrderTransitionNotAllowed ErrorCode = "ORDER_TRANSITION_NOT_ALLOWED"
+	ErrOrderStatusChanged        ErrorCode = "ORDER_STATUS_CHANGED"
+	ErrOrderCreateFailed         ErrorCode = "ORDER_CREATE_FAILED"
+	ErrOrderReadFailed           ErrorCode = "ORDER_READ_FAILED"
+	ErrOrderUpdateFailed         ErrorCode = "ORDER_UPDATE_FAILED"
+)
+
+// errorCatalog is the HTTP status of each error. Their messages are in the
+// bundles of the i18n package, keyed by the code.
+var errorCatalog = map[ErrorCode]int{
+	ErrInternalError:              http.StatusInternalServerError,
+	ErrTransactionFailed:          http.StatusInternalServerError,
+	ErrValidationFailed:           http.StatusBadRequest,
+	ErrMalformedBody:              http.StatusBadRequest,
+	ErrVersionConflict:            http.StatusPreconditionFailed,
+	ErrInvalidPage:                http.StatusBadRequest,
+	ErrInvalidCursor:              http.StatusBadRequest,
+	ErrCursorSortMismatch:         http.StatusBadRequest,
+	ErrPageWithCursor:             http.StatusBadRequest,
+	ErrCategoryNotFound:           http.StatusNotFound,
+	ErrCategoriesNotFound:         http.StatusBadRequest,
+	ErrParentCategoryNotFound:     http.StatusBadRequest,
+	ErrCategoryCycle:              http.StatusBadRequest,
+	ErrCategoryHasChildren:        http.StatusConflict,
+	ErrCategoryListFailed:         http.StatusInternalServerError,
+	ErrCategoryReadFailed:         http.StatusInternalServerError,
+	ErrCategoryCreateFailed:       http.StatusInternalServerError,
+	ErrCategoryUpdateFailed:       http.StatusInternalServerError,
+	ErrCategoryDeleteFailed:       http.StatusInternalServerError,
+	ErrProductNotFound:            http.StatusNotFound,
+	ErrProductCodeNotFound:        http.StatusNotFound,
+	ErrProductCodeTaken:           http.StatusConflict,
+	ErrConcurrentModification:     http.StatusConflict,
+	ErrMissingReference:           http.StatusUnprocessableEntity,
+	ErrProductCategoriesNotFound:  http.StatusUnprocessableEntity,
+	ErrProductNotDeleted:          http.StatusConflict,
+	ErrInvalidProductPrice:        http.StatusBadRequest,
+	ErrInvalidProductStatus:       http.StatusBadRequest,
+	ErrInvalidPublishWindow:       http.StatusBadRequest,
+	ErrInvalidPriceFilter:         http.StatusBadRequest,
+	ErrInvalidMinPrice:            http.StatusBadRequest,
+	ErrInvalidMaxPrice:            http.StatusBadRequest,
+	ErrPriceRangeInverted:         http.StatusBadRequest,
+	ErrInvalidCreatedAfter:        http.StatusBadRequest,
+	ErrInvalidCreatedBefore:       http.StatusBadRequest,
+	ErrDateRangeInverted:          http.StatusBadRequest,
+	ErrInvalidSort:                http.StatusBadRequest,
+	ErrUnsupportedSortField:       http.StatusBadRequest,
+	ErrInvalidPurgeAge:            http.StatusBadRequest,
+	ErrNegativePurgeAge:           http.StatusBadRequest,
+	ErrUnsupportedPatchType:       http.StatusUnsupportedMediaType,
+	ErrPatchTooLarge:              http.StatusRequestEntityTooLarge,
+	ErrMalformedPatch:             http.StatusBadRequest,
+	ErrInvalidPatchOperations:     http.StatusBadRequest,
+	ErrPatchTestFailed:            http.StatusConflict,
+	ErrPatchNotApplicable:         http.StatusUnprocessableEntity,
+	ErrPatchResultNotObject:       http.StatusUnprocessableEntity,
+	ErrProductCountFailed:         http.StatusInternalServerError,
+	ErrProductListFailed:          http.StatusInternalServerError,
+	ErrProductSearchFailed:        http.StatusInternalServerError,
+	ErrPriceFacetsFailed:          http.StatusInternalServerError,
+	ErrCategoryFacetsFailed:       http.StatusInternalServerError,
+	ErrDateFacetsFailed:           http.StatusInternalServerError,
+	ErrAttributeFacetsFailed:      http.StatusInternalServerError,
+	ErrProductReadFailed:          http.StatusInternalServerError,
+	ErrProductCreateFailed:        http.StatusInternalServerError,
+	ErrProductUpdateFailed:        http.StatusInternalServerError,
+	ErrProductVersionFailed:       http.StatusInternalServerError,
+	ErrProductCategoriesFailed:    http.StatusInternalServerError,
+	ErrProductDeleteFailed:        http.StatusInternalServerError,
+	ErrProductPublishFailed:       http.StatusInternalServerError,
+	ErrProductArchiveFailed:       http.StatusInternalServerError,
+	ErrDeletedProductCountFailed:  http.StatusInternalServerError,
+	ErrDeletedProductListFailed:   http.StatusInternalServerError,
+	ErrProductRestoreFailed:       http.StatusInternalServerError,
+	ErrPurgeListFailed:            http.StatusInternalServerError,
+	ErrProductPurgeFailed:         http.StatusInternalServerError,
+	ErrUnsupportedExportFormat:    http.StatusBadRequest,
+	ErrInvalidExportColumn:        http.StatusBadRequest,
+	ErrUnsupportedImportFormat:    http.StatusBadRequest,
+	ErrImportFileMissing:          http.StatusBadRequest,
+	ErrImportHeaderUnreadable:     http.StatusBadRequest,
+	ErrUnknownImportColumn:        http.StatusBadRequest,
+	ErrImportFileUnreadable:       http.StatusBadRequest,
+	ErrInvalidCSVLine:             http.StatusBadRequest,
+	ErrCSVColumnCountMismatch:     http.StatusBadRequest,
+	ErrInvalidJSONLine:            http.StatusBadRequest,
+	ErrInvalidProductData:         http.StatusBadRequest,
+	ErrUnknownImportField:         http.StatusBadRequest,
+	ErrInvalidImportPrice:         http.StatusBadRequest,
+	ErrInvalidImportStock:         http.StatusBadRequest,
+	ErrInvalidImportCategories:    http.StatusBadRequest,
+	ErrInvalidImportPublishAt:     http.StatusBadRequest,
+	ErrInvalidImportUnpublishAt:   http.StatusBadRequest,
+	ErrVariantNotFound:            http.StatusNotFound,
+	ErrVariantSKURequired:         http.StatusBadRequest,
+	ErrInvalidVariantPrice:        http.StatusBadRequest,
+	ErrVariantSKUTaken:            http.StatusConflict,
+	ErrInsufficientStock:          http.StatusConflict,
+	ErrVariantReadFailed:          http.StatusInternalServerError,
+	ErrVariantSKUCheckFailed:      http.StatusInternalServerError,
+	ErrVariantCreateFailed:        http.StatusInternalServerError,
+	ErrVariantUpdateFailed:        http.StatusInternalServerError,
+	ErrVariantDeleteFailed:        http.StatusInternalServerError,
+	ErrStockReserveFailed:         http.StatusInternalServerError,
+	ErrStockReleaseFailed:         http.StatusInternalServerError,
+	ErrStockUpdateFailed:          http.StatusInternalServerError,
+	ErrExchangeRateNotFound:       http.StatusNotFound,
+	ErrProductPriceNotFound:       http.StatusNotFound,
+	ErrInvalidExchangeRate:        http.StatusBadRequest,
+	ErrInvalidPrice:               http.StatusBadRequest,
+	ErrCurrencyNotAvailable:       http.StatusBadRequest,
+	ErrInvalidCurrency:            http.StatusBadRequest,
+	ErrDefaultCurrencyPrice:       http.StatusBadRequest,
+	ErrExchangeRateListFailed:     http.StatusInternalServerError,
+	ErrExchangeRateReadFailed:     http.StatusInternalServerError,
+	ErrExchangeRateSaveFailed:     http.StatusInternalServerError,
+	ErrExchangeRateDeleteFailed:   http.StatusInternalServerError,
+	ErrProductPriceListFailed:     http.StatusInternalServerError,
+	ErrPriceListFailed:            http.StatusInternalServerError,
+	ErrProductPriceSaveFailed:     http.StatusInternalServerError,
+	ErrProductPriceDeleteFailed:   http.StatusInternalServerError,
+	ErrTaxRuleNotFound:            http.StatusNotFound,
+	ErrTaxRegionNotFound:          http.StatusBadRequest,
+	ErrTaxCategoryNotFound:        http.StatusBadRequest,
+	ErrTaxRuleKeyRequired:         http.StatusBadRequest,
+	ErrInvalidTaxRate:             http.StatusBadRequest,
+	ErrTaxRuleTaken:               http.StatusConflict,
+	ErrTaxRuleListFailed:          http.StatusInternalServerError,
+	ErrTaxRuleReadFailed:          http.StatusInternalServerError,
+	ErrTaxRuleCheckFailed:         http.StatusInternalServerError,
+	ErrTaxCategoryCheckFailed:     http.StatusInternalServerError,
+	ErrTaxRuleCreateFailed:        http.StatusInternalServerError,
+	ErrTaxRuleUpdateFailed:        http.StatusInternalServerError,
+	ErrTaxRuleDeleteFailed:        http.StatusInternalServerError,
+	ErrCouponNotFound:             http.StatusNotFound,
+	ErrPromotionCodeRequired:      http.StatusBadRequest,
+	ErrInvalidPromotionWindow:     http.StatusBadRequest,
+	ErrFreeProductNotFound:        http.StatusBadRequest,
+	ErrProductsNotFound:           http.StatusBadRequest,
+	ErrInvalidPromotionType:       http.StatusBadRequest,
+	ErrInvalidPromotionThreshold:  http.StatusBadRequest,
+	ErrInvalidDiscountPercentage:  http.StatusBadRequest,
+	ErrInvalidDiscountAmount:      http.StatusBadRequest,
+	ErrInvalidPromotionQuantities: http.StatusBadRequest,
+	ErrFreeProductRequired:        http.StatusBadRequest,
+	ErrPromotionCodeTaken:         http.StatusConflict,
+	ErrCouponInactive:             http.StatusConflict,
+	ErrCouponExhausted:            http.StatusConflict,
+	ErrCouponAlreadyApplied:       http.StatusConflict,
+	ErrCouponNotApplied:           http.StatusNotFound,
+	ErrPromotionListFailed:        http.StatusInternalServerError,
+	ErrPromotionReadFailed:        http.StatusInternalServerError,
+	ErrPromotionCodeCheckFailed:   http.StatusInternalServerError,
+	ErrPromotionCreateFailed:      http.StatusInternalServerError,
+	ErrPromotionUpdateFailed:      http.StatusInternalServerError,
+	ErrPromotionScopeFailed:       http.StatusInternalServerError,
+	ErrPromotionDeleteFailed:      http.StatusInternalServerError,
+	ErrCouponRedeemFailed:         http.StatusInternalServerError,
+	ErrCouponApplyFailed:          http.StatusInternalServerError,
+	ErrCouponRemoveFailed:         http.StatusInternalServerError,
+	ErrCartNotFound:               http.StatusNotFound,
+	ErrCartLocked:                 http.StatusConflict,
+	ErrCartChanged:                http.StatusConflict,
+	ErrCartEmpty:                  http.StatusBadRequest,
+	ErrCartProductGone:            http.StatusConflict,
+	ErrProductNotPublished:        http.StatusConflict,
+	ErrProductArchived:            http.StatusConflict,
+	ErrVariantRequired:            http.StatusBadRequest,
+	ErrCartReadFailed:             http.StatusInternalServerError,
+	ErrCartCreateFailed:           http.StatusInternalServerError,
+	ErrCartAddFailed:              http.StatusInternalServerError,
+	ErrCartRemoveFailed:           http.StatusInternalServerError,
+	ErrCartDeleteFailed:           http.StatusInternalServerError,
+	ErrCartCheckFailed:            http.StatusInternalServerError,
+	ErrCartCheckoutFailed:         http.StatusInternalServerError,
+	ErrExpiredReservationsFailed:  http.StatusInternalServerError,
+	ErrOrderNotFound:              http.StatusNotFound,
+	ErrUnknownOrderAction:         http.StatusBadRequest,
+	ErrOrderTransitionNotAllowed:  http.StatusConflict,
+	ErrOrderStatusChanged:         http.StatusConflict,
+	ErrOrderCreateFailed:          http.StatusInternalServerError,
+	ErrOrderReadFailed:            http.StatusInternalServerError,
+	ErrOrderUpdateFailed:          http.StatusInternalServerError,
+}
@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"codifin-challenge/domain/service"
+	"codifin-challenge/domain/utils"
+	"codifin-challenge/infrastructure/web/dto"
+	"codifin-challenge/infrastructure/web/responses"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type CategoryController struct {
+	categoryService service.CategoryService
+}
+
+func NewCategoryController(categoryService service.CategoryService) *CategoryController {
+	return &CategoryController{categoryService: categoryService}
+}
+
+// FindCategoryTree
+// @Summary Get the category tree
+// @Description Retrieves every category nested under its parent, to render the catalog navigation
+// @Tags Categories
+// @ID find-category-tree
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.CategoryTreeDTO "Root categories with their descendants"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve categories"
+// @Router /categories [get]
+func (ctrl *CategoryController) FindCategoryTree(c *gin.Context) {
+	categories, err := ctrl.categoryService.CategoryTree()
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	treeDTO := dto.ToCategoriesTreeDTO(categories)
+	responses.SendSuccess(c, http.StatusOK, treeDTO)
+}
+
+// FindCategory
+// @Summary Get a category by ID
+// @Description Retrieves a category with its direct subcategories by its ID
+// @Tags Categories
+// @ID find-category
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} dto.CategoryTreeDTO "Category found"
+// @Failure 404 {object} responses.ProblemDTO "Category does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve category"
+// @Router /category/{id} [get]
+func (ctrl *CategoryController) FindCategory(c *gin.Context) {
+	categoryID, _ := strconv.Atoi(c.Param("id"))
+
+	category, err := ctrl.categoryService.CategoryByID(uint(categoryID))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	categoryDTO := dto.ToCategoryTreeDTO(category)
+	responses.SendSuccess(c, http.StatusOK, categoryDTO)
+}
+
+// NewCategory
+// @Summary Create a new category
+// @Description Creates a new category, optionally nested under a parent category
+// @Tags Categories
+// @ID new-category
+// @Accept json
+// @Produce json
+// @Param data body dto.CategoryData true "Category data"
+// @Success 201 {object} dto.CategoryDTO "Created category"
+// @Failure 400 {object} responses.ProblemDTO "Invalid category data or parent"
+// @Failure 500 {object} responses.ProblemDTO "Failed to create category"
+// @Router /categories [post]
+func (ctrl *CategoryController) NewCategory(c *gin.Context) {
+	var categoryData dto.CategoryData
+
+	if err := c.ShouldBindJSON(&categoryData); err != nil {
+		responses.SendError(c, dto.BindingError(&categoryData, err))
+		return
+	}
+
+	newCategory := categoryData.ToCategory()
+
+	if err := ctrl.categoryService.CreateCategory(newCategory); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	categoryDTO := dto.ToCategoryDTO(newCategory)
+	responses.SendSuccess(c, http.StatusCreated, categoryDTO)
+}
+
+// UpdateCategory
+// @Summary Update a category
+// @Description Replaces the name and parent of a category
+// @Tags Categories
+// @ID update-category
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param data body dto.CategoryData true "Category data"
+// @Success 200 {object} dto.CategoryDTO "Updated category"
+// @Failure 400 {object} responses.ProblemDTO "Invalid category data or parent"
+// @Failure 404 {object} responses.ProblemDTO "Category does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to update category"
+// @Router /category/{id} [put]
+func (ctrl *CategoryController) UpdateCategory(c *gin.Context) {
+	categoryID, _ := strconv.Atoi(c.Param("id"))
+
+	var categoryData dto.CategoryData
+	if err := c.ShouldBindJSON(&categoryData); err != nil {
+		responses.SendError(c, dto.BindingError(&categoryData, err))
+		return
+	}
+
+	category := categoryData.ToCategory()
+	category.ID = uint(categoryID)
+
+	if err := ctrl.categoryService.UpdateCategory(category); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	categoryDTO := dto.ToCategoryDTO(category)
+	responses.SendSuccess(c, http.StatusOK, categoryDTO)
+}
+
+// RemoveCategory
+// @Summary Delete a category
+// @Description Deletes a category without subcategories and unassigns it from its products
+// @Tags Categories
+// @ID remove-category
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} responses.SuccessDTO "Category deleted successfully"
+// @Failure 404 {object} responses.ProblemDTO "Category does not exist"
+// @Failure 409 {object} responses.ProblemDTO "Category has subcategories"
+// @Failure 500 {object} responses.ProblemDTO "Failed to delete category"
+// @Router /category/{id} [delete]
+func (ctrl *CategoryController) RemoveCategory(c *gin.Context) {
+	categoryID, _ := strconv.Atoi(c.Param("id"))
+
+	if err := ctrl.categoryService.DeleteCategory(uint(categoryID)); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	resp := responses.NewSuccess(responses.CategoryDeleted)
+	responses.SendSuccess(c, http.StatusOK, resp)
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/service"
+	"codifin-challenge/domain/utils"
+	"codifin-challenge/infrastructure/web/dto"
+	"codifin-challenge/infrastructure/web/responses"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// orderActions maps the transition endpoints to the status they move an order to.
+var orderActions = map[string]model.OrderStatus{
+	"pay":     model.OrderPaid,
+	"ship":    model.OrderShipped,
+	"deliver": model.OrderDelivered,
+	"cancel":  model.OrderCancelled,
+	"refund":  model.OrderRefunded,
+}
+
+type OrderController struct {
+	orderService service.OrderService
+}
+
+func NewOrderController(orderService service.OrderService) *OrderController {
+	return &OrderController{orderService: orderService}
+}
+
+// Checkout
+// @Summary Checkout a shopping cart
+// @Description Turns a shopping cart into a pending order, freezing its items and unit prices and locking the cart against further changes
+// @Tags Orders
+// @ID checkout
+// @Accept json
+// @Produce json
+// @Param id path int true "Shopping cart ID"
+// @Success 201 {object} dto.OrderDTO "Created order"
+// @Failure 400 {object} responses.ProblemDTO "Empty shopping cart"
+// @Failure 404 {object} responses.ProblemDTO "Shopping cart does not exist"
+// @Failure 409 {object} responses.ProblemDTO "Shopping cart already checked out, changed during the checkout or not enough stock"
+// @Failure 500 {object} responses.ProblemDTO "Failed to create order"
+// @Router /cart/{id}/checkout [post]
+func (ctrl *OrderController) Checkout(c *gin.Context) {
+	cartID, _ := strconv.Atoi(c.Param("id"))
+
+	order, err := ctrl.orderService.Checkout(uint(cartID))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	orderDTO := dto.ToOrderDTO(order)
+	responses.SendSuccess(c, http.StatusCreated, orderDTO)
+}
+
+// FindOrder
+// @Summary Get an order by ID
+// @Description Retrieves an order with its lines by its ID
+// @Tags Orders
+// @ID find-order
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} dto.OrderDTO "Order found"
+// @Failure 404 {object} responses.ProblemDTO "Order does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve order"
+// @Router /order/{id} [get]
+func (ctrl *OrderController) FindOrder(c *gin.Context) {
+	orderID, _ := strconv.Atoi(c.Param("id"))
+
+	order, err := ctrl.orderService.FindOrder(uint(orderID))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	orderDTO := dto.ToOrderDTO(order)
+	responses.SendSuccess(c, http.StatusOK, orderDTO)
+}
+
+// ChangeOrderStatus
+// @Summary Move an order to another status
+// @Description Applies a transition to an order. Allowed transitions are pending → paid|cancelled, paid → shipped|refunded, shipped → delivered and delivered → refunded.
+// @Tags Orders
+// @ID change-order-status
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param action path string true "Transition to apply" Enums(pay, ship, deliver, cancel, refund)
+// @Success 200 {object} dto.OrderDTO "Updated order"
+// @Failure 400 {object} responses.ProblemDTO "Unknown transition"
+// @Failure 404 {object} responses.ProblemDTO "Order does not exist"
+// @Failure 409 {object} responses.ProblemDTO "Transition not allowed from the current status"
+// @Failure 500 {object} responses.ProblemDTO "Failed to update order"
+// @Router /order/{id}/{action} [post]
+func (ctrl *OrderController) ChangeOrderStatus(c *gin.Context) {
+	orderID, _ := strconv.Atoi(c.Param("id"))
+
+	status, ok := orderActions[c.Param("action")]
+	if !ok {
+		err := fmt.Errorf("unknown order action '%s'", c.Param("action"))
+		responses.SendError(c, utils.NewError(utils.ErrUnknownOrderAction, err))
+		return
+	}
+
+	order, err := ctrl.orderService.ChangeStatus(uint(orderID), status)
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	orderDTO := dto.ToOrderDTO(order)
+	responses.SendSuccess(c, http.StatusOK, orderDTO)
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"codifin-challenge/domain/utils"
+	"codifin-challenge/infrastructure/web/responses"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strings"
+)
+
+// etag returns the strong entity tag of the body of a response, a hash of its
+// JSON. It changes with anything the body is made of, such as the prices,
+// promotions, taxes and exchange rates of a cart or the language of its
+// messages, and not only with the version of the resource.
+func etag(body interface{}) string {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return "\"" + hex.EncodeToString(sum[:16]) + "\""
+}
+
+// setETag sets the entity tag of the body of a response. The body depends on
+// the requested currency and language, so caches must keep a copy per each.
+func setETag(c *gin.Context, tag string) {
+	if tag != "" {
+		c.Header("ETag", tag)
+	}
+	c.Writer.Header().Add("Vary", "Accept-Currency")
+}
+
+// notModified answers 304 if the If-None-Match header of a request names the
+// entity tag of the body the resource has now, and reports whether it did.
+// Tags are compared weakly, ignoring the W/ prefix.
+func notModified(c *gin.Context, tag string) bool {
+	header := strings.TrimSpace(c.GetHeader("If-None-Match"))
+	if header == "" || tag == "" {
+		return false
+	}
+
+	for _, v := range entityTags(header) {
+		if v == "*" || strings.TrimPrefix(v, "W/") == tag {
+			setETag(c, tag)
+			responses.SendNotModified(c)
+			return true
+		}
+	}
+
+	return false
+}
+
+// ifMatch evaluates the If-Match header of a request against the current
+// representation of the resource, given by current with its version. As in
+// RFC 9110, * matches any current representation and a list matches if any
+// of its tags does. Tags are compared strongly, so weak tags never match. It
+// returns the version a change must be made on, or nil if it may be made on
+// any.
+func ifMatch(c *gin.Context, current func() (interface{}, uint, error)) (*uint, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return nil, nil
+	}
+
+	body, version, err := current()
+	if err != nil {
+		return nil, err
+	}
+
+	tag := etag(body)
+	for _, v := range entityTags(header) {
+		if v == "*" {
+			return nil, nil
+		}
+		if v == tag {
+			return &version, nil
+		}
+	}
+
+	return nil, utils.NewError(utils.ErrVersionConflict, fmt.Errorf("If-Match %q does not match the entity tag %s", header, tag))
+}
+
+// entityTags splits the comma separated entity tags of a conditional header.
+// Weak tags keep their W/ prefix, and parsing stops at the first malformed
+// one.
+func entityTags(header string) []string {
+	var tags []string
+
+	for rest := header; ; {
+		rest = strings.TrimLeft(rest, " \t,")
+		if rest == "" {
+			return tags
+		}
+
+		if rest[0] == '*' {
+			tags = append(tags, "*")
+			rest = rest[1:]
+			continue
+		}
+
+		weak := strings.HasPrefix(rest, "W/")
+		if weak {
+			rest = rest[2:]
+		}
+
+		if !strings.HasPrefix(rest, "\"") {
+			return tags
+		}
+
+		end := strings.IndexByte(rest[1:], '"')
+		if end < 0 {
+			return tags
+		}
+
+		tag := rest[:end+2]
+		if weak {
+			tag = "W/" + tag
+		}
+		tags = append(tags, tag)
+		rest = rest[end+2:]
+	}
+}
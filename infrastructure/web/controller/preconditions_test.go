@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"codifin-challenge/domain/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func conditionalContext(header, value string) (*gin.Context, *httptest.ResponseRecorder) {
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if value != "" {
+		c.Request.Header.Set(header, value)
+	}
+	return c, recorder
+}
+
+// Test_ifMatch tests that If-Match matches * and any tag of a list strongly, and that weak tags never match.
+func Test_ifMatch(t *testing.T) {
+	body := map[string]int{"version": 3}
+	tag := etag(body)
+	current := func() (interface{}, uint, error) { return body, 3, nil }
+
+	cases := []struct {
+		header   string
+		version  *uint
+		conflict bool
+	}{
+		{"", nil, false},
+		{"*", nil, false},
+		{tag, new(uint), false},
+		{`"other", ` + tag, new(uint), false},
+		{"W/" + tag, nil, true},
+		{`"other"`, nil, true},
+		{"3", nil, true},
+	}
+
+	for _, v := range cases {
+		c, _ := conditionalContext("If-Match", v.header)
+		version, err := ifMatch(c, current)
+
+		if v.conflict {
+			var e *utils.DBError
+			if !errors.As(err, &e) || e.Code != utils.ErrVersionConflict || e.Status != http.StatusPreconditionFailed {
+				t.Errorf("Expected If-Match %s to fail with a version conflict, got %v", v.header, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Expected If-Match %s to match, got %v", v.header, err)
+		} else if (v.version == nil) != (version == nil) || (version != nil && *version != 3) {
+			t.Errorf("Expected If-Match %s to give version %v, got %v", v.header, v.version, version)
+		}
+	}
+
+	missing := errors.New("missing")
+	c, _ := conditionalContext("If-Match", "*")
+	if _, err := ifMatch(c, func() (interface{}, uint, error) { return nil, 0, missing }); err != missing {
+		t.Errorf("Expected the error finding the resource, got %v", err)
+	}
+}
+
+// Test_notModified tests that If-None-Match compares tags weakly and answers 304 with the headers of the full response.
+func Test_notModified(t *testing.T) {
+	tag := etag(map[string]string{"name": "Taza"})
+
+	cases := []struct {
+		header   string
+		expected bool
+	}{
+		{"", false},
+		{"*", true},
+		{tag, true},
+		{"W/" + tag, true},
+		{`"other", W/` + tag, true},
+		{`"other"`, false},
+	}
+
+	for _, v := range cases {
+		c, recorder := conditionalContext("If-None-Match", v.header)
+		if notModified(c, tag) != v.expected {
+			t.Errorf("Expected If-None-Match %s to be not modified %v", v.header, v.expected)
+			continue
+		}
+
+		if !v.expected {
+			continue
+		}
+
+		c.Writer.WriteHeaderNow()
+		vary := strings.Join(recorder.Header().Values("Vary"), ", ")
+		if recorder.Code != http.StatusNotModified || recorder.Header().Get("ETag") != tag ||
+			!strings.Contains(vary, "Accept-Currency") || !strings.Contains(vary, "Accept-Language") {
+			t.Errorf("Expected 304 with the tag and Vary headers, got %d %v", recorder.Code, recorder.Header())
+		}
+	}
+}
+
+// Test_etag tests that the tag changes with anything in the body, not only with its version.
+func Test_etag(t *testing.T) {
+	a := etag(map[string]interface{}{"version": 1, "total": "10.00"})
+	b := etag(map[string]interface{}{"version": 1, "total": "12.00"})
+
+	if a == b || !strings.HasPrefix(a, "\"") || !strings.HasSuffix(a, "\"") {
+		t.Errorf("Expected distinct strong tags, got %s and %s", a, b)
+	}
+}
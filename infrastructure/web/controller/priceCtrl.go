@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"codifin-challenge/domain/service"
+	"codifin-challenge/domain/utils"
+	"codifin-challenge/infrastructure/web/dto"
+	"codifin-challenge/infrastructure/web/responses"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type PriceController struct {
+	priceService service.PriceService
+}
+
+func NewPriceController(priceService service.PriceService) *PriceController {
+	return &PriceController{priceService: priceService}
+}
+
+// requestCurrency returns the currency prices are requested in: the currency
+// query parameter, or else the Accept-Currency header.
+func requestCurrency(c *gin.Context) string {
+	currency := c.Query("currency")
+	if currency == "" {
+		currency = c.GetHeader("Accept-Currency")
+	}
+	return strings.TrimSpace(strings.ToUpper(currency))
+}
+
+// FindExchangeRates
+// @Summary Get the list of exchange rates
+// @Description Retrieves the units of every currency that one unit of the default currency buys
+// @Tags Prices
+// @ID find-exchange-rates
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.ExchangeRateDTO "List of exchange rates"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve exchange rates"
+// @Router /admin/exchange-rates [get]
+func (ctrl *PriceController) FindExchangeRates(c *gin.Context) {
+	rates, err := ctrl.priceService.ExchangeRates()
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	ratesDTO := dto.ToExchangeRatesDTO(rates)
+	responses.SendSuccess(c, http.StatusOK, ratesDTO)
+}
+
+// SetExchangeRate
+// @Summary Set the exchange rate of a currency
+// @Description Creates or replaces the units of a currency that one unit of the default currency buys. Products without a price in the price list of the currency are sold at their converted price.
+// @Tags Prices
+// @ID set-exchange-rate
+// @Accept json
+// @Produce json
+// @Param currency path string true "ISO 4217 currency code"
+// @Param data body dto.ExchangeRateData true "Exchange rate data"
+// @Success 200 {object} dto.ExchangeRateDTO "Saved exchange rate"
+// @Failure 400 {object} responses.ProblemDTO "Invalid currency or rate"
+// @Failure 500 {object} responses.ProblemDTO "Failed to save exchange rate"
+// @Router /admin/exchange-rate/{currency} [put]
+func (ctrl *PriceController) SetExchangeRate(c *gin.Context) {
+	var rateData dto.ExchangeRateData
+	if err := c.ShouldBindJSON(&rateData); err != nil {
+		responses.SendError(c, dto.BindingError(&rateData, err))
+		return
+	}
+
+	rate := rateData.ToExchangeRate(c.Param("currency"))
+
+	if err := ctrl.priceService.SetExchangeRate(rate); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	rateDTO := dto.ToExchangeRateDTO(rate)
+	responses.SendSuccess(c, http.StatusOK, rateDTO)
+}
+
+// RemoveExchangeRate
+// @Summary Delete the exchange rate of a currency
+// @Description Deletes the exchange rate of a currency, so prices are no longer offered in it
+// @Tags Prices
+// @ID remove-exchange-rate
+// @Accept json
+// @Produce json
+// @Param currency path string true "ISO 4217 currency code"
+// @Success 200 {object} responses.SuccessDTO "Exchange rate deleted successfully"
+// @Failure 404 {object} responses.ProblemDTO "Currency has no exchange rate"
+// @Failure 500 {object} responses.ProblemDTO "Failed to delete exchange rate"
+// @Router /admin/exchange-rate/{currency} [delete]
+func (ctrl *PriceController) RemoveExchangeRate(c *gin.Context) {
+	currency := strings.TrimSpace(strings.ToUpper(c.Param("currency")))
+
+	if err := ctrl.priceService.DeleteExchangeRate(currency); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	resp := responses.NewSuccess(responses.ExchangeRateDeleted)
+	responses.SendSuccess(c, http.StatusOK, resp)
+}
+
+// FindProductPrices
+// @Summary Get the price lists of a product
+// @Description Retrieves the prices of a product in the price list of every currency it has one
+// @Tags Prices
+// @ID find-product-prices
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} dto.ProductPriceDTO "List of product prices"
+// @Failure 404 {object} responses.ProblemDTO "Product does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve product prices"
+// @Router /admin/product/{id}/prices [get]
+func (ctrl *PriceController) FindProductPrices(c *gin.Context) {
+	productID, _ := strconv.Atoi(c.Param("id"))
+
+	prices, err := ctrl.priceService.ProductPrices(uint(productID))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	pricesDTO := dto.ToProductPricesDTO(prices)
+	responses.SendSuccess(c, http.StatusOK, pricesDTO)
+}
+
+// SetProductPrice
+// @Summary Set the price of a product in a currency
+// @Description Creates or replaces the price of a product in the price list of the currency of the price, which can not be the default currency
+// @Tags Prices
+// @ID set-product-price
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param data body dto.ProductPriceData true "Product price data"
+// @Success 200 {object} dto.ProductPriceDTO "Saved product price"
+// @Failure 400 {object} responses.ProblemDTO "Invalid price or currency"
+// @Failure 404 {object} responses.ProblemDTO "Product does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to save product price"
+// @Router /admin/product/{id}/prices [put]
+func (ctrl *PriceController) SetProductPrice(c *gin.Context) {
+	productID, _ := strconv.Atoi(c.Param("id"))
+
+	var priceData dto.ProductPriceData
+	if err := c.ShouldBindJSON(&priceData); err != nil {
+		responses.SendError(c, dto.BindingError(&priceData, err))
+		return
+	}
+
+	price := priceData.ToProductPrice(uint(productID))
+
+	if err := ctrl.priceService.SetProductPrice(price); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	priceDTO := dto.ToProductPriceDTO(price)
+	responses.SendSuccess(c, http.StatusOK, priceDTO)
+}
+
+// RemoveProductPrice
+// @Summary Delete the price of a product in a currency
+// @Description Deletes the price of a product in the price list of a currency, so it is sold at its converted price
+// @Tags Prices
+// @ID remove-product-price
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param currency path string true "ISO 4217 currency code"
+// @Success 200 {object} responses.SuccessDTO "Product price deleted successfully"
+// @Failure 404 {object} responses.ProblemDTO "Product has no price in the currency"
+// @Failure 500 {object} responses.ProblemDTO "Failed to delete product price"
+// @Router /admin/product/{id}/prices/{currency} [delete]
+func (ctrl *PriceController) RemoveProductPrice(c *gin.Context) {
+	productID, _ := strconv.Atoi(c.Param("id"))
+	currency := strings.TrimSpace(strings.ToUpper(c.Param("currency")))
+
+	if err := ctrl.priceService.DeleteProductPrice(uint(productID), currency); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	resp := responses.NewSuccess(responses.ProductPriceDeleted)
+	responses.SendSuccess(c, http.StatusOK, resp)
+}
@@ -1,13 +1,19 @@
 package controller
 
 import (
+	"codifin-challenge/domain/model"
 	"codifin-challenge/domain/service"
 	"codifin-challenge/domain/utils"
 	"codifin-challenge/infrastructure/web/dto"
 	"codifin-challenge/infrastructure/web/responses"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type ProductController struct {
@@ -20,84 +26,470 @@ func NewProductController(productService service.ProductService) *ProductControl
 
 // FindProducts
 // @Summary Get a paginated and filtered list of products
-// @Description Retrieves a paginated and filtered list of products based on search criteria
+// @Description Retrieves a paginated and filtered list of the active products based on search criteria
 // @Tags Products
 // @ID find-products
 // @Accept json
 // @Produce json
-// @Param page query int true "Page number" minimum(1) "The page number for pagination"
+// @Param page query int false "Page number" minimum(1) "The page number for pagination. Without it products are paged by cursor."
 // @Param pageSize query int true "Page size" minimum(1) "The number of products per page"
-// @Param searchTerm query string false "Search term to filter products by code or name"
-// @Param orderBy query string false "Field to order results by. Can be 'name', 'price', or 'code'. Default is 'price'."
-// @Param ascending query bool false "Whether to order results in ascending or descending order. Default is true."
-// @Success 200 {object} dto.ProductsListResp "Paginated and filtered list of products"
-// @Failure 400 {object} responses.ErrorDTO "Invalid page, pageSize, or search parameters"
-// @Failure 500 {object} responses.ErrorDTO "Failed to retrieve products"
+// @Param cursor query string false "Opaque cursor of the page to list, the nextCursor or prevCursor of a previous response with the same sort. Without it the first page is listed. Cannot be combined with page."
+// @Param searchTerm query string false "Search term to find products by code, name or description, regardless of case and accents, by word prefixes and with typos. Every word must match, and every product found has a relevance score."
+// @Param sort query string false "Fields to sort results by, separated by commas and prefixed with '-' to sort descending, such as -price,name. Can be 'id', 'code', 'name', 'price' or 'createdAt'. Ties are broken by id. Default is relevance when searching and price otherwise."
+// @Param orderBy query string false "Deprecated, use sort. Field to order results by, when sort is omitted."
+// @Param ascending query bool false "Deprecated, use sort. Whether orderBy orders results in ascending order. Default is true."
+// @Param category query int false "Category ID to filter products by"
+// @Param includeDescendants query bool false "Whether the category filter also matches its descendant categories. Default is false."
+// @Param minPrice query string false "Lowest price of the products, inclusive, as a decimal amount in the store currency"
+// @Param maxPrice query string false "Highest price of the products, inclusive, as a decimal amount in the store currency"
+// @Param createdAfter query string false "Products created from this moment on, as an RFC 3339 timestamp or a YYYY-MM-DD date"
+// @Param createdBefore query string false "Products created before this moment, as an RFC 3339 timestamp or a YYYY-MM-DD date"
+// @Param attr[name] query string false "Attribute a variant of the products must have, such as attr[color]=red. Can be repeated for different attributes, which the same variant must have."
+// @Param currency query string false "Currency of the prices, the default currency if omitted"
+// @Param Accept-Currency header string false "Currency of the prices when the currency parameter is omitted"
+// @Success 200 {object} dto.ProductsListResp "Paginated and filtered list of products, with the facets of all the products that pass the filters and, when paged by cursor, the cursors of the adjacent pages"
+// @Failure 400 {object} responses.ProblemDTO "Invalid page, pageSize, cursor, sort, search or filter parameters, or unavailable currency"
+// @Failure 404 {object} responses.ProblemDTO "Category does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve products"
 // @Router /products [get]
 func (ctrl *ProductController) FindProducts(c *gin.Context) {
+	ctrl.findProducts(c, []model.ProductStatus{model.ProductActive})
+}
+
+// FindAdminProducts
+// @Summary Get a paginated and filtered list of products in any status
+// @Description Retrieves a paginated and filtered list of products like the product list, including drafts and archived products
+// @Tags Products
+// @ID find-admin-products
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" minimum(1) "The page number for pagination. Without it products are paged by cursor."
+// @Param pageSize query int true "Page size" minimum(1) "The number of products per page"
+// @Param cursor query string false "Opaque cursor of the page to list, the nextCursor or prevCursor of a previous response with the same sort. Without it the first page is listed. Cannot be combined with page."
+// @Param searchTerm query string false "Search term to find products by code, name or description, regardless of case and accents, by word prefixes and with typos. Every word must match, and every product found has a relevance score."
+// @Param sort query string false "Fields to sort results by, separated by commas and prefixed with '-' to sort descending, such as -price,name. Can be 'id', 'code', 'name', 'price' or 'createdAt'. Ties are broken by id. Default is relevance when searching and price otherwise."
+// @Param orderBy query string false "Deprecated, use sort. Field to order results by, when sort is omitted."
+// @Param ascending query bool false "Deprecated, use sort. Whether orderBy orders results in ascending order. Default is true."
+// @Param category query int false "Category ID to filter products by"
+// @Param includeDescendants query bool false "Whether the category filter also matches its descendant categories. Default is false."
+// @Param minPrice query string false "Lowest price of the products, inclusive, as a decimal amount in the store currency"
+// @Param maxPrice query string false "Highest price of the products, inclusive, as a decimal amount in the store currency"
+// @Param createdAfter query string false "Products created from this moment on, as an RFC 3339 timestamp or a YYYY-MM-DD date"
+// @Param createdBefore query string false "Products created before this moment, as an RFC 3339 timestamp or a YYYY-MM-DD date"
+// @Param attr[name] query string false "Attribute a variant of the products must have, such as attr[color]=red. Can be repeated for different attributes, which the same variant must have."
+// @Param currency query string false "Currency of the prices, the default currency if omitted"
+// @Param Accept-Currency header string false "Currency of the prices when the currency parameter is omitted"
+// @Param status query string false "Statuses of the products, separated by commas: 'draft', 'active' or 'archived'. Default is every status."
+// @Success 200 {object} dto.ProductsListResp "Paginated and filtered list of products, with the facets of all the products that pass the filters and, when paged by cursor, the cursors of the adjacent pages"
+// @Failure 400 {object} responses.ProblemDTO "Invalid page, pageSize, cursor, sort, search, status or filter parameters, or unavailable currency"
+// @Failure 404 {object} responses.ProblemDTO "Category does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve products"
+// @Router /admin/products [get]
+func (ctrl *ProductController) FindAdminProducts(c *gin.Context) {
+	statuses, err := queryStatuses(c)
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	ctrl.findProducts(c, statuses)
+}
+
+// findProducts lists the products of the query with any of the statuses, or
+// in any status if none is given.
+func (ctrl *ProductController) findProducts(c *gin.Context, statuses []model.ProductStatus) {
 	page, _ := strconv.Atoi(c.Query("page"))
 	pageSize, _ := strconv.Atoi(c.Query("pageSize"))
-	searchTerm := c.Query("searchTerm")
-	orderBy := c.DefaultQuery("orderBy", "price")
-	ascending, _ := strconv.ParseBool(c.DefaultQuery("ascending", "true"))
 
-	products, total, err := ctrl.productService.ProductsList(page, pageSize, searchTerm, orderBy, ascending)
+	filter, err := queryFilter(c)
 	if err != nil {
 		responses.SendError(c, utils.GetCustomError(err))
 		return
 	}
 
-	resp := dto.ProductsListResp{
-		Total:    total,
-		Products: dto.ToProductsDTO(products),
+	if value := strings.TrimSpace(c.Query("cursor")); value != "" {
+		if filter.Cursor, err = model.DecodeProductCursor(value); err != nil {
+			responses.SendError(c, utils.NewError(utils.ErrInvalidCursor, err))
+			return
+		}
 	}
 
+	filter.Page = page
+	filter.PageSize = pageSize
+	filter.Currency = requestCurrency(c)
+	filter.Statuses = statuses
+
+	list, err := ctrl.productService.ProductsList(filter)
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	resp := dto.ToProductsListResp(list)
 	responses.SendSuccess(c, http.StatusOK, resp)
 }
 
+// ExportProducts
+// @Summary Export the product catalog
+// @Description Streams every product that passes the same filters as the product list, at its catalog price, as CSV, NDJSON or XLSX. The default columns are the ones the import reads, so CSV and NDJSON exports can be imported back; the id, available, createdAt and updatedAt columns are skipped by imports.
+// @Tags Products
+// @ID export-products
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Format of the export: 'csv', 'ndjson' or 'xlsx'. Default is csv."
+// @Param columns query string false "Columns to export, separated by commas: id, code, name, description, price, currency, imageURL, taxCategory, stock, available, categoryIDs, status, publishAt, unpublishAt, createdAt, updatedAt. Default is every column the import reads."
+// @Param searchTerm query string false "Search term to find products by code, name or description"
+// @Param sort query string false "Fields to sort products by, such as -price,name. Default is relevance when searching and id otherwise."
+// @Param category query int false "Category ID to filter products by"
+// @Param includeDescendants query bool false "Whether the category filter also matches its descendant categories. Default is false."
+// @Param minPrice query string false "Lowest price of the products, inclusive, as a decimal amount in the store currency"
+// @Param maxPrice query string false "Highest price of the products, inclusive, as a decimal amount in the store currency"
+// @Param createdAfter query string false "Products created from this moment on, as an RFC 3339 timestamp or a YYYY-MM-DD date"
+// @Param createdBefore query string false "Products created before this moment, as an RFC 3339 timestamp or a YYYY-MM-DD date"
+// @Param attr[name] query string false "Attribute a variant of the products must have, such as attr[color]=red"
+// @Param status query string false "Statuses of the products, separated by commas: 'draft', 'active' or 'archived'. Default is every status."
+// @Success 200 {file} file "Product catalog"
+// @Failure 400 {object} responses.ProblemDTO "Invalid format, columns, sort, status or filter parameters"
+// @Failure 404 {object} responses.ProblemDTO "Category does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to export products"
+// @Router /products/export [get]
+func (ctrl *ProductController) ExportProducts(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", "csv"))
+
+	exportFormat, ok := dto.ExportFormats[format]
+	if !ok {
+		responses.SendError(c, utils.NewError(utils.ErrUnsupportedExportFormat, fmt.Errorf("unsupported export format %q", format), format))
+		return
+	}
+
+	columns, err := dto.ParseExportColumns(c.Query("columns"))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	filter, err := queryFilter(c)
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	if filter.Statuses, err = queryStatuses(c); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	// The response starts with the first batch, so errors found before it,
+	// such as an unknown category, are still sent as errors.
+	var writer dto.ProductWriter
+	begin := func() (err error) {
+		c.Header("Content-Type", exportFormat.ContentType)
+		c.Header("Content-Disposition", "attachment; filename=products."+exportFormat.Extension)
+		c.Status(http.StatusOK)
+
+		writer, err = dto.NewProductWriter(format, c.Writer, columns)
+		return err
+	}
+
+	err = ctrl.productService.ExportProducts(filter, func(products []*model.Product) error {
+		if writer == nil {
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+		return writer.Write(products)
+	})
+
+	if err == nil && writer == nil {
+		err = begin()
+	}
+
+	if err == nil {
+		err = writer.Close()
+	}
+
+	if err != nil {
+		if !c.Writer.Written() {
+			responses.SendError(c, utils.GetCustomError(err))
+			return
+		}
+
+		// The response has started, so the error can only cut it short.
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+// queryFilter parses the search, sort and filters of the product list from
+// the query.
+func queryFilter(c *gin.Context) (*model.ProductFilter, error) {
+	ascending, _ := strconv.ParseBool(c.DefaultQuery("ascending", "true"))
+	categoryID, _ := strconv.Atoi(c.Query("category"))
+	includeDescendants, _ := strconv.ParseBool(c.DefaultQuery("includeDescendants", "false"))
+
+	minPrice, err := queryPrice(c, "minPrice")
+	if err != nil {
+		return nil, utils.NewError(utils.ErrInvalidMinPrice, err)
+	}
+
+	maxPrice, err := queryPrice(c, "maxPrice")
+	if err != nil {
+		return nil, utils.NewError(utils.ErrInvalidMaxPrice, err)
+	}
+
+	createdAfter, err := queryTime(c, "createdAfter")
+	if err != nil {
+		return nil, utils.NewError(utils.ErrInvalidCreatedAfter, err)
+	}
+
+	createdBefore, err := queryTime(c, "createdBefore")
+	if err != nil {
+		return nil, utils.NewError(utils.ErrInvalidCreatedBefore, err)
+	}
+
+	sort, err := querySort(c, ascending)
+	if err != nil {
+		return nil, utils.NewError(utils.ErrInvalidSort, err)
+	}
+
+	return &model.ProductFilter{
+		SearchTerm:         c.Query("searchTerm"),
+		Sort:               sort,
+		CategoryID:         uint(categoryID),
+		IncludeDescendants: includeDescendants,
+		MinPrice:           minPrice,
+		MaxPrice:           maxPrice,
+		CreatedAfter:       createdAfter,
+		CreatedBefore:      createdBefore,
+		Attributes:         c.QueryMap("attr"),
+	}, nil
+}
+
+// queryStatuses parses the product statuses of the query, separated by
+// commas, nil if the parameter is missing.
+func queryStatuses(c *gin.Context) ([]model.ProductStatus, error) {
+	value := strings.TrimSpace(c.Query("status"))
+	if value == "" {
+		return nil, nil
+	}
+
+	statuses := make([]model.ProductStatus, 0)
+	for _, v := range strings.Split(value, ",") {
+		status := model.ProductStatus(strings.TrimSpace(strings.ToLower(v)))
+		if !status.IsValid() {
+			return nil, utils.NewError(utils.ErrInvalidProductStatus, fmt.Errorf("invalid product status %q", status), status, "draft, active, archived")
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// querySort parses the sort specification of the query, or the field of the
+// orderBy parameter that preceded it in the given direction.
+func querySort(c *gin.Context, ascending bool) ([]model.SortField, error) {
+	if spec := c.Query("sort"); spec != "" {
+		return model.ParseSort(spec)
+	}
+
+	if field := strings.TrimSpace(c.Query("orderBy")); field != "" {
+		return []model.SortField{{Field: field, Descending: !ascending}}, nil
+	}
+
+	return nil, nil
+}
+
+// queryPrice parses a decimal amount of the default currency from the query,
+// nil if the parameter is missing.
+func queryPrice(c *gin.Context, name string) (*model.Money, error) {
+	value := strings.TrimSpace(c.Query(name))
+	if value == "" {
+		return nil, nil
+	}
+
+	price, err := model.ParseMoney(value, model.DefaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &price, nil
+}
+
+// queryTime parses an RFC 3339 timestamp or a date from the query, nil if the
+// parameter is missing. Dates are taken as midnight UTC.
+func queryTime(c *gin.Context, name string) (*time.Time, error) {
+	value := strings.TrimSpace(c.Query(name))
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if t, err = time.Parse("2006-01-02", value); err != nil {
+			return nil, err
+		}
+	}
+
+	return &t, nil
+}
+
 // FindProduct
 // @Summary Get a product by ID
-// @Description Retrieves a product by its ID
+// @Description Retrieves an active product by its ID. The ETag header identifies this representation of the product, prices included, to check with If-Match on changes or If-None-Match on later requests.
 // @Tags Products
 // @ID find-product
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param currency query string false "Currency of the prices, the default currency if omitted"
+// @Param Accept-Currency header string false "Currency of the prices when the currency parameter is omitted"
+// @Param If-None-Match header string false "ETag of the product the client already has"
 // @Success 200 {object} dto.ProductDTO "Product found"
-// @Failure 400 {object} responses.ErrorDTO "Invalid product ID"
-// @Failure 404 {object} responses.ErrorDTO "Product does not exist"
-// @Failure 500 {object} responses.ErrorDTO "Failed to retrieve product"
+// @Success 304 "The product has not changed since the ETag of If-None-Match"
+// @Failure 400 {object} responses.ProblemDTO "Invalid product ID or unavailable currency"
+// @Failure 404 {object} responses.ProblemDTO "Product does not exist or is not active"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve product"
 // @Router /product/{id} [get]
 func (ctrl *ProductController) FindProduct(c *gin.Context) {
+	ctrl.findProduct(c, []model.ProductStatus{model.ProductActive})
+}
+
+// FindAdminProduct
+// @Summary Get a product in any status by ID
+// @Description Retrieves a product by its ID like the product endpoint, including drafts and archived products
+// @Tags Products
+// @ID find-admin-product
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param currency query string false "Currency of the prices, the default currency if omitted"
+// @Param Accept-Currency header string false "Currency of the prices when the currency parameter is omitted"
+// @Param If-None-Match header string false "ETag of the product the client already has"
+// @Success 200 {object} dto.ProductDTO "Product found"
+// @Success 304 "The product has not changed since the ETag of If-None-Match"
+// @Failure 400 {object} responses.ProblemDTO "Invalid product ID or unavailable currency"
+// @Failure 404 {object} responses.ProblemDTO "Product does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve product"
+// @Router /admin/product/{id} [get]
+func (ctrl *ProductController) FindAdminProduct(c *gin.Context) {
+	ctrl.findProduct(c, nil)
+}
+
+// findProduct sends the product of the path ID if it has any of the statuses,
+// or in any status if none is given.
+func (ctrl *ProductController) findProduct(c *gin.Context, statuses []model.ProductStatus) {
 	productID, _ := strconv.Atoi(c.Param("id"))
 
-	product, err := ctrl.productService.ProductByID(uint(productID))
+	product, err := ctrl.productService.ProductByID(uint(productID), requestCurrency(c), statuses)
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	sendProduct(c, product)
+}
+
+// FindProductByCode
+// @Summary Get a product by code
+// @Description Retrieves the active product with a code among the ones that are not deleted, with its entity tag in the ETag header
+// @Tags Products
+// @ID find-product-by-code
+// @Accept json
+// @Produce json
+// @Param code path string true "Product code"
+// @Param currency query string false "Currency of the prices, the default currency if omitted"
+// @Param Accept-Currency header string false "Currency of the prices when the currency parameter is omitted"
+// @Param If-None-Match header string false "ETag of the product the client already has"
+// @Success 200 {object} dto.ProductDTO "Product found"
+// @Success 304 "The product has not changed since the ETag of If-None-Match"
+// @Failure 400 {object} responses.ProblemDTO "Unavailable currency"
+// @Failure 404 {object} responses.ProblemDTO "No active product has the code"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve product"
+// @Router /products/by-code/{code} [get]
+func (ctrl *ProductController) FindProductByCode(c *gin.Context) {
+	ctrl.findProductByCode(c, []model.ProductStatus{model.ProductActive})
+}
+
+// FindAdminProductByCode
+// @Summary Get a product in any status by code
+// @Description Retrieves the product with a code like the product by code endpoint, including drafts and archived products
+// @Tags Products
+// @ID find-admin-product-by-code
+// @Accept json
+// @Produce json
+// @Param code path string true "Product code"
+// @Param currency query string false "Currency of the prices, the default currency if omitted"
+// @Param Accept-Currency header string false "Currency of the prices when the currency parameter is omitted"
+// @Param If-None-Match header string false "ETag of the product the client already has"
+// @Success 200 {object} dto.ProductDTO "Product found"
+// @Success 304 "The product has not changed since the ETag of If-None-Match"
+// @Failure 400 {object} responses.ProblemDTO "Unavailable currency"
+// @Failure 404 {object} responses.ProblemDTO "No product has the code"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve product"
+// @Router /admin/products/by-code/{code} [get]
+func (ctrl *ProductController) FindAdminProductByCode(c *gin.Context) {
+	ctrl.findProductByCode(c, nil)
+}
+
+// findProductByCode sends the product of the path code if it has any of the
+// statuses, or in any status if none is given.
+func (ctrl *ProductController) findProductByCode(c *gin.Context, statuses []model.ProductStatus) {
+	product, err := ctrl.productService.ProductByCode(strings.TrimSpace(c.Param("code")), requestCurrency(c), statuses)
 	if err != nil {
 		responses.SendError(c, utils.GetCustomError(err))
 		return
 	}
 
+	sendProduct(c, product)
+}
+
+// sendProduct sends a product with the entity tag of its body, or only the
+// tag if the client already has that body.
+func sendProduct(c *gin.Context, product *model.Product) {
 	productDTO := dto.ToProductDTO(product)
+	tag := etag(productDTO)
+	if notModified(c, tag) {
+		return
+	}
+
+	setETag(c, tag)
 	responses.SendSuccess(c, http.StatusOK, productDTO)
 }
 
+// currentProduct returns the current representation of a product for a
+// request, in any status, with its version, to evaluate the preconditions of
+// a change.
+func (ctrl *ProductController) currentProduct(c *gin.Context, productID uint) func() (interface{}, uint, error) {
+	return func() (interface{}, uint, error) {
+		product, err := ctrl.productService.ProductByID(productID, requestCurrency(c), nil)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return dto.ToProductDTO(product), product.Version, nil
+	}
+}
+
 // NewProduct
 // @Summary Create a new product
-// @Description Creates a new product with the provided data
+// @Description Creates a new product with the provided data. The price is an object with a decimal string amount and a currency, such as {"amount": "12.30", "currency": "MXN"}, in the store currency
 // @Tags Products
 // @ID new-product
 // @Accept json
 // @Produce json
 // @Param data body dto.ProductData true "Product data"
 // @Success 201 {object} dto.ProductDTO "Created product"
-// @Failure 400 {object} responses.ErrorDTO "Invalid product data"
-// @Failure 500 {object} responses.ErrorDTO "Failed to create product"
+// @Failure 400 {object} responses.ProblemDTO "Invalid product data or unknown category"
+// @Failure 409 {object} responses.ProblemDTO "Another product has the code, with code PRODUCT_CODE_TAKEN"
+// @Failure 422 {object} responses.ProblemDTO "The product refers to a record that does not exist, with code MISSING_REFERENCE or PRODUCT_CATEGORIES_NOT_FOUND"
+// @Failure 500 {object} responses.ProblemDTO "Failed to create product"
 // @Router /products [post]
 func (ctrl *ProductController) NewProduct(c *gin.Context) {
 	var productData dto.ProductData
 
-	if err := c.BindJSON(&productData); err != nil {
-		responses.SendError(c, utils.ToUserError(http.StatusBadRequest, "Datos de producto incorrectos", err))
+	if err := c.ShouldBindJSON(&productData); err != nil {
+		responses.SendError(c, dto.BindingError(&productData, err))
 		return
 	}
 
@@ -112,37 +504,178 @@ func (ctrl *ProductController) NewProduct(c *gin.Context) {
 	responses.SendSuccess(c, http.StatusCreated, productDTO)
 }
 
+// ImportProducts
+// @Summary Import products from a CSV or NDJSON file
+// @Description Creates or updates, by code, the products of a CSV or NDJSON file, normalized and validated as when creating a product, and reports what happened with every row. CSV files have a header with the fields of the product data, plus an optional currency column for the price; category IDs are separated by | and empty cells are left unchanged. NDJSON files have a product data object per line. Updates only change the fields a row has. The file is read as it is uploaded, either as the body or as the file part of a multipart form.
+// @Tags Products
+// @ID import-products
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Accept mpfd
+// @Produce json
+// @Param file formData file false "CSV or NDJSON file, for multipart uploads"
+// @Param format query string false "Format of the file, 'csv' or 'ndjson'. Default is taken from the content type or the file name."
+// @Param dryRun query bool false "Whether to validate the rows without saving them. Default is false."
+// @Success 200 {object} dto.ImportReportDTO "Created, updated and rejected rows"
+// @Failure 400 {object} responses.ProblemDTO "Unsupported format, invalid header or unreadable file"
+// @Failure 500 {object} responses.ProblemDTO "Failed to import products; the rows before the failure are kept"
+// @Router /products/import [post]
+func (ctrl *ProductController) ImportProducts(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+
+	body, format, err := importFile(c)
+	if err != nil {
+		responses.SendError(c, utils.NewError(utils.ErrImportFileMissing, err))
+		return
+	}
+
+	var source service.ProductSource
+	switch format {
+	case "csv":
+		if source, err = dto.NewCSVProductSource(body); err != nil {
+			responses.SendError(c, utils.GetCustomError(err))
+			return
+		}
+	case "ndjson":
+		source = dto.NewNDJSONProductSource(body)
+	default:
+		responses.SendError(c, utils.NewError(utils.ErrUnsupportedImportFormat, fmt.Errorf("unsupported import format %q", format)))
+		return
+	}
+
+	report, err := ctrl.productService.ImportProducts(source, dryRun)
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	resp := dto.ToImportReportDTO(report, responses.Language(c))
+	responses.SendSuccess(c, http.StatusOK, resp)
+}
+
+// importFile returns the uploaded file, streamed from the body or from the
+// file part of a multipart form, and its format: the one of the query, or
+// the one its content type or name tells.
+func importFile(c *gin.Context) (io.Reader, string, error) {
+	var body io.Reader = c.Request.Body
+	contentType := c.ContentType()
+	name := ""
+
+	if contentType == "multipart/form-data" {
+		reader, err := c.Request.MultipartReader()
+		if err != nil {
+			return nil, "", err
+		}
+
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				return nil, "", fmt.Errorf("file part not found: %w", err)
+			}
+
+			if part.FormName() == "file" {
+				body, contentType, name = part, part.Header.Get("Content-Type"), part.FileName()
+				break
+			}
+		}
+	}
+
+	if format := strings.ToLower(c.Query("format")); format != "" {
+		return body, format, nil
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, "text/csv") || strings.HasSuffix(strings.ToLower(name), ".csv"):
+		return body, "csv", nil
+	case strings.Contains(contentType, "ndjson") || strings.Contains(contentType, "jsonl"),
+		strings.HasSuffix(strings.ToLower(name), ".ndjson"), strings.HasSuffix(strings.ToLower(name), ".jsonl"):
+		return body, "ndjson", nil
+	}
+
+	return body, "", nil
+}
+
 // UpdateProduct
 // @Summary Update a product
-// @Description Updates a product with the provided updates
+// @Description Updates a product with a JSON Merge Patch (RFC 7396), where null members clear their fields, or a JSON Patch (RFC 6902) of its document, the fields of dto.ProductData. Plain JSON is taken as a merge patch. A price may be given as a decimal string or as an object with an amount and a currency. The ETag header holds the entity tag of the updated product.
 // @Tags Products
 // @ID update-product
-// @Accept json
+// @Accept json,application/merge-patch+json,application/json-patch+json
 // @Produce json
 // @Param id path int true "Product ID"
-// @Param updates body dto.ProductData true "Product updates"
+// @Param If-Match header string false "ETag of the product the updates were made on, or * for any"
+// @Param currency query string false "Currency of the prices the ETag of If-Match was taken with, the default currency if omitted"
+// @Param Accept-Currency header string false "Currency of the prices the ETag of If-Match was taken with when the currency parameter is omitted"
+// @Param updates body dto.ProductData true "Merge patch of the product, or an array of JSON Patch operations with op, path, from and value members"
 // @Success 200 {object} responses.SuccessDTO "Product updated successfully"
-// @Failure 400 {object} responses.ErrorDTO "Invalid product ID or updates"
-// @Failure 500 {object} responses.ErrorDTO "Failed to update product"
+// @Failure 400 {object} responses.ProblemDTO "Invalid product ID, patch or field value"
+// @Failure 404 {object} responses.ProblemDTO "Product does not exist"
+// @Failure 409 {object} responses.ProblemDTO "Another product has the code, with code PRODUCT_CODE_TAKEN, another request changed the product at the same time, with code CONCURRENT_MODIFICATION, or a test operation failed, with code PATCH_TEST_FAILED"
+// @Failure 412 {object} responses.ProblemDTO "The product no longer matches If-Match, with code VERSION_CONFLICT"
+// @Failure 413 {object} responses.ProblemDTO "The patch is larger than 1 MB"
+// @Failure 415 {object} responses.ProblemDTO "Unsupported content type"
+// @Failure 422 {object} responses.ProblemDTO "The JSON Patch refers to values the product does not have, or the product refers to a record that does not exist, with code MISSING_REFERENCE or PRODUCT_CATEGORIES_NOT_FOUND"
+// @Failure 500 {object} responses.ProblemDTO "Failed to update product"
 // @Router /product/{id} [patch]
 func (ctrl *ProductController) UpdateProduct(c *gin.Context) {
 	productID, _ := strconv.Atoi(c.Param("id"))
 
-	var updates map[string]interface{}
-	if err := c.BindJSON(&updates); err != nil {
-		responses.SendError(c, utils.ToUserError(http.StatusBadRequest, "Datos de actualizacion de producto incorrectos", err))
+	version, err := ifMatch(c, ctrl.currentProduct(c, uint(productID)))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPatchSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			responses.SendError(c, utils.NewError(utils.ErrPatchTooLarge, err, maxPatchSize/1024))
+			return
+		}
+		responses.SendError(c, utils.NewError(utils.ErrMalformedPatch, err))
+		return
+	}
+
+	changes, err := productPatch(c.ContentType(), body)
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	if _, err = ctrl.productService.UpdateProduct(uint(productID), changes, version); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
 		return
 	}
 
-	if err := ctrl.productService.UpdateProduct(uint(productID), updates); err != nil {
+	current, _, err := ctrl.currentProduct(c, uint(productID))()
+	if err != nil {
 		responses.SendError(c, utils.GetCustomError(err))
 		return
 	}
 
-	resp := responses.SuccessDTO{Message: "Producto actulizado correctamente"}
+	setETag(c, etag(current))
+	resp := responses.NewSuccess(responses.ProductUpdated)
 	responses.SendSuccess(c, http.StatusOK, resp)
 }
 
+// maxPatchSize is the largest patch of a product update, in bytes.
+const maxPatchSize = 1 << 20
+
+// productPatch reads the patch of a product update by its media type. Plain
+// JSON is taken as a merge patch, which partial updates already were.
+func productPatch(contentType string, body []byte) (*dto.ProductPatch, error) {
+	switch contentType {
+	case dto.JSONPatchType:
+		return dto.NewJSONPatch(body)
+	case dto.MergePatchType, "application/json", "":
+		return dto.NewMergePatch(body)
+	}
+
+	return nil, utils.NewError(utils.ErrUnsupportedPatchType, fmt.Errorf("unsupported content type %q", contentType),
+		contentType, dto.MergePatchType, dto.JSONPatchType)
+}
+
 // RemoveProduct
 // @Summary Delete a product
 // @Description Deletes a product by its ID
@@ -151,18 +684,110 @@ func (ctrl *ProductController) UpdateProduct(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param If-Match header string false "ETag of the product to delete, or * for any"
+// @Param currency query string false "Currency of the prices the ETag of If-Match was taken with, the default currency if omitted"
+// @Param Accept-Currency header string false "Currency of the prices the ETag of If-Match was taken with when the currency parameter is omitted"
 // @Success 200 {object} responses.SuccessDTO "Product deleted successfully"
-// @Failure 400 {object} responses.ErrorDTO "Invalid product ID"
-// @Failure 500 {object} responses.ErrorDTO "Failed to delete product"
+// @Failure 400 {object} responses.ProblemDTO "Invalid product ID or unavailable currency"
+// @Failure 404 {object} responses.ProblemDTO "Product does not exist, when If-Match is given"
+// @Failure 412 {object} responses.ProblemDTO "The product no longer matches If-Match, with code VERSION_CONFLICT"
+// @Failure 500 {object} responses.ProblemDTO "Failed to delete product"
 // @Router /product/{id} [delete]
 func (ctrl *ProductController) RemoveProduct(c *gin.Context) {
 	productID, _ := strconv.Atoi(c.Param("id"))
 
-	if err := ctrl.productService.DeleteProduct(uint(productID)); err != nil {
+	version, err := ifMatch(c, ctrl.currentProduct(c, uint(productID)))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	if err = ctrl.productService.DeleteProduct(uint(productID), version); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	resp := responses.NewSuccess(responses.ProductDeleted)
+	responses.SendSuccess(c, http.StatusOK, resp)
+}
+
+// FindDeletedProducts
+// @Summary Get a paginated list of deleted products
+// @Description Retrieves a paginated list of the deleted products, the most recently deleted first, so they can be restored or purged
+// @Tags Products
+// @ID find-deleted-products
+// @Accept json
+// @Produce json
+// @Param page query int true "Page number" minimum(1)
+// @Param pageSize query int true "Page size" minimum(1)
+// @Success 200 {object} dto.DeletedProductsListResp "Paginated list of deleted products"
+// @Failure 400 {object} responses.ProblemDTO "Invalid page or pageSize"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve deleted products"
+// @Router /admin/products/deleted [get]
+func (ctrl *ProductController) FindDeletedProducts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("pageSize"))
+
+	products, total, err := ctrl.productService.DeletedProductsList(page, pageSize)
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	resp := dto.ToDeletedProductsListResp(products, total)
+	responses.SendSuccess(c, http.StatusOK, resp)
+}
+
+// RestoreProduct
+// @Summary Restore a deleted product
+// @Description Undoes the deletion of a product. The items that open carts lost when it was deleted are not restored.
+// @Tags Products
+// @ID restore-product
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} responses.SuccessDTO "Product restored successfully"
+// @Failure 404 {object} responses.ProblemDTO "Product does not exist"
+// @Failure 409 {object} responses.ProblemDTO "Product is not deleted, or another product has its code, with code PRODUCT_CODE_TAKEN"
+// @Failure 500 {object} responses.ProblemDTO "Failed to restore product"
+// @Router /admin/product/{id}/restore [post]
+func (ctrl *ProductController) RestoreProduct(c *gin.Context) {
+	productID, _ := strconv.Atoi(c.Param("id"))
+
+	if err := ctrl.productService.RestoreProduct(uint(productID)); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	resp := responses.NewSuccess(responses.ProductRestored)
+	responses.SendSuccess(c, http.StatusOK, resp)
+}
+
+// PurgeProducts
+// @Summary Permanently delete old deleted products
+// @Description Permanently deletes the products deleted more than a number of days ago, with their variants, prices, category and promotion assignments and the cart items that held them. Orders keep their copies of the products.
+// @Tags Products
+// @ID purge-products
+// @Accept json
+// @Produce json
+// @Param olderThanDays query int true "Minimum days since the products were deleted" minimum(0)
+// @Success 200 {object} dto.PurgeResp "Number of products purged"
+// @Failure 400 {object} responses.ProblemDTO "Invalid olderThanDays"
+// @Failure 500 {object} responses.ProblemDTO "Failed to purge products; the products purged before the failure stay purged"
+// @Router /admin/products/deleted [delete]
+func (ctrl *ProductController) PurgeProducts(c *gin.Context) {
+	days, err := strconv.Atoi(c.Query("olderThanDays"))
+	if err != nil {
+		responses.SendError(c, utils.NewError(utils.ErrInvalidPurgeAge, err))
+		return
+	}
+
+	purged, err := ctrl.productService.PurgeDeletedProducts(days)
+	if err != nil {
 		responses.SendError(c, utils.GetCustomError(err))
 		return
 	}
 
-	resp := responses.SuccessDTO{Message: "Producto eliminado correctamente"}
+	resp := &dto.PurgeResp{Purged: purged}
 	responses.SendSuccess(c, http.StatusOK, resp)
 }
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"codifin-challenge/domain/service"
+	"codifin-challenge/domain/utils"
+	"codifin-challenge/infrastructure/web/dto"
+	"codifin-challenge/infrastructure/web/responses"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type PromotionController struct {
+	promotionService service.PromotionService
+}
+
+func NewPromotionController(promotionService service.PromotionService) *PromotionController {
+	return &PromotionController{promotionService: promotionService}
+}
+
+// FindPromotions
+// @Summary Get the list of promotions
+// @Description Retrieves every promotion with its rule, limits, usage count and scope
+// @Tags Promotions
+// @ID find-promotions
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.PromotionDTO "List of promotions"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve promotions"
+// @Router /promotions [get]
+func (ctrl *PromotionController) FindPromotions(c *gin.Context) {
+	promotions, err := ctrl.promotionService.PromotionsList()
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	promotionsDTO := dto.ToPromotionsDTO(promotions)
+	responses.SendSuccess(c, http.StatusOK, promotionsDTO)
+}
+
+// FindPromotion
+// @Summary Get a promotion by ID
+// @Description Retrieves a promotion with its rule, limits, usage count and scope by its ID
+// @Tags Promotions
+// @ID find-promotion
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} dto.PromotionDTO "Promotion found"
+// @Failure 404 {object} responses.ProblemDTO "Promotion does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve promotion"
+// @Router /promotion/{id} [get]
+func (ctrl *PromotionController) FindPromotion(c *gin.Context) {
+	promotionID, _ := strconv.Atoi(c.Param("id"))
+
+	promotion, err := ctrl.promotionService.PromotionByID(uint(promotionID))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	promotionDTO := dto.ToPromotionDTO(promotion)
+	responses.SendSuccess(c, http.StatusOK, promotionDTO)
+}
+
+// NewPromotion
+// @Summary Create a new promotion
+// @Description Creates a coupon code with a percentage off, fixed amount off, buy X get Y or free item over threshold rule, optionally limited to a validity window, a number of uses and some products or categories
+// @Tags Promotions
+// @ID new-promotion
+// @Accept json
+// @Produce json
+// @Param data body dto.PromotionData true "Promotion data"
+// @Success 201 {object} dto.PromotionDTO "Created promotion"
+// @Failure 400 {object} responses.ProblemDTO "Invalid promotion data"
+// @Failure 409 {object} responses.ProblemDTO "Code already registered"
+// @Failure 500 {object} responses.ProblemDTO "Failed to create promotion"
+// @Router /promotions [post]
+func (ctrl *PromotionController) NewPromotion(c *gin.Context) {
+	var promotionData dto.PromotionData
+
+	if err := c.ShouldBindJSON(&promotionData); err != nil {
+		responses.SendError(c, dto.BindingError(&promotionData, err))
+		return
+	}
+
+	newPromotion := promotionData.ToPromotion()
+
+	if err := ctrl.promotionService.CreatePromotion(newPromotion); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	promotionDTO := dto.ToPromotionDTO(newPromotion)
+	responses.SendSuccess(c, http.StatusCreated, promotionDTO)
+}
+
+// UpdatePromotion
+// @Summary Update a promotion
+// @Description Replaces the code, rule, limits and scope of a promotion, keeping its usage count
+// @Tags Promotions
+// @ID update-promotion
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Param data body dto.PromotionData true "Promotion data"
+// @Success 200 {object} dto.PromotionDTO "Updated promotion"
+// @Failure 400 {object} responses.ProblemDTO "Invalid promotion data"
+// @Failure 404 {object} responses.ProblemDTO "Promotion does not exist"
+// @Failure 409 {object} responses.ProblemDTO "Code already registered"
+// @Failure 500 {object} responses.ProblemDTO "Failed to update promotion"
+// @Router /promotion/{id} [put]
+func (ctrl *PromotionController) UpdatePromotion(c *gin.Context) {
+	promotionID, _ := strconv.Atoi(c.Param("id"))
+
+	var promotionData dto.PromotionData
+	if err := c.ShouldBindJSON(&promotionData); err != nil {
+		responses.SendError(c, dto.BindingError(&promotionData, err))
+		return
+	}
+
+	promotion := promotionData.ToPromotion()
+	promotion.ID = uint(promotionID)
+
+	if err := ctrl.promotionService.UpdatePromotion(promotion); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	promotionDTO := dto.ToPromotionDTO(promotion)
+	responses.SendSuccess(c, http.StatusOK, promotionDTO)
+}
+
+// RemovePromotion
+// @Summary Delete a promotion
+// @Description Deletes a promotion and removes its coupon from the carts it was applied to
+// @Tags Promotions
+// @ID remove-promotion
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} responses.SuccessDTO "Promotion deleted successfully"
+// @Failure 404 {object} responses.ProblemDTO "Promotion does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to delete promotion"
+// @Router /promotion/{id} [delete]
+func (ctrl *PromotionController) RemovePromotion(c *gin.Context) {
+	promotionID, _ := strconv.Atoi(c.Param("id"))
+
+	if err := ctrl.promotionService.DeletePromotion(uint(promotionID)); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	resp := responses.NewSuccess(responses.PromotionDeleted)
+	responses.SendSuccess(c, http.StatusOK, resp)
+}
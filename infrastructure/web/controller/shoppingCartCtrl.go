@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 type ShoppingCartController struct {
@@ -21,26 +22,32 @@ func NewShoppingCartController(service service.ShoppingCartService) *ShoppingCar
 
 // NewCart
 // @Summary Create a new shopping cart
-// @Description Creates a new shopping cart with the specified items
+// @Description Creates a new shopping cart with the specified items, taxed with the rules of a region
 // @Tags Shopping Carts
 // @ID new-cart
 // @Accept json
 // @Produce json
+// @Param region query string false "Tax region of the cart, the configured default region if omitted"
 // @Param items body []dto.ItemData true "Items to add to the shopping cart"
-// @Success 201 {object} dto.ShoppingCartDTO "Created shopping cart"
-// @Failure 400 {object} responses.ErrorDTO "Invalid item data"
-// @Failure 500 {object} responses.ErrorDTO "Failed to create shopping cart"
+// @Param currency query string false "Currency of the prices, the default currency if omitted"
+// @Param Accept-Currency header string false "Currency of the prices when the currency parameter is omitted"
+// @Success 201 {object} dto.ShoppingCartDTO "Created shopping cart, with its entity tag in the ETag header"
+// @Failure 400 {object} responses.ProblemDTO "Invalid item data or region without tax rules"
+// @Failure 404 {object} responses.ProblemDTO "Product does not exist"
+// @Failure 409 {object} responses.ProblemDTO "Not enough stock, or a product is not published yet or archived, with code PRODUCT_NOT_PUBLISHED or PRODUCT_ARCHIVED"
+// @Failure 500 {object} responses.ProblemDTO "Failed to create shopping cart"
 // @Router /carts [post]
 func (ctrl *ShoppingCartController) NewCart(c *gin.Context) {
 	var items []*dto.ItemData
-	if err := c.BindJSON(&items); err != nil {
-		responses.SendError(c, utils.ToUserError(http.StatusBadRequest, "Datos de carrito incorrectos", err))
+	if err := c.ShouldBindJSON(&items); err != nil {
+		responses.SendError(c, dto.BindingError(&items, err))
 		return
 	}
 
 	itemsCart := dto.ToItemsCart(0, items)
 	newCart := &model.ShoppingCart{
-		Items: itemsCart,
+		Items:  itemsCart,
+		Region: strings.TrimSpace(strings.ToUpper(c.Query("region"))),
 	}
 
 	err := ctrl.shoppingCartService.CreateShoppingCart(newCart)
@@ -49,114 +56,262 @@ func (ctrl *ShoppingCartController) NewCart(c *gin.Context) {
 		return
 	}
 
-	created, err := ctrl.shoppingCartService.FindCart(newCart.ID)
+	created, err := ctrl.shoppingCartService.CartSummary(newCart.ID, requestCurrency(c))
 	if err != nil {
 		responses.SendError(c, utils.GetCustomError(err))
 		return
 	}
 
-	cartDTO := dto.ToShoppingCartDTO(created)
-	responses.SendSuccess(c, http.StatusCreated, cartDTO)
+	sendCart(c, http.StatusCreated, created)
 }
 
 // FindShoppingCart
 // @Summary Get a shopping cart by ID
-// @Description Retrieves a shopping cart by its ID
+// @Description Retrieves a shopping cart by its ID with its line totals, subtotal, item count, discounts, taxes and total. Prices are tax inclusive or exclusive as configured. The ETag header identifies this representation of the cart, so it changes with its items and coupons and with the prices, promotions, taxes and exchange rates behind its totals.
 // @Tags Shopping Carts
 // @ID find-shopping-cart
 // @Accept json
 // @Produce json
 // @Param id path int true "Shopping cart ID"
+// @Param currency query string false "Currency of the prices, the default currency if omitted"
+// @Param Accept-Currency header string false "Currency of the prices when the currency parameter is omitted"
+// @Param If-None-Match header string false "ETag of the shopping cart the client already has"
 // @Success 200 {object} dto.ShoppingCartDTO "Found shopping cart"
-// @Failure 400 {object} responses.ErrorDTO "Invalid shopping cart ID"
-// @Failure 500 {object} responses.ErrorDTO "Failed to retrieve shopping cart"
+// @Success 304 "The shopping cart has not changed since the ETag of If-None-Match"
+// @Failure 400 {object} responses.ProblemDTO "Invalid shopping cart ID or unavailable currency"
+// @Failure 404 {object} responses.ProblemDTO "Shopping cart does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve shopping cart"
 // @Router /cart/{id} [get]
 func (ctrl *ShoppingCartController) FindShoppingCart(c *gin.Context) {
 	cartID, _ := strconv.Atoi(c.Param("id"))
 
-	cart, err := ctrl.shoppingCartService.FindCart(uint(cartID))
+	summary, err := ctrl.shoppingCartService.CartSummary(uint(cartID), requestCurrency(c))
 	if err != nil {
 		responses.SendError(c, utils.GetCustomError(err))
 		return
 	}
 
-	cartDTO := dto.ToShoppingCartDTO(cart)
+	cartDTO := dto.ToShoppingCartDTO(summary, responses.Language(c))
+	tag := etag(cartDTO)
+	if notModified(c, tag) {
+		return
+	}
+
+	setETag(c, tag)
 	responses.SendSuccess(c, http.StatusOK, cartDTO)
 }
 
 // AddItem
 // @Summary Add an item to a shopping cart
-// @Description Adds an item to the specified shopping cart
+// @Description Adds an item to the specified shopping cart and reserves its stock for a limited time. Products sold in variants require the variantID, and counts are merged per variant.
 // @Tags Shopping Carts
 // @ID add-item
 // @Accept json
 // @Produce json
 // @Param id path int true "Shopping cart ID"
+// @Param If-Match header string false "ETag of the shopping cart the change was made on, or * for any"
 // @Param item body dto.ItemData true "Item data"
-// @Success 200 {object} dto.ShoppingCartDTO "Updated shopping cart"
-// @Failure 400 {object} responses.ErrorDTO "Invalid shopping cart ID or item data"
-// @Failure 500 {object} responses.ErrorDTO "Failed to add item to shopping cart"
+// @Param currency query string false "Currency of the prices, the default currency if omitted"
+// @Param Accept-Currency header string false "Currency of the prices when the currency parameter is omitted"
+// @Success 200 {object} dto.ShoppingCartDTO "Updated shopping cart, with its new entity tag in the ETag header"
+// @Failure 400 {object} responses.ProblemDTO "Invalid shopping cart ID or item data"
+// @Failure 404 {object} responses.ProblemDTO "Shopping cart does not exist"
+// @Failure 409 {object} responses.ProblemDTO "Shopping cart already checked out, with code CART_LOCKED, not enough stock, or the product is not published yet or archived, with code PRODUCT_NOT_PUBLISHED or PRODUCT_ARCHIVED"
+// @Failure 412 {object} responses.ProblemDTO "The shopping cart no longer matches If-Match, with code VERSION_CONFLICT"
+// @Failure 500 {object} responses.ProblemDTO "Failed to add item to shopping cart"
 // @Router /cart/{id}/items [post]
 func (ctrl *ShoppingCartController) AddItem(c *gin.Context) {
 	cartID, _ := strconv.Atoi(c.Param("id"))
 
+	version, err := ifMatch(c, ctrl.currentCart(c, uint(cartID)))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
 	var item dto.ItemData
-	if err := c.BindJSON(&item); err != nil {
-		responses.SendError(c, utils.ToUserError(http.StatusBadRequest, "Datos de producto incorrectos", err))
+	if err = c.ShouldBindJSON(&item); err != nil {
+		responses.SendError(c, dto.BindingError(&item, err))
 		return
 	}
 
 	itemsCart := dto.ToItemsCart(uint(cartID), []*dto.ItemData{&item})
-	err := ctrl.shoppingCartService.AddItemsToShoppingCart(itemsCart)
+	err = ctrl.shoppingCartService.AddItemsToShoppingCart(uint(cartID), itemsCart, version)
 	if err != nil {
 		responses.SendError(c, utils.GetCustomError(err))
 		return
 	}
 
-	cart, err := ctrl.shoppingCartService.FindCart(uint(cartID))
+	summary, err := ctrl.shoppingCartService.CartSummary(uint(cartID), requestCurrency(c))
 	if err != nil {
 		responses.SendError(c, utils.GetCustomError(err))
 		return
 	}
 
-	cartDTO := dto.ToShoppingCartDTO(cart)
-	responses.SendSuccess(c, http.StatusOK, cartDTO)
+	sendCart(c, http.StatusOK, summary)
 }
 
 // RemoveItems
 // @Summary Remove items from a shopping cart
-// @Description Removes the specified items from the shopping cart
+// @Description Removes the specified products, with all their variants, from the shopping cart
 // @Tags Shopping Carts
 // @ID remove-items
 // @Accept json
 // @Produce json
 // @Param id path int true "Shopping cart ID"
+// @Param If-Match header string false "ETag of the shopping cart the change was made on, or * for any"
 // @Param productIds body []int true "IDs of the products to remove"
-// @Success 200 {object} dto.ShoppingCartDTO "Updated shopping cart"
-// @Failure 400 {object} responses.ErrorDTO "Invalid shopping cart ID or item IDs"
-// @Failure 500 {object} responses.ErrorDTO "Failed to remove items from shopping cart"
+// @Param currency query string false "Currency of the prices, the default currency if omitted"
+// @Param Accept-Currency header string false "Currency of the prices when the currency parameter is omitted"
+// @Success 200 {object} dto.ShoppingCartDTO "Updated shopping cart, with its new entity tag in the ETag header"
+// @Failure 400 {object} responses.ProblemDTO "Invalid shopping cart ID or item IDs"
+// @Failure 404 {object} responses.ProblemDTO "Shopping cart does not exist"
+// @Failure 409 {object} responses.ProblemDTO "Shopping cart already checked out"
+// @Failure 412 {object} responses.ProblemDTO "The shopping cart no longer matches If-Match, with code VERSION_CONFLICT"
+// @Failure 500 {object} responses.ProblemDTO "Failed to remove items from shopping cart"
 // @Router /cart/{id}/items [delete]
 func (ctrl *ShoppingCartController) RemoveItems(c *gin.Context) {
 	cartID, _ := strconv.Atoi(c.Param("id"))
 
+	version, err := ifMatch(c, ctrl.currentCart(c, uint(cartID)))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
 	var productIds []uint
-	if err := c.BindJSON(&productIds); err != nil {
-		responses.SendError(c, utils.ToUserError(http.StatusBadRequest, "Id de producto incorrecto", err))
+	if err = c.ShouldBindJSON(&productIds); err != nil {
+		responses.SendError(c, dto.BindingError(&productIds, err))
+		return
+	}
+
+	if err = dto.ValidateIDs("productIds", productIds); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
 		return
 	}
 
-	err := ctrl.shoppingCartService.RemoveItemsFromShoppingCart(uint(cartID), productIds)
+	err = ctrl.shoppingCartService.RemoveItemsFromShoppingCart(uint(cartID), productIds, version)
 	if err != nil {
 		responses.SendError(c, utils.GetCustomError(err))
 		return
 	}
 
-	cart, err := ctrl.shoppingCartService.FindCart(uint(cartID))
+	summary, err := ctrl.shoppingCartService.CartSummary(uint(cartID), requestCurrency(c))
 	if err != nil {
 		responses.SendError(c, utils.GetCustomError(err))
 		return
 	}
 
-	cartDTO := dto.ToShoppingCartDTO(cart)
-	responses.SendSuccess(c, http.StatusOK, cartDTO)
+	sendCart(c, http.StatusOK, summary)
+}
+
+// ApplyCoupon
+// @Summary Apply a coupon to a shopping cart
+// @Description Applies the promotion of a coupon code to the shopping cart. The cart response lists the discount of every coupon and the adjusted total.
+// @Tags Shopping Carts
+// @ID apply-coupon
+// @Accept json
+// @Produce json
+// @Param id path int true "Shopping cart ID"
+// @Param If-Match header string false "ETag of the shopping cart the change was made on, or * for any"
+// @Param coupon body dto.CouponData true "Coupon code"
+// @Param currency query string false "Currency of the prices, the default currency if omitted"
+// @Param Accept-Currency header string false "Currency of the prices when the currency parameter is omitted"
+// @Success 200 {object} dto.ShoppingCartDTO "Updated shopping cart, with its new entity tag in the ETag header"
+// @Failure 400 {object} responses.ProblemDTO "Invalid coupon data"
+// @Failure 404 {object} responses.ProblemDTO "Shopping cart or coupon does not exist"
+// @Failure 409 {object} responses.ProblemDTO "Shopping cart already checked out, coupon already applied, expired or exhausted"
+// @Failure 412 {object} responses.ProblemDTO "The shopping cart no longer matches If-Match, with code VERSION_CONFLICT"
+// @Failure 500 {object} responses.ProblemDTO "Failed to apply coupon"
+// @Router /cart/{id}/coupons [post]
+func (ctrl *ShoppingCartController) ApplyCoupon(c *gin.Context) {
+	cartID, _ := strconv.Atoi(c.Param("id"))
+
+	version, err := ifMatch(c, ctrl.currentCart(c, uint(cartID)))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	var coupon dto.CouponData
+	if err = c.ShouldBindJSON(&coupon); err != nil {
+		responses.SendError(c, dto.BindingError(&coupon, err))
+		return
+	}
+
+	code := strings.TrimSpace(strings.ToUpper(coupon.Code))
+	if err = ctrl.shoppingCartService.ApplyCoupon(uint(cartID), code, version); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	summary, err := ctrl.shoppingCartService.CartSummary(uint(cartID), requestCurrency(c))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	sendCart(c, http.StatusOK, summary)
+}
+
+// RemoveCoupon
+// @Summary Remove a coupon from a shopping cart
+// @Description Removes a coupon code from the shopping cart
+// @Tags Shopping Carts
+// @ID remove-coupon
+// @Accept json
+// @Produce json
+// @Param id path int true "Shopping cart ID"
+// @Param If-Match header string false "ETag of the shopping cart the change was made on, or * for any"
+// @Param code path string true "Coupon code"
+// @Param currency query string false "Currency of the prices, the default currency if omitted"
+// @Param Accept-Currency header string false "Currency of the prices when the currency parameter is omitted"
+// @Success 200 {object} dto.ShoppingCartDTO "Updated shopping cart, with its new entity tag in the ETag header"
+// @Failure 404 {object} responses.ProblemDTO "Shopping cart or coupon does not exist, or coupon not applied"
+// @Failure 409 {object} responses.ProblemDTO "Shopping cart already checked out"
+// @Failure 412 {object} responses.ProblemDTO "The shopping cart no longer matches If-Match, with code VERSION_CONFLICT"
+// @Failure 500 {object} responses.ProblemDTO "Failed to remove coupon"
+// @Router /cart/{id}/coupons/{code} [delete]
+func (ctrl *ShoppingCartController) RemoveCoupon(c *gin.Context) {
+	cartID, _ := strconv.Atoi(c.Param("id"))
+	code := strings.TrimSpace(strings.ToUpper(c.Param("code")))
+
+	version, err := ifMatch(c, ctrl.currentCart(c, uint(cartID)))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	if err = ctrl.shoppingCartService.RemoveCoupon(uint(cartID), code, version); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	summary, err := ctrl.shoppingCartService.CartSummary(uint(cartID), requestCurrency(c))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	sendCart(c, http.StatusOK, summary)
+}
+
+// currentCart returns the current representation of a cart for a request,
+// with its version, to evaluate the preconditions of a change.
+func (ctrl *ShoppingCartController) currentCart(c *gin.Context, cartID uint) func() (interface{}, uint, error) {
+	return func() (interface{}, uint, error) {
+		summary, err := ctrl.shoppingCartService.CartSummary(cartID, requestCurrency(c))
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return dto.ToShoppingCartDTO(summary, responses.Language(c)), summary.Cart.Version, nil
+	}
+}
+
+// sendCart sends a cart with the entity tag of its body.
+func sendCart(c *gin.Context, statusCode int, summary *model.CartSummary) {
+	cartDTO := dto.ToShoppingCartDTO(summary, responses.Language(c))
+	setETag(c, etag(cartDTO))
+	responses.SendSuccess(c, statusCode, cartDTO)
 }
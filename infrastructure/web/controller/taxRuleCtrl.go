@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"codifin-challenge/domain/service"
+	"codifin-challenge/domain/utils"
+	"codifin-challenge/infrastructure/web/dto"
+	"codifin-challenge/infrastructure/web/responses"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type TaxRuleController struct {
+	taxService service.TaxService
+}
+
+func NewTaxRuleController(taxService service.TaxService) *TaxRuleController {
+	return &TaxRuleController{taxService: taxService}
+}
+
+// FindTaxRules
+// @Summary Get the list of tax rules
+// @Description Retrieves the tax rate of every tax category in every region
+// @Tags Taxes
+// @ID find-tax-rules
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.TaxRuleDTO "List of tax rules"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve tax rules"
+// @Router /tax-rules [get]
+func (ctrl *TaxRuleController) FindTaxRules(c *gin.Context) {
+	rules, err := ctrl.taxService.TaxRules()
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	rulesDTO := dto.ToTaxRulesDTO(rules)
+	responses.SendSuccess(c, http.StatusOK, rulesDTO)
+}
+
+// FindTaxRule
+// @Summary Get a tax rule by ID
+// @Description Retrieves a tax rule by its ID
+// @Tags Taxes
+// @ID find-tax-rule
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax rule ID"
+// @Success 200 {object} dto.TaxRuleDTO "Tax rule found"
+// @Failure 404 {object} responses.ProblemDTO "Tax rule does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to retrieve tax rule"
+// @Router /tax-rule/{id} [get]
+func (ctrl *TaxRuleController) FindTaxRule(c *gin.Context) {
+	ruleID, _ := strconv.Atoi(c.Param("id"))
+
+	rule, err := ctrl.taxService.TaxRuleByID(uint(ruleID))
+	if err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	ruleDTO := dto.ToTaxRuleDTO(rule)
+	responses.SendSuccess(c, http.StatusOK, ruleDTO)
+}
+
+// NewTaxRule
+// @Summary Create a new tax rule
+// @Description Sets the tax rate, as a fraction between 0 and 1, of a tax category in a region
+// @Tags Taxes
+// @ID new-tax-rule
+// @Accept json
+// @Produce json
+// @Param data body dto.TaxRuleData true "Tax rule data"
+// @Success 201 {object} dto.TaxRuleDTO "Created tax rule"
+// @Failure 400 {object} responses.ProblemDTO "Invalid tax rule data"
+// @Failure 409 {object} responses.ProblemDTO "Tax category already has a rule in the region"
+// @Failure 500 {object} responses.ProblemDTO "Failed to create tax rule"
+// @Router /tax-rules [post]
+func (ctrl *TaxRuleController) NewTaxRule(c *gin.Context) {
+	var ruleData dto.TaxRuleData
+
+	if err := c.ShouldBindJSON(&ruleData); err != nil {
+		responses.SendError(c, dto.BindingError(&ruleData, err))
+		return
+	}
+
+	newRule := ruleData.ToTaxRule()
+
+	if err := ctrl.taxService.CreateTaxRule(newRule); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	ruleDTO := dto.ToTaxRuleDTO(newRule)
+	responses.SendSuccess(c, http.StatusCreated, ruleDTO)
+}
+
+// UpdateTaxRule
+// @Summary Update a tax rule
+// @Description Replaces the region, tax category and rate of a tax rule
+// @Tags Taxes
+// @ID update-tax-rule
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax rule ID"
+// @Param data body dto.TaxRuleData true "Tax rule data"
+// @Success 200 {object} dto.TaxRuleDTO "Updated tax rule"
+// @Failure 400 {object} responses.ProblemDTO "Invalid tax rule data"
+// @Failure 404 {object} responses.ProblemDTO "Tax rule does not exist"
+// @Failure 409 {object} responses.ProblemDTO "Tax category already has a rule in the region"
+// @Failure 500 {object} responses.ProblemDTO "Failed to update tax rule"
+// @Router /tax-rule/{id} [put]
+func (ctrl *TaxRuleController) UpdateTaxRule(c *gin.Context) {
+	ruleID, _ := strconv.Atoi(c.Param("id"))
+
+	var ruleData dto.TaxRuleData
+	if err := c.ShouldBindJSON(&ruleData); err != nil {
+		responses.SendError(c, dto.BindingError(&ruleData, err))
+		return
+	}
+
+	rule := ruleData.ToTaxRule()
+	rule.ID = uint(ruleID)
+
+	if err := ctrl.taxService.UpdateTaxRule(rule); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	ruleDTO := dto.ToTaxRuleDTO(rule)
+	responses.SendSuccess(c, http.StatusOK, ruleDTO)
+}
+
+// RemoveTaxRule
+// @Summary Delete a tax rule
+// @Description Deletes a tax rule. Products of its tax category are then taxed at the standard rate of the region.
+// @Tags Taxes
+// @ID remove-tax-rule
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax rule ID"
+// @Success 200 {object} responses.SuccessDTO "Tax rule deleted successfully"
+// @Failure 404 {object} responses.ProblemDTO "Tax rule does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to delete tax rule"
+// @Router /tax-rule/{id} [delete]
+func (ctrl *TaxRuleController) RemoveTaxRule(c *gin.Context) {
+	ruleID, _ := strconv.Atoi(c.Param("id"))
+
+	if err := ctrl.taxService.DeleteTaxRule(uint(ruleID)); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	resp := responses.NewSuccess(responses.TaxRuleDeleted)
+	responses.SendSuccess(c, http.StatusOK, resp)
+}
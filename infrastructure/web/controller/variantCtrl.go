@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"codifin-challenge/domain/service"
+	"codifin-challenge/domain/utils"
+	"codifin-challenge/infrastructure/web/dto"
+	"codifin-challenge/infrastructure/web/responses"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type VariantController struct {
+	variantService service.VariantService
+}
+
+func NewVariantController(variantService service.VariantService) *VariantController {
+	return &VariantController{variantService: variantService}
+}
+
+// NewVariant
+// @Summary Create a product variant
+// @Description Adds a variant, such as a size or a color, with its own SKU, price override, stock and attributes to a product
+// @Tags Products
+// @ID new-variant
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param data body dto.VariantData true "Variant data"
+// @Success 201 {object} dto.VariantDTO "Created variant"
+// @Failure 400 {object} responses.ProblemDTO "Invalid variant data"
+// @Failure 404 {object} responses.ProblemDTO "Product does not exist"
+// @Failure 409 {object} responses.ProblemDTO "SKU already registered"
+// @Failure 500 {object} responses.ProblemDTO "Failed to create variant"
+// @Router /product/{id}/variants [post]
+func (ctrl *VariantController) NewVariant(c *gin.Context) {
+	productID, _ := strconv.Atoi(c.Param("id"))
+
+	var variantData dto.VariantData
+	if err := c.ShouldBindJSON(&variantData); err != nil {
+		responses.SendError(c, dto.BindingError(&variantData, err))
+		return
+	}
+
+	newVariant := variantData.ToVariant(uint(productID))
+
+	if err := ctrl.variantService.CreateVariant(newVariant); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	variantDTO := dto.ToVariantDTO(newVariant)
+	responses.SendSuccess(c, http.StatusCreated, variantDTO)
+}
+
+// UpdateVariant
+// @Summary Update a product variant
+// @Description Replaces the SKU, price override, stock and attributes of a product variant
+// @Tags Products
+// @ID update-variant
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variantID path int true "Variant ID"
+// @Param data body dto.VariantData true "Variant data"
+// @Success 200 {object} dto.VariantDTO "Updated variant"
+// @Failure 400 {object} responses.ProblemDTO "Invalid variant data"
+// @Failure 404 {object} responses.ProblemDTO "Variant does not exist"
+// @Failure 409 {object} responses.ProblemDTO "SKU already registered"
+// @Failure 500 {object} responses.ProblemDTO "Failed to update variant"
+// @Router /product/{id}/variants/{variantID} [put]
+func (ctrl *VariantController) UpdateVariant(c *gin.Context) {
+	productID, _ := strconv.Atoi(c.Param("id"))
+	variantID, _ := strconv.Atoi(c.Param("variantID"))
+
+	var variantData dto.VariantData
+	if err := c.ShouldBindJSON(&variantData); err != nil {
+		responses.SendError(c, dto.BindingError(&variantData, err))
+		return
+	}
+
+	variant := variantData.ToVariant(uint(productID))
+	variant.ID = uint(variantID)
+
+	if err := ctrl.variantService.UpdateVariant(variant); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	variantDTO := dto.ToVariantDTO(variant)
+	responses.SendSuccess(c, http.StatusOK, variantDTO)
+}
+
+// RemoveVariant
+// @Summary Delete a product variant
+// @Description Deletes a variant of a product by its ID
+// @Tags Products
+// @ID remove-variant
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variantID path int true "Variant ID"
+// @Success 200 {object} responses.SuccessDTO "Variant deleted successfully"
+// @Failure 404 {object} responses.ProblemDTO "Variant does not exist"
+// @Failure 500 {object} responses.ProblemDTO "Failed to delete variant"
+// @Router /product/{id}/variants/{variantID} [delete]
+func (ctrl *VariantController) RemoveVariant(c *gin.Context) {
+	productID, _ := strconv.Atoi(c.Param("id"))
+	variantID, _ := strconv.Atoi(c.Param("variantID"))
+
+	if err := ctrl.variantService.DeleteVariant(uint(productID), uint(variantID)); err != nil {
+		responses.SendError(c, utils.GetCustomError(err))
+		return
+	}
+
+	resp := responses.NewSuccess(responses.VariantDeleted)
+	responses.SendSuccess(c, http.StatusOK, resp)
+}
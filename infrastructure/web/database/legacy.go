@@ -0,0 +1,198 @@
+package database
+
+import (
+	"codifin-challenge/domain/model"
+	"fmt"
+	"gorm.io/gorm"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	createTablePattern = regexp.MustCompile(`^\s*CREATE\s+TABLE\s+(\w+)`)
+	createIndexPattern = regexp.MustCompile(`^\s*CREATE\s+(?:UNIQUE\s+)?INDEX\s+(\w+)\s+ON\s+(\w+)`)
+)
+
+// moneyColumns lists the float columns of the tables created with AutoMigrate
+// that were replaced by money columns named after them, such as price by
+// price_amount and price_currency.
+var moneyColumns = []struct {
+	table   string
+	columns []string
+}{
+	{"products", []string{"price"}},
+	{"product_variants", []string{"price"}},
+	{"promotions", []string{"threshold"}},
+	{"orders", []string{"subtotal", "discount_total", "tax_total", "total"}},
+	{"order_lines", []string{"unit_price", "line_total", "discount", "tax"}},
+	{"order_discounts", []string{"amount"}},
+}
+
+// adoptLegacySchema brings a database the server created with AutoMigrate,
+// before the schema was versioned, to the schema of the initial migration and
+// records it as applied. Missing tables, columns and indexes are created and
+// float amounts are converted to money columns, so databases of any release
+// can be upgraded in place. The tax rules seeded by the server are kept and
+// their migration is recorded as applied too.
+func (m *Migrator) adoptLegacySchema(conn *gorm.DB) error {
+	initial := m.migrations[0]
+	seeded := conn.Migrator().HasTable("tax_rules")
+
+	err := m.apply(conn, func(tx *gorm.DB) error {
+		if err := m.completeSchema(tx, initial.Up); err != nil {
+			return err
+		}
+
+		if err := convertMoneyColumns(tx); err != nil {
+			return fmt.Errorf("error converting money columns: %w", err)
+		}
+
+		// The initial migration is followed by the seed of the tax rules.
+		adopted := m.migrations[:1]
+		if seeded {
+			adopted = m.migrations[:2]
+		}
+
+		for _, migration := range adopted {
+			record := &schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}
+			if err := tx.Create(record).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error adopting legacy schema: %w", err)
+	}
+
+	return nil
+}
+
+// completeSchema runs the statements of the initial migration that create
+// tables and indexes missing from the database, and adds the missing columns
+// of the tables that exist.
+func (m *Migrator) completeSchema(tx *gorm.DB, script string) error {
+	statements, err := splitStatements(script)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		if match := createTablePattern.FindStringSubmatch(statement); match != nil && tx.Migrator().HasTable(match[1]) {
+			if err = m.completeTable(tx, match[1], statement); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if match := createIndexPattern.FindStringSubmatch(statement); match != nil && tx.Migrator().HasIndex(match[2], match[1]) {
+			continue
+		}
+
+		if err = tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// completeTable adds to an existing table the columns and constraints of its
+// CREATE TABLE statement that it lacks. SQLite can not add constraints to an
+// existing table, so its tables are left without them.
+func (m *Migrator) completeTable(tx *gorm.DB, table, statement string) error {
+	lines := strings.Split(statement, "\n")
+
+	for _, line := range lines[1:] {
+		definition := strings.TrimSuffix(strings.TrimSpace(line), ",")
+		if definition == "" || definition == ");" || strings.HasPrefix(definition, "PRIMARY KEY") {
+			continue
+		}
+
+		fields := strings.Fields(definition)
+		if fields[0] == "CONSTRAINT" {
+			if m.dialect == "sqlite" || tx.Migrator().HasConstraint(table, fields[1]) {
+				continue
+			}
+			if err := tx.Exec("ALTER TABLE " + table + " ADD " + definition).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		if hasColumn(tx, table, fields[0]) {
+			continue
+		}
+		if err := tx.Exec("ALTER TABLE " + table + " ADD COLUMN " + definition).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// convertMoneyColumns moves the float amounts into the money columns and drops
+// the float columns. The old amounts are taken as amounts of the default
+// currency, rounded to its minor unit.
+func convertMoneyColumns(tx *gorm.DB) error {
+	factor := math.Pow10(model.MinorUnits(model.DefaultCurrency))
+
+	// Fixed amount promotions kept the amount off in value.
+	if hasColumn(tx, "promotions", "threshold") {
+		err := tx.Table("promotions").
+			Where("type = ?", model.PromotionFixedAmount).
+			UpdateColumns(map[string]interface{}{
+				"amount_off_amount":   gorm.Expr("ROUND(value * ?)", factor),
+				"amount_off_currency": model.DefaultCurrency,
+				"value":               0,
+			}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, v := range moneyColumns {
+		for _, column := range v.columns {
+			if !hasColumn(tx, v.table, column) {
+				continue
+			}
+
+			err := tx.Table(v.table).
+				Where(column + " IS NOT NULL").
+				UpdateColumns(map[string]interface{}{
+					column + "_amount":   gorm.Expr("ROUND("+column+" * ?)", factor),
+					column + "_currency": model.DefaultCurrency,
+				}).Error
+			if err != nil {
+				return err
+			}
+
+			if err = tx.Exec("ALTER TABLE " + v.table + " DROP COLUMN " + column).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasColumn tells whether a table has a column. The SQLite migrator of gorm
+// also finds columns whose name merely contains the one looked for, such as
+// amount in amount_amount.
+func hasColumn(tx *gorm.DB, table, column string) bool {
+	columns, err := tx.Migrator().ColumnTypes(table)
+	if err != nil {
+		return false
+	}
+
+	for _, c := range columns {
+		if c.Name() == column {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,21 +1,13 @@
 package database
 
-import (
-	"codifin-challenge/domain/model"
-	"fmt"
-	"gorm.io/gorm"
-)
+import "gorm.io/gorm"
 
+// RunMigrations applies the pending migrations of the database.
 func RunMigrations(db *gorm.DB) error {
-	models := []interface{}{
-		&model.Product{},
-		&model.ShoppingCart{},
-		&model.ItemCart{},
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
 	}
 
-	if err := db.AutoMigrate(models...); err != nil {
-		return fmt.Errorf("error auto-migrating schema: %w", err)
-	}
-
-	return nil
+	return migrator.Up()
 }
@@ -0,0 +1,249 @@
+-- Schema of the catalog, shopping carts, promotions, taxes and orders.
+
+CREATE TABLE tax_rules (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    region varchar(191) NOT NULL,
+    tax_category varchar(191) NOT NULL,
+    rate double NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_tax_rule ON tax_rules (region, tax_category);
+CREATE INDEX idx_tax_rules_deleted_at ON tax_rules (deleted_at);
+
+CREATE TABLE categories (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    name longtext NOT NULL,
+    parent_id bigint unsigned,
+    CONSTRAINT fk_categories_children FOREIGN KEY (parent_id) REFERENCES categories (id)
+);
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
+CREATE INDEX idx_categories_deleted_at ON categories (deleted_at);
+
+CREATE TABLE products (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    code longtext,
+    name longtext,
+    price_amount bigint,
+    price_currency varchar(3),
+    image_url longtext,
+    tax_category varchar(191) NOT NULL DEFAULT 'standard',
+    stock bigint unsigned NOT NULL DEFAULT 0,
+    reserved bigint unsigned NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_products_deleted_at ON products (deleted_at);
+
+CREATE TABLE product_categories (
+    product_id bigint unsigned,
+    category_id bigint unsigned,
+    PRIMARY KEY (product_id, category_id),
+    CONSTRAINT fk_product_categories_product FOREIGN KEY (product_id) REFERENCES products (id),
+    CONSTRAINT fk_product_categories_category FOREIGN KEY (category_id) REFERENCES categories (id)
+);
+
+CREATE TABLE product_variants (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    product_id bigint unsigned NOT NULL,
+    sku varchar(191) NOT NULL,
+    price_amount bigint,
+    price_currency varchar(3),
+    stock bigint unsigned NOT NULL DEFAULT 0,
+    reserved bigint unsigned NOT NULL DEFAULT 0,
+    attributes text,
+    CONSTRAINT fk_products_variants FOREIGN KEY (product_id) REFERENCES products (id)
+);
+CREATE INDEX idx_product_variants_sku ON product_variants (sku);
+CREATE INDEX idx_product_variants_product_id ON product_variants (product_id);
+CREATE INDEX idx_product_variants_deleted_at ON product_variants (deleted_at);
+
+CREATE TABLE exchange_rates (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    currency varchar(3) NOT NULL,
+    rate double NOT NULL
+);
+CREATE UNIQUE INDEX idx_exchange_rates_currency ON exchange_rates (currency);
+CREATE INDEX idx_exchange_rates_deleted_at ON exchange_rates (deleted_at);
+
+CREATE TABLE product_prices (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    product_id bigint unsigned NOT NULL,
+    currency varchar(3) NOT NULL,
+    amount bigint NOT NULL
+);
+CREATE UNIQUE INDEX idx_product_price ON product_prices (product_id, currency);
+CREATE INDEX idx_product_prices_deleted_at ON product_prices (deleted_at);
+
+CREATE TABLE promotions (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    code varchar(191) NOT NULL,
+    description longtext,
+    type longtext NOT NULL,
+    value double,
+    amount_off_amount bigint,
+    amount_off_currency varchar(3),
+    buy_quantity bigint unsigned,
+    get_quantity bigint unsigned,
+    threshold_amount bigint,
+    threshold_currency varchar(3),
+    free_product_id bigint unsigned,
+    starts_at datetime(3),
+    ends_at datetime(3),
+    usage_limit bigint unsigned,
+    usage_count bigint unsigned NOT NULL DEFAULT 0,
+    per_cart_limit bigint unsigned
+);
+CREATE UNIQUE INDEX idx_promotions_code ON promotions (code);
+CREATE INDEX idx_promotions_deleted_at ON promotions (deleted_at);
+
+CREATE TABLE promotion_categories (
+    promotion_id bigint unsigned,
+    category_id bigint unsigned,
+    PRIMARY KEY (promotion_id, category_id),
+    CONSTRAINT fk_promotion_categories_promotion FOREIGN KEY (promotion_id) REFERENCES promotions (id),
+    CONSTRAINT fk_promotion_categories_category FOREIGN KEY (category_id) REFERENCES categories (id)
+);
+
+CREATE TABLE promotion_products (
+    promotion_id bigint unsigned,
+    product_id bigint unsigned,
+    PRIMARY KEY (promotion_id, product_id),
+    CONSTRAINT fk_promotion_products_promotion FOREIGN KEY (promotion_id) REFERENCES promotions (id),
+    CONSTRAINT fk_promotion_products_product FOREIGN KEY (product_id) REFERENCES products (id)
+);
+
+CREATE TABLE shopping_carts (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    region varchar(191) NOT NULL DEFAULT 'MX',
+    checked_out_at datetime(3)
+);
+CREATE INDEX idx_shopping_carts_deleted_at ON shopping_carts (deleted_at);
+
+CREATE TABLE cart_coupons (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    shopping_cart_id bigint unsigned NOT NULL,
+    promotion_id bigint unsigned NOT NULL,
+    CONSTRAINT fk_cart_coupons_promotion FOREIGN KEY (promotion_id) REFERENCES promotions (id),
+    CONSTRAINT fk_shopping_carts_coupons FOREIGN KEY (shopping_cart_id) REFERENCES shopping_carts (id)
+);
+CREATE UNIQUE INDEX idx_cart_coupon ON cart_coupons (shopping_cart_id, promotion_id);
+CREATE INDEX idx_cart_coupons_deleted_at ON cart_coupons (deleted_at);
+
+CREATE TABLE item_carts (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    shopping_cart_id bigint unsigned NOT NULL,
+    product_id bigint unsigned NOT NULL,
+    variant_id bigint unsigned,
+    count bigint unsigned DEFAULT 0,
+    CONSTRAINT fk_item_carts_product FOREIGN KEY (product_id) REFERENCES products (id),
+    CONSTRAINT fk_item_carts_variant FOREIGN KEY (variant_id) REFERENCES product_variants (id),
+    CONSTRAINT fk_shopping_carts_items FOREIGN KEY (shopping_cart_id) REFERENCES shopping_carts (id)
+);
+CREATE INDEX idx_item_carts_variant_id ON item_carts (variant_id);
+CREATE INDEX idx_item_carts_deleted_at ON item_carts (deleted_at);
+
+CREATE TABLE stock_reservations (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    shopping_cart_id bigint unsigned NOT NULL,
+    product_id bigint unsigned NOT NULL,
+    variant_id bigint unsigned,
+    quantity bigint unsigned NOT NULL,
+    expires_at datetime(3) NOT NULL
+);
+CREATE INDEX idx_stock_reservations_variant_id ON stock_reservations (variant_id);
+CREATE INDEX idx_stock_reservations_product_id ON stock_reservations (product_id);
+CREATE INDEX idx_stock_reservations_shopping_cart_id ON stock_reservations (shopping_cart_id);
+CREATE INDEX idx_stock_reservations_deleted_at ON stock_reservations (deleted_at);
+CREATE INDEX idx_stock_reservations_expires_at ON stock_reservations (expires_at);
+
+CREATE TABLE orders (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    shopping_cart_id bigint unsigned NOT NULL,
+    status varchar(191) NOT NULL DEFAULT 'pending',
+    region longtext,
+    item_count bigint unsigned,
+    subtotal_amount bigint,
+    subtotal_currency varchar(3),
+    discount_total_amount bigint,
+    discount_total_currency varchar(3),
+    tax_total_amount bigint,
+    tax_total_currency varchar(3),
+    total_amount bigint,
+    total_currency varchar(3),
+    prices_include_tax boolean,
+    CONSTRAINT fk_orders_shopping_cart FOREIGN KEY (shopping_cart_id) REFERENCES shopping_carts (id)
+);
+CREATE UNIQUE INDEX idx_orders_shopping_cart_id ON orders (shopping_cart_id);
+CREATE INDEX idx_orders_deleted_at ON orders (deleted_at);
+
+CREATE TABLE order_lines (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    order_id bigint unsigned NOT NULL,
+    product_id bigint unsigned NOT NULL,
+    variant_id bigint unsigned,
+    code longtext,
+    sku longtext,
+    name longtext,
+    unit_price_amount bigint,
+    unit_price_currency varchar(3),
+    count bigint unsigned,
+    line_total_amount bigint,
+    line_total_currency varchar(3),
+    discount_amount bigint,
+    discount_currency varchar(3),
+    tax_rate double,
+    tax_amount bigint,
+    tax_currency varchar(3),
+    CONSTRAINT fk_orders_lines FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+CREATE INDEX idx_order_lines_deleted_at ON order_lines (deleted_at);
+
+CREATE TABLE order_discounts (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    order_id bigint unsigned NOT NULL,
+    promotion_id bigint unsigned NOT NULL,
+    code longtext,
+    amount_amount bigint,
+    amount_currency varchar(3),
+    CONSTRAINT fk_orders_discounts FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+CREATE INDEX idx_order_discounts_deleted_at ON order_discounts (deleted_at);
@@ -0,0 +1,8 @@
+-- Mexican IVA rates: 16% in general, 8% in the northern border region and
+-- exempt items.
+
+INSERT INTO tax_rules (created_at, updated_at, region, tax_category, rate) VALUES
+    (CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, 'MX', 'standard', 0.16),
+    (CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, 'MX', 'exempt', 0),
+    (CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, 'MX-BORDER', 'standard', 0.08),
+    (CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, 'MX-BORDER', 'exempt', 0);
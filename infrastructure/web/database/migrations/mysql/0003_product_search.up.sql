@@ -0,0 +1 @@
+ALTER TABLE products ADD COLUMN description longtext;
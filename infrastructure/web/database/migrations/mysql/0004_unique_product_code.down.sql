@@ -0,0 +1,2 @@
+DROP INDEX idx_products_code ON products;
+ALTER TABLE products DROP COLUMN active_code;
@@ -0,0 +1,7 @@
+-- Products that are not deleted cannot share a code. Products without a code
+-- are left out, since they cannot be looked up by it. MySQL has no partial
+-- indexes, so the index is on a column that is NULL for the rows left out.
+-- Fails if some products already share a code, which must be fixed by hand
+-- before migrating.
+ALTER TABLE products ADD COLUMN active_code varchar(255) GENERATED ALWAYS AS (IF(deleted_at IS NULL AND code <> '', code, NULL)) VIRTUAL;
+CREATE UNIQUE INDEX idx_products_code ON products (active_code);
@@ -0,0 +1,5 @@
+-- Products that already exist stay on sale.
+ALTER TABLE products ADD COLUMN status varchar(191) NOT NULL DEFAULT 'active';
+ALTER TABLE products ADD COLUMN publish_at datetime(3);
+ALTER TABLE products ADD COLUMN unpublish_at datetime(3);
+CREATE INDEX idx_products_status ON products (status);
@@ -0,0 +1,4 @@
+-- The version of a product or cart changes with every change to it, so
+-- clients can tell whether what they read is still current.
+ALTER TABLE products ADD COLUMN version bigint unsigned NOT NULL DEFAULT 1;
+ALTER TABLE shopping_carts ADD COLUMN version bigint unsigned NOT NULL DEFAULT 1;
@@ -0,0 +1,17 @@
+DROP TABLE order_discounts;
+DROP TABLE order_lines;
+DROP TABLE orders;
+DROP TABLE stock_reservations;
+DROP TABLE item_carts;
+DROP TABLE cart_coupons;
+DROP TABLE shopping_carts;
+DROP TABLE promotion_products;
+DROP TABLE promotion_categories;
+DROP TABLE promotions;
+DROP TABLE product_prices;
+DROP TABLE exchange_rates;
+DROP TABLE product_variants;
+DROP TABLE product_categories;
+DROP TABLE products;
+DROP TABLE categories;
+DROP TABLE tax_rules;
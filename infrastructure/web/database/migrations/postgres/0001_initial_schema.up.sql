@@ -0,0 +1,249 @@
+-- Schema of the catalog, shopping carts, promotions, taxes and orders.
+
+CREATE TABLE tax_rules (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    region text NOT NULL,
+    tax_category text NOT NULL,
+    rate decimal NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_tax_rule ON tax_rules (region, tax_category);
+CREATE INDEX idx_tax_rules_deleted_at ON tax_rules (deleted_at);
+
+CREATE TABLE categories (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name text NOT NULL,
+    parent_id bigint,
+    CONSTRAINT fk_categories_children FOREIGN KEY (parent_id) REFERENCES categories (id)
+);
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
+CREATE INDEX idx_categories_deleted_at ON categories (deleted_at);
+
+CREATE TABLE products (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    code text,
+    name text,
+    price_amount bigint,
+    price_currency varchar(3),
+    image_url text,
+    tax_category text NOT NULL DEFAULT 'standard',
+    stock bigint NOT NULL DEFAULT 0,
+    reserved bigint NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_products_deleted_at ON products (deleted_at);
+
+CREATE TABLE product_categories (
+    product_id bigint,
+    category_id bigint,
+    PRIMARY KEY (product_id, category_id),
+    CONSTRAINT fk_product_categories_product FOREIGN KEY (product_id) REFERENCES products (id),
+    CONSTRAINT fk_product_categories_category FOREIGN KEY (category_id) REFERENCES categories (id)
+);
+
+CREATE TABLE product_variants (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    product_id bigint NOT NULL,
+    sku text NOT NULL,
+    price_amount bigint,
+    price_currency varchar(3),
+    stock bigint NOT NULL DEFAULT 0,
+    reserved bigint NOT NULL DEFAULT 0,
+    attributes text,
+    CONSTRAINT fk_products_variants FOREIGN KEY (product_id) REFERENCES products (id)
+);
+CREATE INDEX idx_product_variants_sku ON product_variants (sku);
+CREATE INDEX idx_product_variants_product_id ON product_variants (product_id);
+CREATE INDEX idx_product_variants_deleted_at ON product_variants (deleted_at);
+
+CREATE TABLE exchange_rates (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    currency varchar(3) NOT NULL,
+    rate decimal NOT NULL
+);
+CREATE UNIQUE INDEX idx_exchange_rates_currency ON exchange_rates (currency);
+CREATE INDEX idx_exchange_rates_deleted_at ON exchange_rates (deleted_at);
+
+CREATE TABLE product_prices (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    product_id bigint NOT NULL,
+    currency varchar(3) NOT NULL,
+    amount bigint NOT NULL
+);
+CREATE UNIQUE INDEX idx_product_price ON product_prices (product_id, currency);
+CREATE INDEX idx_product_prices_deleted_at ON product_prices (deleted_at);
+
+CREATE TABLE promotions (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    code text NOT NULL,
+    description text,
+    type text NOT NULL,
+    value decimal,
+    amount_off_amount bigint,
+    amount_off_currency varchar(3),
+    buy_quantity bigint,
+    get_quantity bigint,
+    threshold_amount bigint,
+    threshold_currency varchar(3),
+    free_product_id bigint,
+    starts_at timestamptz,
+    ends_at timestamptz,
+    usage_limit bigint,
+    usage_count bigint NOT NULL DEFAULT 0,
+    per_cart_limit bigint
+);
+CREATE UNIQUE INDEX idx_promotions_code ON promotions (code);
+CREATE INDEX idx_promotions_deleted_at ON promotions (deleted_at);
+
+CREATE TABLE promotion_categories (
+    promotion_id bigint,
+    category_id bigint,
+    PRIMARY KEY (promotion_id, category_id),
+    CONSTRAINT fk_promotion_categories_promotion FOREIGN KEY (promotion_id) REFERENCES promotions (id),
+    CONSTRAINT fk_promotion_categories_category FOREIGN KEY (category_id) REFERENCES categories (id)
+);
+
+CREATE TABLE promotion_products (
+    promotion_id bigint,
+    product_id bigint,
+    PRIMARY KEY (promotion_id, product_id),
+    CONSTRAINT fk_promotion_products_promotion FOREIGN KEY (promotion_id) REFERENCES promotions (id),
+    CONSTRAINT fk_promotion_products_product FOREIGN KEY (product_id) REFERENCES products (id)
+);
+
+CREATE TABLE shopping_carts (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    region text NOT NULL DEFAULT 'MX',
+    checked_out_at timestamptz
+);
+CREATE INDEX idx_shopping_carts_deleted_at ON shopping_carts (deleted_at);
+
+CREATE TABLE cart_coupons (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    shopping_cart_id bigint NOT NULL,
+    promotion_id bigint NOT NULL,
+    CONSTRAINT fk_cart_coupons_promotion FOREIGN KEY (promotion_id) REFERENCES promotions (id),
+    CONSTRAINT fk_shopping_carts_coupons FOREIGN KEY (shopping_cart_id) REFERENCES shopping_carts (id)
+);
+CREATE UNIQUE INDEX idx_cart_coupon ON cart_coupons (shopping_cart_id, promotion_id);
+CREATE INDEX idx_cart_coupons_deleted_at ON cart_coupons (deleted_at);
+
+CREATE TABLE item_carts (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    shopping_cart_id bigint NOT NULL,
+    product_id bigint NOT NULL,
+    variant_id bigint,
+    count bigint DEFAULT 0,
+    CONSTRAINT fk_item_carts_product FOREIGN KEY (product_id) REFERENCES products (id),
+    CONSTRAINT fk_item_carts_variant FOREIGN KEY (variant_id) REFERENCES product_variants (id),
+    CONSTRAINT fk_shopping_carts_items FOREIGN KEY (shopping_cart_id) REFERENCES shopping_carts (id)
+);
+CREATE INDEX idx_item_carts_variant_id ON item_carts (variant_id);
+CREATE INDEX idx_item_carts_deleted_at ON item_carts (deleted_at);
+
+CREATE TABLE stock_reservations (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    shopping_cart_id bigint NOT NULL,
+    product_id bigint NOT NULL,
+    variant_id bigint,
+    quantity bigint NOT NULL,
+    expires_at timestamptz NOT NULL
+);
+CREATE INDEX idx_stock_reservations_variant_id ON stock_reservations (variant_id);
+CREATE INDEX idx_stock_reservations_product_id ON stock_reservations (product_id);
+CREATE INDEX idx_stock_reservations_shopping_cart_id ON stock_reservations (shopping_cart_id);
+CREATE INDEX idx_stock_reservations_deleted_at ON stock_reservations (deleted_at);
+CREATE INDEX idx_stock_reservations_expires_at ON stock_reservations (expires_at);
+
+CREATE TABLE orders (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    shopping_cart_id bigint NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    region text,
+    item_count bigint,
+    subtotal_amount bigint,
+    subtotal_currency varchar(3),
+    discount_total_amount bigint,
+    discount_total_currency varchar(3),
+    tax_total_amount bigint,
+    tax_total_currency varchar(3),
+    total_amount bigint,
+    total_currency varchar(3),
+    prices_include_tax boolean,
+    CONSTRAINT fk_orders_shopping_cart FOREIGN KEY (shopping_cart_id) REFERENCES shopping_carts (id)
+);
+CREATE UNIQUE INDEX idx_orders_shopping_cart_id ON orders (shopping_cart_id);
+CREATE INDEX idx_orders_deleted_at ON orders (deleted_at);
+
+CREATE TABLE order_lines (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    order_id bigint NOT NULL,
+    product_id bigint NOT NULL,
+    variant_id bigint,
+    code text,
+    sku text,
+    name text,
+    unit_price_amount bigint,
+    unit_price_currency varchar(3),
+    count bigint,
+    line_total_amount bigint,
+    line_total_currency varchar(3),
+    discount_amount bigint,
+    discount_currency varchar(3),
+    tax_rate decimal,
+    tax_amount bigint,
+    tax_currency varchar(3),
+    CONSTRAINT fk_orders_lines FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+CREATE INDEX idx_order_lines_deleted_at ON order_lines (deleted_at);
+
+CREATE TABLE order_discounts (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    order_id bigint NOT NULL,
+    promotion_id bigint NOT NULL,
+    code text,
+    amount_amount bigint,
+    amount_currency varchar(3),
+    CONSTRAINT fk_orders_discounts FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+CREATE INDEX idx_order_discounts_deleted_at ON order_discounts (deleted_at);
@@ -0,0 +1,2 @@
+DROP FUNCTION search_fold(text);
+ALTER TABLE products DROP COLUMN description;
@@ -0,0 +1,10 @@
+ALTER TABLE products ADD COLUMN description text;
+
+-- search_fold folds texts like search.Fold: lowercase, without accents, and
+-- with single spaces between letters and digits. unaccent is not immutable,
+-- so it is called with an explicit dictionary.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE FUNCTION search_fold(value text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE
+    AS $$ SELECT btrim(regexp_replace(lower(public.unaccent('public.unaccent'::regdictionary, value)), '[^a-z0-9]+', ' ', 'g')) $$;
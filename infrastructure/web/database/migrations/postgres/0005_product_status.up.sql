@@ -0,0 +1,5 @@
+-- Products that already exist stay on sale.
+ALTER TABLE products ADD COLUMN status text NOT NULL DEFAULT 'active';
+ALTER TABLE products ADD COLUMN publish_at timestamptz;
+ALTER TABLE products ADD COLUMN unpublish_at timestamptz;
+CREATE INDEX idx_products_status ON products (status);
@@ -0,0 +1,2 @@
+ALTER TABLE shopping_carts DROP COLUMN version;
+ALTER TABLE products DROP COLUMN version;
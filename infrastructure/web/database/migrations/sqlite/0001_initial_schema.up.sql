@@ -0,0 +1,249 @@
+-- Schema of the catalog, shopping carts, promotions, taxes and orders.
+
+CREATE TABLE tax_rules (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    region text NOT NULL,
+    tax_category text NOT NULL,
+    rate real NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX idx_tax_rule ON tax_rules (region, tax_category);
+CREATE INDEX idx_tax_rules_deleted_at ON tax_rules (deleted_at);
+
+CREATE TABLE categories (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    name text NOT NULL,
+    parent_id integer,
+    CONSTRAINT fk_categories_children FOREIGN KEY (parent_id) REFERENCES categories (id)
+);
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
+CREATE INDEX idx_categories_deleted_at ON categories (deleted_at);
+
+CREATE TABLE products (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    code text,
+    name text,
+    price_amount integer,
+    price_currency text,
+    image_url text,
+    tax_category text NOT NULL DEFAULT 'standard',
+    stock integer NOT NULL DEFAULT 0,
+    reserved integer NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_products_deleted_at ON products (deleted_at);
+
+CREATE TABLE product_categories (
+    product_id integer,
+    category_id integer,
+    PRIMARY KEY (product_id, category_id),
+    CONSTRAINT fk_product_categories_product FOREIGN KEY (product_id) REFERENCES products (id),
+    CONSTRAINT fk_product_categories_category FOREIGN KEY (category_id) REFERENCES categories (id)
+);
+
+CREATE TABLE product_variants (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    product_id integer NOT NULL,
+    sku text NOT NULL,
+    price_amount integer,
+    price_currency text,
+    stock integer NOT NULL DEFAULT 0,
+    reserved integer NOT NULL DEFAULT 0,
+    attributes text,
+    CONSTRAINT fk_products_variants FOREIGN KEY (product_id) REFERENCES products (id)
+);
+CREATE INDEX idx_product_variants_sku ON product_variants (sku);
+CREATE INDEX idx_product_variants_product_id ON product_variants (product_id);
+CREATE INDEX idx_product_variants_deleted_at ON product_variants (deleted_at);
+
+CREATE TABLE exchange_rates (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    currency text NOT NULL,
+    rate real NOT NULL
+);
+CREATE UNIQUE INDEX idx_exchange_rates_currency ON exchange_rates (currency);
+CREATE INDEX idx_exchange_rates_deleted_at ON exchange_rates (deleted_at);
+
+CREATE TABLE product_prices (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    product_id integer NOT NULL,
+    currency text NOT NULL,
+    amount integer NOT NULL
+);
+CREATE UNIQUE INDEX idx_product_price ON product_prices (product_id, currency);
+CREATE INDEX idx_product_prices_deleted_at ON product_prices (deleted_at);
+
+CREATE TABLE promotions (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    code text NOT NULL,
+    description text,
+    type text NOT NULL,
+    value real,
+    amount_off_amount integer,
+    amount_off_currency text,
+    buy_quantity integer,
+    get_quantity integer,
+    threshold_amount integer,
+    threshold_currency text,
+    free_product_id integer,
+    starts_at datetime,
+    ends_at datetime,
+    usage_limit integer,
+    usage_count integer NOT NULL DEFAULT 0,
+    per_cart_limit integer
+);
+CREATE UNIQUE INDEX idx_promotions_code ON promotions (code);
+CREATE INDEX idx_promotions_deleted_at ON promotions (deleted_at);
+
+CREATE TABLE promotion_categories (
+    promotion_id integer,
+    category_id integer,
+    PRIMARY KEY (promotion_id, category_id),
+    CONSTRAINT fk_promotion_categories_promotion FOREIGN KEY (promotion_id) REFERENCES promotions (id),
+    CONSTRAINT fk_promotion_categories_category FOREIGN KEY (category_id) REFERENCES categories (id)
+);
+
+CREATE TABLE promotion_products (
+    promotion_id integer,
+    product_id integer,
+    PRIMARY KEY (promotion_id, product_id),
+    CONSTRAINT fk_promotion_products_promotion FOREIGN KEY (promotion_id) REFERENCES promotions (id),
+    CONSTRAINT fk_promotion_products_product FOREIGN KEY (product_id) REFERENCES products (id)
+);
+
+CREATE TABLE shopping_carts (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    region text NOT NULL DEFAULT 'MX',
+    checked_out_at datetime
+);
+CREATE INDEX idx_shopping_carts_deleted_at ON shopping_carts (deleted_at);
+
+CREATE TABLE cart_coupons (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    shopping_cart_id integer NOT NULL,
+    promotion_id integer NOT NULL,
+    CONSTRAINT fk_cart_coupons_promotion FOREIGN KEY (promotion_id) REFERENCES promotions (id),
+    CONSTRAINT fk_shopping_carts_coupons FOREIGN KEY (shopping_cart_id) REFERENCES shopping_carts (id)
+);
+CREATE UNIQUE INDEX idx_cart_coupon ON cart_coupons (shopping_cart_id, promotion_id);
+CREATE INDEX idx_cart_coupons_deleted_at ON cart_coupons (deleted_at);
+
+CREATE TABLE item_carts (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    shopping_cart_id integer NOT NULL,
+    product_id integer NOT NULL,
+    variant_id integer,
+    count integer DEFAULT 0,
+    CONSTRAINT fk_item_carts_product FOREIGN KEY (product_id) REFERENCES products (id),
+    CONSTRAINT fk_item_carts_variant FOREIGN KEY (variant_id) REFERENCES product_variants (id),
+    CONSTRAINT fk_shopping_carts_items FOREIGN KEY (shopping_cart_id) REFERENCES shopping_carts (id)
+);
+CREATE INDEX idx_item_carts_variant_id ON item_carts (variant_id);
+CREATE INDEX idx_item_carts_deleted_at ON item_carts (deleted_at);
+
+CREATE TABLE stock_reservations (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    shopping_cart_id integer NOT NULL,
+    product_id integer NOT NULL,
+    variant_id integer,
+    quantity integer NOT NULL,
+    expires_at datetime NOT NULL
+);
+CREATE INDEX idx_stock_reservations_variant_id ON stock_reservations (variant_id);
+CREATE INDEX idx_stock_reservations_product_id ON stock_reservations (product_id);
+CREATE INDEX idx_stock_reservations_shopping_cart_id ON stock_reservations (shopping_cart_id);
+CREATE INDEX idx_stock_reservations_deleted_at ON stock_reservations (deleted_at);
+CREATE INDEX idx_stock_reservations_expires_at ON stock_reservations (expires_at);
+
+CREATE TABLE orders (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    shopping_cart_id integer NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    region text,
+    item_count integer,
+    subtotal_amount integer,
+    subtotal_currency text,
+    discount_total_amount integer,
+    discount_total_currency text,
+    tax_total_amount integer,
+    tax_total_currency text,
+    total_amount integer,
+    total_currency text,
+    prices_include_tax numeric,
+    CONSTRAINT fk_orders_shopping_cart FOREIGN KEY (shopping_cart_id) REFERENCES shopping_carts (id)
+);
+CREATE UNIQUE INDEX idx_orders_shopping_cart_id ON orders (shopping_cart_id);
+CREATE INDEX idx_orders_deleted_at ON orders (deleted_at);
+
+CREATE TABLE order_lines (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    order_id integer NOT NULL,
+    product_id integer NOT NULL,
+    variant_id integer,
+    code text,
+    sku text,
+    name text,
+    unit_price_amount integer,
+    unit_price_currency text,
+    count integer,
+    line_total_amount integer,
+    line_total_currency text,
+    discount_amount integer,
+    discount_currency text,
+    tax_rate real,
+    tax_amount integer,
+    tax_currency text,
+    CONSTRAINT fk_orders_lines FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+CREATE INDEX idx_order_lines_deleted_at ON order_lines (deleted_at);
+
+CREATE TABLE order_discounts (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    order_id integer NOT NULL,
+    promotion_id integer NOT NULL,
+    code text,
+    amount_amount integer,
+    amount_currency text,
+    CONSTRAINT fk_orders_discounts FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+CREATE INDEX idx_order_discounts_deleted_at ON order_discounts (deleted_at);
@@ -0,0 +1 @@
+DELETE FROM tax_rules WHERE region IN ('MX', 'MX-BORDER') AND tax_category IN ('standard', 'exempt');
@@ -0,0 +1,4 @@
+-- Products that are not deleted cannot share a code. Products without a code
+-- are left out, since they cannot be looked up by it. Fails if some products
+-- already share a code, which must be fixed by hand before migrating.
+CREATE UNIQUE INDEX idx_products_code ON products (code) WHERE deleted_at IS NULL AND code <> '';
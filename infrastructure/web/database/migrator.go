@@ -0,0 +1,426 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationFiles holds the migrations of every supported database, one
+// directory per dialect with files named NNNN_name.up.sql and
+// NNNN_name.down.sql.
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// schemaTable records the version of every migration applied to the database.
+const schemaTable = "schema_migrations"
+
+// lockKey identifies the advisory lock taken by the runners of the migrations
+// of a postgres database, and lockName the named lock of a mysql one.
+const (
+	lockKey  = 72616503
+	lockName = "codifin_schema_migrations"
+)
+
+// lockTimeout is how long a runner of the migrations of a mysql database
+// waits for the lock held by another runner.
+const lockTimeout = 10 * time.Minute
+
+// ErrMigrationLockTimeout is returned when another runner holds the lock of
+// the migrations for longer than the lock timeout.
+var ErrMigrationLockTimeout = fmt.Errorf("the migration lock %s could not be acquired within %s, another runner may be migrating the database", lockName, lockTimeout)
+
+// Migration is a numbered change of the schema and the statements that apply
+// and revert it.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus tells whether a migration is applied to the database and
+// when. AppliedAt is nil for pending migrations.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// schemaMigration is a row of the schema version table.
+type schemaMigration struct {
+	Version   int `gorm:"primaryKey"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return schemaTable
+}
+
+// Migrator applies and reverts the migrations of the dialect of a database.
+type Migrator struct {
+	db         *gorm.DB
+	dialect    string
+	migrations []*Migration
+}
+
+// NewMigrator creates a Migrator with the migrations embedded for the dialect
+// of the database.
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	dialect := db.Dialector.Name()
+
+	migrations, err := LoadMigrations(migrationFiles, path.Join("migrations", dialect))
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s migrations: %w", dialect, err)
+	}
+
+	if len(migrations) == 0 {
+		return nil, fmt.Errorf("there are no migrations for the %s dialect", dialect)
+	}
+
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// LoadMigrations reads the migrations of a directory ordered by version.
+// Every version must have both an up and a down file.
+func LoadMigrations(fsys fs.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		version, name, direction, err := parseMigrationName(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != name {
+			return nil, fmt.Errorf("migration %04d is named both %s and %s", version, migration.Name, name)
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// parseMigrationName splits a file name such as 0001_initial_schema.up.sql
+// into its version, name and direction.
+func parseMigrationName(fileName string) (int, string, string, error) {
+	base := strings.TrimSuffix(fileName, ".sql")
+
+	direction := path.Ext(base)
+	if direction != ".up" && direction != ".down" {
+		return 0, "", "", fmt.Errorf("migration %s is neither an up nor a down file", fileName)
+	}
+	base = strings.TrimSuffix(base, direction)
+
+	number, name, found := strings.Cut(base, "_")
+	version, err := strconv.Atoi(number)
+	if !found || name == "" || err != nil || version <= 0 {
+		return 0, "", "", fmt.Errorf("migration %s is not named NNNN_name.%s.sql", fileName, direction[1:])
+	}
+
+	return version, name, direction[1:], nil
+}
+
+// Up applies every pending migration in order.
+func (m *Migrator) Up() error {
+	return m.withLock(func(conn *gorm.DB) error {
+		// Databases created before the schema was versioned were migrated
+		// by the server with AutoMigrate.
+		legacy := !conn.Migrator().HasTable(schemaTable) && conn.Migrator().HasTable("products")
+
+		if err := m.createSchemaTable(conn); err != nil {
+			return err
+		}
+
+		if legacy {
+			if err := m.adoptLegacySchema(conn); err != nil {
+				return err
+			}
+		}
+
+		applied, err := m.appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			err = m.apply(conn, func(tx *gorm.DB) error {
+				if err := execStatements(tx, migration.Up); err != nil {
+					return err
+				}
+				return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("error applying migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Down reverts the last steps applied migrations, the most recent first.
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("the number of migrations to revert must be positive, got %d", steps)
+	}
+
+	return m.withLock(func(conn *gorm.DB) error {
+		if err := m.createSchemaTable(conn); err != nil {
+			return err
+		}
+
+		applied, err := m.appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		known := make(map[int]bool, len(m.migrations))
+		for _, migration := range m.migrations {
+			known[migration.Version] = true
+		}
+
+		for version := range applied {
+			if !known[version] {
+				return fmt.Errorf("migration %04d is applied but this binary does not know how to revert it", version)
+			}
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			err = m.apply(conn, func(tx *gorm.DB) error {
+				if err := execStatements(tx, migration.Down); err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{Version: migration.Version}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("error reverting migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			steps--
+		}
+
+		return nil
+	})
+}
+
+// Status lists every migration and whether it is applied, including the
+// applied migrations this binary does not know.
+func (m *Migrator) Status() ([]*MigrationStatus, error) {
+	applied := make(map[int]*schemaMigration)
+	if m.db.Migrator().HasTable(schemaTable) {
+		var err error
+		if applied, err = m.appliedVersions(m.db); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]*MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := &MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &record.AppliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range applied {
+		appliedAt := record.AppliedAt
+		statuses = append(statuses, &MigrationStatus{Version: record.Version, Name: record.Name, AppliedAt: &appliedAt})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// withLock runs fn on a single connection while holding the lock that keeps
+// other runners from migrating the database at the same time. Postgres takes
+// an advisory lock, MySQL a named lock, and SQLite an immediate transaction
+// that the migrations run in.
+func (m *Migrator) withLock(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		switch m.dialect {
+		case "postgres":
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+				return fmt.Errorf("error taking migration lock: %w", err)
+			}
+			defer conn.Exec("SELECT pg_advisory_unlock(?)", lockKey)
+
+			return fn(conn)
+		case "mysql":
+			// GET_LOCK returns 1 once it takes the lock, 0 if it timed out
+			// and NULL if it failed otherwise, such as when it was killed.
+			var acquired sql.NullInt64
+			err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, int(lockTimeout.Seconds())).Scan(&acquired).Error
+			if err != nil {
+				return fmt.Errorf("error taking migration lock: %w", err)
+			}
+			if !acquired.Valid || acquired.Int64 != 1 {
+				return ErrMigrationLockTimeout
+			}
+			defer conn.Exec("SELECT RELEASE_LOCK(?)", lockName)
+
+			return fn(conn)
+		case "sqlite":
+			if err := conn.Exec("PRAGMA busy_timeout = 60000").Error; err != nil {
+				return fmt.Errorf("error setting busy timeout: %w", err)
+			}
+
+			if err := conn.Exec("BEGIN IMMEDIATE").Error; err != nil {
+				return fmt.Errorf("error taking migration lock: %w", err)
+			}
+
+			// Writes must not open transactions of their own inside this one.
+			conn = conn.Session(&gorm.Session{SkipDefaultTransaction: true})
+
+			if err := fn(conn); err != nil {
+				conn.Exec("ROLLBACK")
+				return err
+			}
+
+			return conn.Exec("COMMIT").Error
+		default:
+			return fmt.Errorf("migrations can not lock the %s dialect", m.dialect)
+		}
+	})
+}
+
+// apply runs a migration step atomically. SQLite steps already run in the
+// transaction of the lock. MySQL commits every DDL statement on its own, so a
+// MySQL migration that fails halfway must be repaired by hand.
+func (m *Migrator) apply(conn *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if m.dialect == "sqlite" {
+		return fn(conn)
+	}
+
+	return conn.Transaction(fn)
+}
+
+func (m *Migrator) createSchemaTable(conn *gorm.DB) error {
+	err := conn.Exec("CREATE TABLE IF NOT EXISTS " + schemaTable + " (" +
+		"version bigint PRIMARY KEY, " +
+		"name varchar(255) NOT NULL, " +
+		"applied_at timestamp NOT NULL)").Error
+	if err != nil {
+		return fmt.Errorf("error creating schema version table: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) appliedVersions(conn *gorm.DB) (map[int]*schemaMigration, error) {
+	var records []*schemaMigration
+	if err := conn.Order("version").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("error reading schema versions: %w", err)
+	}
+
+	applied := make(map[int]*schemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
+
+// execStatements runs the statements of a migration one at a time.
+func execStatements(tx *gorm.DB, script string) error {
+	statements, err := splitStatements(script)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		if err = tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitStatements splits a migration into its statements. Statements end with
+// a semicolon at the end of a line, and lines starting with -- are comments.
+func splitStatements(script string) ([]string, error) {
+	var statements []string
+	var statement strings.Builder
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		statement.WriteString(line)
+		statement.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, statement.String())
+			statement.Reset()
+		}
+	}
+
+	if strings.TrimSpace(statement.String()) != "" {
+		return nil, errors.New("the last statement of the migration does not end with a semicolon")
+	}
+
+	return statements, nil
+}
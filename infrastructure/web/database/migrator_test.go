@@ -0,0 +1,269 @@
+package database
+
+import (
+	"codifin-challenge/domain/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// Test_MigratorUpDown tests that the migrations create the columns of every model, and that they can be reverted.
+func Test_MigratorUpDown(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("Error loading migrations: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err = migrator.Up(); err != nil {
+			t.Fatalf("Error applying migrations: %v", err)
+		}
+	}
+
+	models := []interface{}{
+		&model.TaxRule{}, &model.Category{}, &model.Product{}, &model.ProductVariant{},
+		&model.ExchangeRate{}, &model.ProductPrice{}, &model.Promotion{}, &model.ShoppingCart{},
+		&model.CartCoupon{}, &model.ItemCart{}, &model.StockReservation{}, &model.Order{},
+		&model.OrderLine{}, &model.OrderDiscount{},
+	}
+	for _, m := range models {
+		parsed, err := schema.Parse(m, &sync.Map{}, db.NamingStrategy)
+		if err != nil {
+			t.Fatalf("Error parsing model: %v", err)
+		}
+
+		for _, field := range parsed.Fields {
+			if field.DBName != "" && !field.IgnoreMigration && !db.Migrator().HasColumn(m, field.DBName) {
+				t.Errorf("Expected table %s to have column %s", parsed.Table, field.DBName)
+			}
+		}
+
+		for _, rel := range parsed.Relationships.Relations {
+			if rel.JoinTable != nil && !db.Migrator().HasTable(rel.JoinTable.Table) {
+				t.Errorf("Expected join table %s to exist", rel.JoinTable.Table)
+			}
+		}
+	}
+
+	var rules int64
+	if err = db.Model(&model.TaxRule{}).Count(&rules).Error; err != nil || rules != 4 {
+		t.Errorf("Expected the 4 seeded tax rules, got %d (%v)", rules, err)
+	}
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Error reading migration status: %v", err)
+	}
+
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			t.Errorf("Expected migration %04d_%s to be applied", status.Version, status.Name)
+		}
+	}
+
+	// Revert everything but the initial schema.
+	if err = migrator.Down(len(statuses) - 1); err != nil {
+		t.Fatalf("Error reverting migrations: %v", err)
+	}
+
+	if err = db.Model(&model.TaxRule{}).Count(&rules).Error; err != nil || rules != 0 {
+		t.Errorf("Expected the tax rules to be removed, got %d (%v)", rules, err)
+	}
+
+	if err = migrator.Down(len(statuses)); err != nil {
+		t.Fatalf("Error reverting migrations: %v", err)
+	}
+
+	if db.Migrator().HasTable(&model.Product{}) {
+		t.Errorf("Expected the products table to be dropped")
+	}
+
+	statuses, err = migrator.Status()
+	if err != nil {
+		t.Fatalf("Error reading migration status: %v", err)
+	}
+
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			t.Errorf("Expected migration %04d_%s to be pending", status.Version, status.Name)
+		}
+	}
+}
+
+// Test_MigratorConcurrentUp tests that concurrent runners apply every migration only once.
+func Test_MigratorConcurrentUp(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "codifin.db")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			migrator, err := NewMigrator(db)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			errs <- migrator.Up()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Error applying migrations concurrently: %v", err)
+		}
+	}
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+
+	var rules int64
+	if err = db.Model(&model.TaxRule{}).Count(&rules).Error; err != nil || rules != 4 {
+		t.Errorf("Expected the tax rules to be seeded once, got %d (%v)", rules, err)
+	}
+}
+
+// baselineSchema is the schema AutoMigrate created for the first release of
+// the server, when prices were floats and there were no exchange rates.
+const baselineSchema = `
+CREATE TABLE products (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    code text,
+    name text,
+    price real,
+    image_url text
+);
+CREATE INDEX idx_products_deleted_at ON products (deleted_at);
+
+CREATE TABLE shopping_carts (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime
+);
+CREATE INDEX idx_shopping_carts_deleted_at ON shopping_carts (deleted_at);
+
+CREATE TABLE item_carts (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    shopping_cart_id integer NOT NULL,
+    product_id integer NOT NULL,
+    count integer DEFAULT 0,
+    CONSTRAINT fk_item_carts_product FOREIGN KEY (product_id) REFERENCES products (id),
+    CONSTRAINT fk_shopping_carts_items FOREIGN KEY (shopping_cart_id) REFERENCES shopping_carts (id)
+);
+CREATE INDEX idx_item_carts_deleted_at ON item_carts (deleted_at);
+
+INSERT INTO products (created_at, updated_at, code, name, price) VALUES
+    (CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, 'A1', 'Taza', 19.999),
+    (CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, 'B2', 'Plato', 10.5),
+    (CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, 'C3', 'Vaso', NULL);
+INSERT INTO shopping_carts (created_at, updated_at) VALUES (CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+INSERT INTO item_carts (created_at, updated_at, shopping_cart_id, product_id, count) VALUES
+    (CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, 1, 1, 3);
+`
+
+// Test_MigratorUpBaseline tests that a database of the first release is upgraded in place, converting the prices to
+// money in the default currency.
+func Test_MigratorUpBaseline(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+
+	if err = execStatements(db, baselineSchema); err != nil {
+		t.Fatalf("Error creating baseline schema: %v", err)
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("Error loading migrations: %v", err)
+	}
+
+	if err = migrator.Up(); err != nil {
+		t.Fatalf("Error upgrading baseline database: %v", err)
+	}
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Error reading migration status: %v", err)
+	}
+
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			t.Errorf("Expected migration %04d_%s to be applied", status.Version, status.Name)
+		}
+	}
+
+	if db.Migrator().HasColumn("products", "price") {
+		t.Errorf("Expected the float price column to be dropped")
+	}
+
+	if !db.Migrator().HasTable(&model.ExchangeRate{}) {
+		t.Errorf("Expected the exchange rates table to be created")
+	}
+
+	if !db.Migrator().HasColumn(&model.ItemCart{}, "variant_id") {
+		t.Errorf("Expected the items of the carts to have a variant")
+	}
+
+	var products []*model.Product
+	if err = db.Order("id").Find(&products).Error; err != nil {
+		t.Fatalf("Error reading products: %v", err)
+	}
+
+	expected := []model.Money{
+		{Amount: 2000, Currency: model.DefaultCurrency},
+		{Amount: 1050, Currency: model.DefaultCurrency},
+		{},
+	}
+	if len(products) != len(expected) {
+		t.Fatalf("Expected %d products, got %d", len(expected), len(products))
+	}
+
+	for i, product := range products {
+		if product.Price != expected[i] {
+			t.Errorf("Expected product %s to cost %v, got %v", product.Code, expected[i], product.Price)
+		}
+
+		if product.Version != 1 || product.TaxCategory != model.TaxStandard {
+			t.Errorf("Expected product %s to get the defaults of the new columns, got %+v", product.Code, product)
+		}
+	}
+
+	var items []*model.ItemCart
+	if err = db.Find(&items).Error; err != nil || len(items) != 1 || items[0].Count != 3 {
+		t.Errorf("Expected the items of the carts to be kept, got %d (%v)", len(items), err)
+	}
+
+	var rules int64
+	if err = db.Model(&model.TaxRule{}).Count(&rules).Error; err != nil || rules != 4 {
+		t.Errorf("Expected the 4 seeded tax rules, got %d (%v)", rules, err)
+	}
+}
@@ -1,32 +1,230 @@
 package database
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"log"
+	"net"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 )
 
+// Supported database drivers.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMySQL    = "mysql"
+)
+
+// mysqlTLSConfig is the name the TLS settings of MySQL connections are
+// registered with.
+const mysqlTLSConfig = "codifin"
+
 var db *gorm.DB
 
-func NewDataBase(host, port, user, password, name string, retries int) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		host, port, user, password, name)
+// Settings describes how to connect to the database. Name is the file of the
+// database for SQLite, which ignores the network and SSL settings.
+//
+// SSLMode takes the Postgres sslmode values: disable, allow, prefer, require,
+// verify-ca and verify-full. MySQL treats allow and prefer as preferred, and
+// verify-ca as verify-full.
+type Settings struct {
+	Driver      string
+	Host        string
+	Port        string
+	User        string
+	Password    string
+	Name        string
+	SSLMode     string
+	SSLRootCert string
+	SSLCert     string
+	SSLKey      string
+	Retries     int
+}
+
+func NewDataBase(settings Settings) (*gorm.DB, error) {
+	dialector, err := NewDialector(settings)
+	if err != nil {
+		return nil, err
+	}
 
-	var err error
-	for i := 0; i < retries; i++ {
-		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
+	for i := 0; i < settings.Retries; i++ {
+		db, err = gorm.Open(dialector, &gorm.Config{TranslateError: true})
 		if err == nil {
 			db = db.Debug()
 
-			log.Printf("connection with database on %s:%s was stablished, enjoy it!...", host, port)
+			log.Printf("connection with %s database %s was stablished, enjoy it!...", settings.Driver, settings.address())
 			return db, nil
 		}
 
-		log.Printf("%d remaining attempts to connect with the database: %s\n", retries-i-1, err.Error())
+		log.Printf("%d remaining attempts to connect with the database: %s\n", settings.Retries-i-1, err.Error())
 		time.Sleep(time.Second * 3)
 	}
 
-	return nil, fmt.Errorf("failed to establish connection to database after %d attempts", retries)
+	return nil, fmt.Errorf("failed to establish connection to database after %d attempts", settings.Retries)
+}
+
+// NewDialector returns the gorm dialector of the driver of the settings.
+func NewDialector(settings Settings) (gorm.Dialector, error) {
+	dsn, err := BuildDSN(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	switch settings.Driver {
+	case DriverPostgres:
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		return sqlite.Open(dsn), nil
+	case DriverMySQL:
+		return mysql.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", settings.Driver)
+	}
+}
+
+// BuildDSN returns the data source name of the driver of the settings.
+func BuildDSN(settings Settings) (string, error) {
+	switch settings.Driver {
+	case DriverPostgres:
+		return postgresDSN(settings), nil
+	case DriverSQLite:
+		return sqliteDSN(settings), nil
+	case DriverMySQL:
+		return mysqlDSN(settings)
+	default:
+		return "", fmt.Errorf("unsupported database driver %q", settings.Driver)
+	}
+}
+
+func (s Settings) address() string {
+	if s.Driver == DriverSQLite {
+		return s.Name
+	}
+
+	return net.JoinHostPort(s.Host, s.Port)
+}
+
+func (s Settings) sslMode() string {
+	if s.SSLMode == "" {
+		return "disable"
+	}
+
+	return s.SSLMode
+}
+
+func postgresDSN(s Settings) string {
+	params := []string{
+		"host=" + quoteDSNValue(s.Host),
+		"port=" + quoteDSNValue(s.Port),
+		"user=" + quoteDSNValue(s.User),
+		"password=" + quoteDSNValue(s.Password),
+		"dbname=" + quoteDSNValue(s.Name),
+		"sslmode=" + quoteDSNValue(s.sslMode()),
+	}
+
+	if s.SSLRootCert != "" {
+		params = append(params, "sslrootcert="+quoteDSNValue(s.SSLRootCert))
+	}
+
+	if s.SSLCert != "" {
+		params = append(params, "sslcert="+quoteDSNValue(s.SSLCert))
+	}
+
+	if s.SSLKey != "" {
+		params = append(params, "sslkey="+quoteDSNValue(s.SSLKey))
+	}
+
+	return strings.Join(params, " ")
+}
+
+// quoteDSNValue quotes a value of a Postgres keyword/value connection string
+// when it is empty or has spaces, quotes or backslashes.
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " '\\") {
+		return value
+	}
+
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+// sqliteDSN opens the database file with foreign keys enforced, and waits for
+// the locks of other connections instead of failing right away.
+func sqliteDSN(s Settings) string {
+	params := url.Values{}
+	params.Set("_foreign_keys", "1")
+	params.Set("_busy_timeout", "5000")
+
+	return "file:" + s.Name + "?" + params.Encode()
+}
+
+func mysqlDSN(s Settings) (string, error) {
+	cfg := mysqldriver.NewConfig()
+	cfg.User = s.User
+	cfg.Passwd = s.Password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(s.Host, s.Port)
+	cfg.DBName = s.Name
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+	cfg.Params = map[string]string{"charset": "utf8mb4"}
+
+	switch s.sslMode() {
+	case "disable":
+		cfg.TLSConfig = "false"
+	case "allow", "prefer":
+		cfg.TLSConfig = "preferred"
+	case "require", "verify-ca", "verify-full":
+		tlsConfig, err := mysqlTLS(s)
+		if err != nil {
+			return "", err
+		}
+
+		if err = mysqldriver.RegisterTLSConfig(mysqlTLSConfig, tlsConfig); err != nil {
+			return "", fmt.Errorf("error registering mysql TLS config: %w", err)
+		}
+		cfg.TLSConfig = mysqlTLSConfig
+	default:
+		return "", fmt.Errorf("unsupported SSL mode %q", s.SSLMode)
+	}
+
+	return cfg.FormatDSN(), nil
+}
+
+// mysqlTLS builds the TLS settings of MySQL connections. The require mode
+// encrypts the connection without checking the certificate of the server.
+func mysqlTLS(s Settings) (*tls.Config, error) {
+	tlsConfig := &tls.Config{ServerName: s.Host, InsecureSkipVerify: s.sslMode() == "require"}
+
+	if s.SSLRootCert != "" {
+		pem, err := os.ReadFile(s.SSLRootCert)
+		if err != nil {
+			return nil, fmt.Errorf("error reading SSL root certificate: %w", err)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("SSL root certificate %s has no certificates", s.SSLRootCert)
+		}
+	}
+
+	if s.SSLCert != "" || s.SSLKey != "" {
+		cert, err := tls.LoadX509KeyPair(s.SSLCert, s.SSLKey)
+		if err != nil {
+			return nil, fmt.Errorf("error loading SSL client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
 }
@@ -0,0 +1,43 @@
+package database
+
+import "testing"
+
+// Test_BuildDSN tests the data source names built for every driver.
+func Test_BuildDSN(t *testing.T) {
+	cases := []struct {
+		settings Settings
+		expected string
+	}{
+		{
+			settings: Settings{Driver: DriverPostgres, Host: "localhost", Port: "5432", User: "tester", Password: "super secret", Name: "products"},
+			expected: "host=localhost port=5432 user=tester password='super secret' dbname=products sslmode=disable",
+		},
+		{
+			settings: Settings{Driver: DriverPostgres, Host: "db", Port: "5432", User: "tester", Password: "x", Name: "products", SSLMode: "verify-full", SSLRootCert: "/certs/ca.pem"},
+			expected: "host=db port=5432 user=tester password=x dbname=products sslmode=verify-full sslrootcert=/certs/ca.pem",
+		},
+		{
+			settings: Settings{Driver: DriverSQLite, Name: "codifin.db"},
+			expected: "file:codifin.db?_busy_timeout=5000&_foreign_keys=1",
+		},
+		{
+			settings: Settings{Driver: DriverMySQL, Host: "localhost", Port: "3306", User: "tester", Password: "x", Name: "products"},
+			expected: "tester:x@tcp(localhost:3306)/products?parseTime=true&tls=false&charset=utf8mb4",
+		},
+	}
+
+	for _, c := range cases {
+		dsn, err := BuildDSN(c.settings)
+		if err != nil {
+			t.Fatalf("Error building %s DSN: %v", c.settings.Driver, err)
+		}
+
+		if dsn != c.expected {
+			t.Errorf("Expected the %s DSN %s, got %s", c.settings.Driver, c.expected, dsn)
+		}
+	}
+
+	if _, err := BuildDSN(Settings{Driver: "oracle"}); err == nil {
+		t.Errorf("Expected an unsupported driver to fail")
+	}
+}
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"codifin-challenge/domain/model"
+	"strings"
+)
+
+type CategoryData struct {
+	Name     string `json:"name" binding:"required,max=255"`
+	ParentID *uint  `json:"parentID" binding:"omitempty,gt=0"`
+}
+
+type CategoryDTO struct {
+	ID uint `json:"id"`
+	CategoryData
+}
+
+type CategoryTreeDTO struct {
+	CategoryDTO
+	Children []*CategoryTreeDTO `json:"children"`
+}
+
+func (c *CategoryData) ToCategory() *model.Category {
+	return &model.Category{
+		Name:     strings.TrimSpace(c.Name),
+		ParentID: c.ParentID,
+	}
+}
+
+func ToCategoryDTO(category *model.Category) *CategoryDTO {
+	if category != nil {
+		return &CategoryDTO{
+			ID: category.ID,
+			CategoryData: CategoryData{
+				Name:     category.Name,
+				ParentID: category.ParentID,
+			},
+		}
+	}
+	return nil
+}
+
+func ToCategoriesDTO(categories []*model.Category) []*CategoryDTO {
+	categoriesDTO := make([]*CategoryDTO, 0)
+	for _, v := range categories {
+		categoriesDTO = append(categoriesDTO, ToCategoryDTO(v))
+	}
+	return categoriesDTO
+}
+
+func ToCategoryTreeDTO(category *model.Category) *CategoryTreeDTO {
+	return &CategoryTreeDTO{
+		CategoryDTO: *ToCategoryDTO(category),
+		Children:    ToCategoriesTreeDTO(category.Children),
+	}
+}
+
+func ToCategoriesTreeDTO(categories []*model.Category) []*CategoryTreeDTO {
+	treeDTO := make([]*CategoryTreeDTO, 0)
+	for _, v := range categories {
+		treeDTO = append(treeDTO, ToCategoryTreeDTO(v))
+	}
+	return treeDTO
+}
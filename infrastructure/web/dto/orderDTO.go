@@ -0,0 +1,110 @@
+package dto
+
+import (
+	"codifin-challenge/domain/model"
+	"time"
+)
+
+type OrderDTO struct {
+	ID               uint            `json:"id"`
+	CartID           uint            `json:"cartID"`
+	Status           string          `json:"status"`
+	Region           string          `json:"region"`
+	Lines            []*OrderLineDTO `json:"lines"`
+	Discounts        []*DiscountDTO  `json:"discounts"`
+	ItemCount        uint            `json:"itemCount"`
+	Subtotal         model.Money     `json:"subtotal"`
+	Discount         model.Money     `json:"discount"`
+	Tax              model.Money     `json:"tax"`
+	Total            model.Money     `json:"total"`
+	PricesIncludeTax bool            `json:"pricesIncludeTax"`
+	CreatedAt        time.Time       `json:"createdAt"`
+	UpdatedAt        time.Time       `json:"updatedAt"`
+}
+
+type OrderLineDTO struct {
+	ProductID uint        `json:"productID"`
+	VariantID *uint       `json:"variantID"`
+	Code      string      `json:"code"`
+	SKU       string      `json:"sku"`
+	Name      string      `json:"name"`
+	UnitPrice model.Money `json:"unitPrice"`
+	Count     uint        `json:"count"`
+	LineTotal model.Money `json:"lineTotal"`
+	Discount  model.Money `json:"discount"`
+	TaxRate   float64     `json:"taxRate"`
+	Tax       model.Money `json:"tax"`
+}
+
+func ToOrderDTO(order *model.Order) *OrderDTO {
+	if order != nil {
+		orderDTO := &OrderDTO{
+			ID:               order.ID,
+			CartID:           order.ShoppingCartID,
+			Status:           string(order.Status),
+			Region:           order.Region,
+			Lines:            ToOrderLinesDTO(order.Lines, order.PricesIncludeTax),
+			Discounts:        ToOrderDiscountsDTO(order.Discounts),
+			ItemCount:        order.ItemCount,
+			Subtotal:         order.Subtotal,
+			Discount:         order.DiscountTotal,
+			Tax:              order.TaxTotal,
+			Total:            order.Total,
+			PricesIncludeTax: order.PricesIncludeTax,
+			CreatedAt:        order.CreatedAt,
+			UpdatedAt:        order.UpdatedAt,
+		}
+
+		if order.PricesIncludeTax {
+			orderDTO.Subtotal = model.NewMoney(0, order.Subtotal.Currency)
+			for _, v := range orderDTO.Lines {
+				orderDTO.Subtotal = orderDTO.Subtotal.Add(v.LineTotal)
+			}
+			orderDTO.Discount = orderDTO.Subtotal.Sub(orderDTO.Total)
+		}
+
+		return orderDTO
+	}
+
+	return nil
+}
+
+func ToOrderLineDTO(line *model.OrderLine, pricesIncludeTax bool) *OrderLineDTO {
+	lineDTO := &OrderLineDTO{
+		ProductID: line.ProductID,
+		VariantID: line.VariantID,
+		Code:      line.Code,
+		SKU:       line.SKU,
+		Name:      line.Name,
+		UnitPrice: line.UnitPrice,
+		Count:     line.Count,
+		LineTotal: line.LineTotal,
+		Discount:  line.Discount,
+		TaxRate:   line.TaxRate,
+		Tax:       line.Tax,
+	}
+
+	if pricesIncludeTax {
+		lineDTO.UnitPrice = line.UnitPrice.Gross(line.TaxRate)
+		lineDTO.LineTotal = line.LineTotal.Gross(line.TaxRate)
+		lineDTO.Discount = line.Discount.Gross(line.TaxRate)
+	}
+
+	return lineDTO
+}
+
+func ToOrderLinesDTO(lines []*model.OrderLine, pricesIncludeTax bool) []*OrderLineDTO {
+	linesDTO := make([]*OrderLineDTO, 0)
+	for _, v := range lines {
+		linesDTO = append(linesDTO, ToOrderLineDTO(v, pricesIncludeTax))
+	}
+	return linesDTO
+}
+
+func ToOrderDiscountsDTO(discounts []*model.OrderDiscount) []*DiscountDTO {
+	discountsDTO := make([]*DiscountDTO, 0)
+	for _, v := range discounts {
+		discountsDTO = append(discountsDTO, &DiscountDTO{Code: v.Code, Amount: v.Amount})
+	}
+	return discountsDTO
+}
@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"codifin-challenge/domain/model"
+	"strings"
+	"time"
+)
+
+type ExchangeRateData struct {
+	Rate float64 `json:"rate" binding:"gt=0" example:"0.058"`
+}
+
+type ExchangeRateDTO struct {
+	Currency string `json:"currency"`
+	ExchangeRateData
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ProductPriceData struct {
+	Price model.Money `json:"price" binding:"gte=0"`
+}
+
+type ProductPriceDTO struct {
+	ProductID uint `json:"productID"`
+	ProductPriceData
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (e *ExchangeRateData) ToExchangeRate(currency string) *model.ExchangeRate {
+	return &model.ExchangeRate{
+		Currency: strings.TrimSpace(strings.ToUpper(currency)),
+		Rate:     e.Rate,
+	}
+}
+
+func ToExchangeRateDTO(rate *model.ExchangeRate) *ExchangeRateDTO {
+	if rate != nil {
+		return &ExchangeRateDTO{
+			Currency:         rate.Currency,
+			ExchangeRateData: ExchangeRateData{Rate: rate.Rate},
+			UpdatedAt:        rate.UpdatedAt,
+		}
+	}
+	return nil
+}
+
+func ToExchangeRatesDTO(rates []*model.ExchangeRate) []*ExchangeRateDTO {
+	ratesDTO := make([]*ExchangeRateDTO, 0)
+	for _, v := range rates {
+		ratesDTO = append(ratesDTO, ToExchangeRateDTO(v))
+	}
+	return ratesDTO
+}
+
+func (p *ProductPriceData) ToProductPrice(productID uint) *model.ProductPrice {
+	return &model.ProductPrice{
+		ProductID: productID,
+		Currency:  p.Price.OrDefaultCurrency().Currency,
+		Amount:    p.Price.Amount,
+	}
+}
+
+func ToProductPriceDTO(price *model.ProductPrice) *ProductPriceDTO {
+	if price != nil {
+		return &ProductPriceDTO{
+			ProductID:        price.ProductID,
+			ProductPriceData: ProductPriceData{Price: price.Price()},
+			UpdatedAt:        price.UpdatedAt,
+		}
+	}
+	return nil
+}
+
+func ToProductPricesDTO(prices []*model.ProductPrice) []*ProductPriceDTO {
+	pricesDTO := make([]*ProductPriceDTO, 0)
+	for _, v := range prices {
+		pricesDTO = append(pricesDTO, ToProductPriceDTO(v))
+	}
+	return pricesDTO
+}
@@ -2,45 +2,103 @@ package dto
 
 import (
 	"codifin-challenge/domain/model"
+	"gorm.io/gorm"
 	"strings"
+	"time"
 )
 
 type ProductsListResp struct {
-	Total    uint          `json:"total"`
-	Products []*ProductDTO `json:"products"`
+	Total      uint              `json:"total"`
+	Products   []*ProductDTO     `json:"products"`
+	Facets     *ProductFacetsDTO `json:"facets"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+	PrevCursor string            `json:"prevCursor,omitempty"`
+}
+
+type DeletedProductsListResp struct {
+	Total    uint                 `json:"total"`
+	Products []*DeletedProductDTO `json:"products"`
+}
+
+type DeletedProductDTO struct {
+	*ProductDTO
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+type PurgeResp struct {
+	Purged int64 `json:"purged"`
 }
 
 type ProductDTO struct {
 	ID uint `json:"id"`
 	ProductData
+	Available  uint           `json:"available"`
+	Score      float64        `json:"score,omitempty"`
+	Categories []*CategoryDTO `json:"categories"`
+	Variants   []*VariantDTO  `json:"variants"`
 }
 
 type ProductData struct {
-	Code     string  `json:"code"`
-	Name     string  `json:"name"`
-	Price    float64 `json:"price"`
-	ImageURL string  `json:"imageURL"`
+	Code        string      `json:"code" binding:"max=64"`
+	Name        string      `json:"name" binding:"required,max=255"`
+	Description string      `json:"description" binding:"max=5000"`
+	Price       model.Money `json:"price" binding:"gte=0"`
+	ImageURL    string      `json:"imageURL" binding:"omitempty,url,max=2048"`
+	TaxCategory string      `json:"taxCategory" binding:"max=64"`
+	Stock       uint        `json:"stock"`
+	CategoryIDs []uint      `json:"categoryIDs" binding:"dive,gt=0"`
+	Status      string      `json:"status" binding:"omitempty,oneof=draft active archived"`
+	PublishAt   *time.Time  `json:"publishAt"`
+	UnpublishAt *time.Time  `json:"unpublishAt"`
 }
 
 func (p *ProductData) ToProduct() *model.Product {
+	categories := make([]*model.Category, 0, len(p.CategoryIDs))
+	for _, id := range p.CategoryIDs {
+		categories = append(categories, &model.Category{Model: gorm.Model{ID: id}})
+	}
+
 	return &model.Product{
-		Code:     strings.TrimSpace(p.Code),
-		Name:     strings.TrimSpace(strings.ToUpper(p.Name)),
-		Price:    p.Price,
-		ImageURL: strings.TrimSpace(p.ImageURL),
+		Code:        strings.TrimSpace(p.Code),
+		Name:        strings.TrimSpace(strings.ToUpper(p.Name)),
+		Description: strings.TrimSpace(p.Description),
+		Price:       p.Price,
+		ImageURL:    strings.TrimSpace(p.ImageURL),
+		TaxCategory: strings.TrimSpace(strings.ToLower(p.TaxCategory)),
+		Stock:       p.Stock,
+		Categories:  categories,
+		Status:      model.ProductStatus(strings.TrimSpace(strings.ToLower(p.Status))),
+		PublishAt:   p.PublishAt,
+		UnpublishAt: p.UnpublishAt,
 	}
 }
 
 func ToProductDTO(product *model.Product) *ProductDTO {
 	if product != nil {
+		categoryIDs := make([]uint, 0, len(product.Categories))
+		for _, v := range product.Categories {
+			categoryIDs = append(categoryIDs, v.ID)
+		}
+
 		return &ProductDTO{
 			ID: product.ID,
 			ProductData: ProductData{
-				Code:     product.Code,
-				Name:     product.Name,
-				Price:    product.Price,
-				ImageURL: product.ImageURL,
+				Code:        product.Code,
+				Name:        product.Name,
+				Description: product.Description,
+				Price:       product.Price,
+				ImageURL:    product.ImageURL,
+				TaxCategory: product.TaxCategory,
+				Stock:       product.Stock,
+				CategoryIDs: categoryIDs,
+				Status:      string(product.Status),
+				PublishAt:   product.PublishAt,
+				UnpublishAt: product.UnpublishAt,
 			},
+			Available:  product.Available(),
+			Score:      product.Score,
+			Categories: ToCategoriesDTO(product.Categories),
+			Variants:   ToVariantsDTO(product.Variants),
 		}
 	}
 	return nil
@@ -54,3 +112,30 @@ func ToProductsDTO(products []*model.Product) []*ProductDTO {
 
 	return productsDTO
 }
+
+func ToProductsListResp(list *model.ProductList) *ProductsListResp {
+	resp := &ProductsListResp{
+		Total:    list.Total,
+		Products: ToProductsDTO(list.Products),
+		Facets:   ToProductFacetsDTO(list.Facets),
+	}
+
+	if list.NextCursor != nil {
+		resp.NextCursor = list.NextCursor.Encode()
+	}
+
+	if list.PrevCursor != nil {
+		resp.PrevCursor = list.PrevCursor.Encode()
+	}
+
+	return resp
+}
+
+func ToDeletedProductsListResp(products []*model.Product, total uint) *DeletedProductsListResp {
+	productsDTO := make([]*DeletedProductDTO, 0, len(products))
+	for _, v := range products {
+		productsDTO = append(productsDTO, &DeletedProductDTO{ProductDTO: ToProductDTO(v), DeletedAt: v.DeletedAt.Time})
+	}
+
+	return &DeletedProductsListResp{Total: total, Products: productsDTO}
+}
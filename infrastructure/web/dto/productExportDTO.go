@@ -0,0 +1,253 @@
+package dto
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"codifin-challenge/infrastructure/web/xlsx"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportColumns are the columns an export may have. Those that are not
+// fields of ProductData are skipped by imports, so exports can be imported.
+var ExportColumns = []string{
+	"id", "code", "name", "description", "price", "currency", "imageURL",
+	"taxCategory", "stock", "available", "categoryIDs", "status", "publishAt", "unpublishAt",
+	"createdAt", "updatedAt",
+}
+
+// DefaultExportColumns are the columns of an export that does not choose
+// them: the ones an import reads.
+var DefaultExportColumns = []string{
+	"code", "name", "description", "price", "currency", "imageURL", "taxCategory", "stock", "categoryIDs",
+	"status", "publishAt", "unpublishAt",
+}
+
+// ExportFormats maps the formats of an export to their content type and file
+// extension.
+var ExportFormats = map[string]struct {
+	ContentType string
+	Extension   string
+}{
+	"csv":    {"text/csv; charset=utf-8", "csv"},
+	"ndjson": {"application/x-ndjson", "ndjson"},
+	"xlsx":   {"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xlsx"},
+}
+
+type ProductWriter interface {
+	Write(products []*model.Product) error
+	Close() error
+}
+
+func ParseExportColumns(spec string) ([]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		return DefaultExportColumns, nil
+	}
+
+	columns := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, v := range strings.Split(spec, ",") {
+		column := strings.TrimSpace(v)
+		if !isExportColumn(column) || seen[column] {
+			return nil, utils.NewError(utils.ErrInvalidExportColumn, fmt.Errorf("invalid or repeated export column %q", column),
+				column, strings.Join(ExportColumns, ", "))
+		}
+		seen[column] = true
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
+
+func isExportColumn(column string) bool {
+	for _, v := range ExportColumns {
+		if v == column {
+			return true
+		}
+	}
+	return false
+}
+
+func NewProductWriter(format string, w io.Writer, columns []string) (ProductWriter, error) {
+	switch format {
+	case "csv":
+		writer := csv.NewWriter(w)
+		if err := writer.Write(columns); err != nil {
+			return nil, err
+		}
+		return &csvProductWriter{writer: writer, columns: columns}, nil
+	case "ndjson":
+		return &ndjsonProductWriter{encoder: json.NewEncoder(w), columns: columns}, nil
+	case "xlsx":
+		writer, err := xlsx.NewWriter(w, "Productos")
+		if err != nil {
+			return nil, err
+		}
+
+		header := make([]interface{}, 0, len(columns))
+		for _, v := range columns {
+			header = append(header, v)
+		}
+		if err = writer.WriteRow(header); err != nil {
+			return nil, err
+		}
+		return &xlsxProductWriter{writer: writer, columns: columns}, nil
+	}
+
+	return nil, utils.NewError(utils.ErrUnsupportedExportFormat, fmt.Errorf("unsupported export format %q", format), format)
+}
+
+// exportValue returns the value of a column of a product.
+func exportValue(p *model.Product, column string) interface{} {
+	switch column {
+	case "id":
+		return p.ID
+	case "code":
+		return p.Code
+	case "name":
+		return p.Name
+	case "description":
+		return p.Description
+	case "price":
+		return p.Price.OrDefaultCurrency()
+	case "currency":
+		return p.Price.OrDefaultCurrency().Currency
+	case "imageURL":
+		return p.ImageURL
+	case "taxCategory":
+		return p.TaxCategory
+	case "stock":
+		return p.Stock
+	case "available":
+		return p.Available()
+	case "categoryIDs":
+		ids := make([]uint, 0, len(p.Categories))
+		for _, v := range p.Categories {
+			ids = append(ids, v.ID)
+		}
+		return ids
+	case "status":
+		return string(p.Status)
+	case "publishAt":
+		return optionalTime(p.PublishAt)
+	case "unpublishAt":
+		return optionalTime(p.UnpublishAt)
+	case "createdAt":
+		return p.CreatedAt
+	case "updatedAt":
+		return p.UpdatedAt
+	}
+	return nil
+}
+
+// optionalTime returns the time t points to, or nil without one.
+func optionalTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+// exportText returns a value of a column as the text of a CSV cell, with the
+// amount of prices and category IDs separated by |, as imports read them.
+// Missing values are empty.
+func exportText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case model.Money:
+		return v.String()
+	case []uint:
+		ids := make([]string, 0, len(v))
+		for _, id := range v {
+			ids = append(ids, strconv.FormatUint(uint64(id), 10))
+		}
+		return strings.Join(ids, "|")
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
+
+type csvProductWriter struct {
+	writer  *csv.Writer
+	columns []string
+}
+
+func (w *csvProductWriter) Write(products []*model.Product) error {
+	record := make([]string, len(w.columns))
+	for _, p := range products {
+		for i, column := range w.columns {
+			record[i] = exportText(exportValue(p, column))
+		}
+		if err := w.writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvProductWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type ndjsonProductWriter struct {
+	encoder *json.Encoder
+	columns []string
+}
+
+func (w *ndjsonProductWriter) Write(products []*model.Product) error {
+	for _, p := range products {
+		object := make(map[string]interface{}, len(w.columns))
+		for _, column := range w.columns {
+			object[column] = exportValue(p, column)
+		}
+		if err := w.encoder.Encode(object); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *ndjsonProductWriter) Close() error {
+	return nil
+}
+
+type xlsxProductWriter struct {
+	writer  *xlsx.Writer
+	columns []string
+}
+
+func (w *xlsxProductWriter) Write(products []*model.Product) error {
+	row := make([]interface{}, len(w.columns))
+	for _, p := range products {
+		for i, column := range w.columns {
+			switch value := exportValue(p, column).(type) {
+			case model.Money:
+				row[i], _ = strconv.ParseFloat(value.String(), 64)
+			case []uint:
+				row[i] = exportText(value)
+			default:
+				row[i] = value
+			}
+		}
+		if err := w.writer.WriteRow(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *xlsxProductWriter) Close() error {
+	return w.writer.Close()
+}
@@ -0,0 +1,290 @@
+package dto
+
+import (
+	"bytes"
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"errors"
+	"gorm.io/gorm"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// exportedProducts returns products as the catalog stores them, with
+// variants, attributes and prices in several currencies, and text that needs
+// quoting in a CSV file.
+func exportedProducts() []*model.Product {
+	publishAt := time.Date(2026, time.March, 1, 9, 30, 0, 0, time.UTC)
+	unpublishAt := time.Date(2026, time.December, 31, 23, 59, 59, 500, time.FixedZone("CST", -6*3600))
+	variantPrice := model.NewMoney(1450, "USD")
+
+	return []*model.Product{
+		{
+			Model:       gorm.Model{ID: 7, CreatedAt: publishAt, UpdatedAt: publishAt},
+			Code:        "CAM-001",
+			Name:        "CAMISA \"OXFORD\", MANGA LARGA",
+			Description: "Algodón, lavar en frío",
+			Price:       model.NewMoney(1230, "USD"),
+			ImageURL:    "https://example.com/camisa.png",
+			TaxCategory: model.TaxStandard,
+			Stock:       12,
+			Reserved:    2,
+			Categories:  []*model.Category{{Model: gorm.Model{ID: 3}}, {Model: gorm.Model{ID: 4}}},
+			Status:      model.ProductActive,
+			PublishAt:   &publishAt,
+			UnpublishAt: &unpublishAt,
+			Variants: []*model.ProductVariant{
+				{SKU: "CAM-001-M", Stock: 5, Attributes: model.Attributes{"size": "M", "color": "blue"}},
+				{SKU: "CAM-001-L", Stock: 7, Price: &variantPrice, Attributes: model.Attributes{"size": "L"}},
+			},
+		},
+		{
+			Model:       gorm.Model{ID: 8},
+			Code:        "TAZ-002",
+			Name:        "TAZA",
+			Description: "Cerámica\nmicroondas",
+			Price:       model.NewMoney(1500, "JPY"),
+			TaxCategory: model.TaxExempt,
+			Categories:  []*model.Category{},
+			Status:      model.ProductDraft,
+		},
+		{
+			Model:       gorm.Model{ID: 9},
+			Code:        "VAS-003",
+			Name:        "VASO",
+			Price:       model.NewMoney(999, model.DefaultCurrency),
+			TaxCategory: model.TaxStandard,
+			Categories:  []*model.Category{{Model: gorm.Model{ID: 5}}},
+			Status:      model.ProductArchived,
+		},
+	}
+}
+
+func readRows(t *testing.T, source interface {
+	Next() (*model.ImportRow, error)
+}) []*model.ImportRow {
+	t.Helper()
+
+	rows := make([]*model.ImportRow, 0)
+	for {
+		row, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return rows
+		}
+		if err != nil {
+			t.Fatalf("Error reading import: %v", err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+func categoryIDs(p *model.Product) []uint {
+	ids := make([]uint, 0, len(p.Categories))
+	for _, v := range p.Categories {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// Test_ExportImportRoundTrip tests that the products an export writes are read back by an import with the same fields,
+// whatever the columns of the export. Variants are managed by their own endpoints and are not part of the file.
+func Test_ExportImportRoundTrip(t *testing.T) {
+	cases := []struct {
+		format  string
+		columns []string
+	}{
+		{"csv", DefaultExportColumns},
+		{"csv", ExportColumns},
+		{"ndjson", DefaultExportColumns},
+		{"ndjson", ExportColumns},
+	}
+
+	for _, v := range cases {
+		products := exportedProducts()
+
+		var file bytes.Buffer
+		writer, err := NewProductWriter(v.format, &file, v.columns)
+		if err != nil {
+			t.Fatalf("Error creating %s writer: %v", v.format, err)
+		}
+
+		if err = writer.Write(products[:2]); err != nil {
+			t.Fatalf("Error writing %s export: %v", v.format, err)
+		}
+		if err = writer.Write(products[2:]); err != nil {
+			t.Fatalf("Error writing %s export: %v", v.format, err)
+		}
+		if err = writer.Close(); err != nil {
+			t.Fatalf("Error closing %s export: %v", v.format, err)
+		}
+
+		var rows []*model.ImportRow
+		var lines []int
+		if v.format == "csv" {
+			source, err := NewCSVProductSource(&file)
+			if err != nil {
+				t.Fatalf("Error reading CSV header: %v", err)
+			}
+			rows = readRows(t, source)
+			// The description of the second product spans two lines.
+			lines = []int{2, 3, 5}
+		} else {
+			rows = readRows(t, NewNDJSONProductSource(&file))
+			lines = []int{1, 2, 3}
+		}
+
+		if len(rows) != len(products) {
+			t.Fatalf("Expected %d rows in the %s export, got %d", len(products), v.format, len(rows))
+		}
+
+		for i, row := range rows {
+			expected := products[i]
+			got := row.Product
+
+			if row.Err != nil || got == nil {
+				t.Errorf("Expected %s row %d to be read, got %v", v.format, i, row.Err)
+				continue
+			}
+
+			if row.Line != lines[i] {
+				t.Errorf("Expected %s row %d to be reported at line %d, got %d", v.format, i, lines[i], row.Line)
+			}
+
+			if got.Code != expected.Code || got.Name != expected.Name || got.Description != expected.Description ||
+				got.Price != expected.Price || got.ImageURL != expected.ImageURL || got.TaxCategory != expected.TaxCategory ||
+				got.Stock != expected.Stock || got.Status != expected.Status {
+				t.Errorf("Expected %s row %d to read %+v, got %+v", v.format, i, expected, got)
+			}
+
+			if !reflect.DeepEqual(categoryIDs(got), categoryIDs(expected)) {
+				t.Errorf("Expected %s row %d to have categories %v, got %v", v.format, i, categoryIDs(expected), categoryIDs(got))
+			}
+
+			if !sameTime(got.PublishAt, expected.PublishAt) || !sameTime(got.UnpublishAt, expected.UnpublishAt) {
+				t.Errorf("Expected %s row %d to be published from %v to %v, got %v to %v", v.format, i,
+					expected.PublishAt, expected.UnpublishAt, got.PublishAt, got.UnpublishAt)
+			}
+
+			for _, field := range v.columns {
+				read := importFields[field]
+				if v.format == "csv" {
+					// Empty cells are left out of the rows of CSV files.
+					read = read && exportText(exportValue(expected, field)) != ""
+				}
+
+				if row.Fields[field] != read {
+					t.Errorf("Expected %s row %d to read the column %s %v, got %v", v.format, i, field, read, row.Fields)
+				}
+			}
+		}
+	}
+}
+
+// Test_ImportRowErrors tests that rows that cannot be read are reported at their line with the error, and that the
+// rows after them are still read.
+func Test_ImportRowErrors(t *testing.T) {
+	csvFile := strings.Join([]string{
+		"code,name,price,currency,stock,categoryIDs,publishAt",
+		"A1,Taza,12.30,MXN,3,1|2,2026-03-01T09:30:00Z",
+		"A2,Plato,12.345,MXN,,,",
+		"A3,Vaso,1.00,MXN,-1,,",
+		"A4,Jarra,1.00,MXN,,1|x,",
+		"A5,Tenedor,1.00,MXN,,,mañana",
+		"A6,Cuchara,1.00",
+		"A7,\"Cuchillo,1.00,MXN,,,",
+	}, "\n")
+
+	source, err := NewCSVProductSource(strings.NewReader(csvFile))
+	if err != nil {
+		t.Fatalf("Error reading CSV header: %v", err)
+	}
+
+	ndjsonFile := strings.Join([]string{
+		`{"code":"B1","name":"Taza","price":{"amount":"12.30","currency":"MXN"}}`,
+		``,
+		`{"code":"B2","name":"Plato","weight":3}`,
+		`{"code":"B3",`,
+		`{"code":"B4","stock":"many"}`,
+		`{"code":"B5","name":"Vaso","price":"1.00"}`,
+	}, "\n")
+
+	cases := []struct {
+		format string
+		rows   []*model.ImportRow
+		lines  []int
+		codes  []utils.ErrorCode
+	}{
+		{
+			format: "csv",
+			rows:   readRows(t, source),
+			lines:  []int{2, 3, 4, 5, 6, 7, 8},
+			codes: []utils.ErrorCode{"", utils.ErrInvalidImportPrice, utils.ErrInvalidImportStock, utils.ErrInvalidImportCategories,
+				utils.ErrInvalidImportPublishAt, utils.ErrCSVColumnCountMismatch, utils.ErrInvalidCSVLine},
+		},
+		{
+			format: "ndjson",
+			rows:   readRows(t, NewNDJSONProductSource(strings.NewReader(ndjsonFile))),
+			lines:  []int{1, 3, 4, 5, 6},
+			codes:  []utils.ErrorCode{"", utils.ErrUnknownImportField, utils.ErrInvalidJSONLine, utils.ErrInvalidProductData, ""},
+		},
+	}
+
+	for _, v := range cases {
+		if len(v.rows) != len(v.codes) {
+			t.Fatalf("Expected %d %s rows, got %d", len(v.codes), v.format, len(v.rows))
+		}
+
+		for i, row := range v.rows {
+			var code utils.ErrorCode
+			var e *utils.DBError
+			if errors.As(row.Err, &e) {
+				code = e.Code
+			}
+
+			if row.Line != v.lines[i] || code != v.codes[i] {
+				t.Errorf("Expected %s row %d at line %d with %q, got line %d with %v", v.format, i, v.lines[i], v.codes[i], row.Line, row.Err)
+			}
+
+			if (row.Err == nil) != (row.Product != nil) {
+				t.Errorf("Expected %s row %d to have either a product or an error, got %+v", v.format, i, row)
+			}
+		}
+	}
+}
+
+// Test_ToImportReportDTO tests that the report of an import counts the rows by status and explains the rejected ones
+// in the language of the request.
+func Test_ToImportReportDTO(t *testing.T) {
+	report := &model.ImportReport{}
+	report.Add(&model.ImportResult{Line: 2, Code: "A1", Status: model.ImportCreated, ProductID: 10})
+	report.Add(&model.ImportResult{Line: 3, Code: "A2", Status: model.ImportUpdated, ProductID: 4})
+	report.Add(&model.ImportResult{Line: 4, Code: "A3", Status: model.ImportRejected,
+		Reason: utils.NewError(utils.ErrInvalidImportPrice, errors.New("invalid"), "12.345")})
+
+	resp := ToImportReportDTO(report, "en")
+
+	if resp.Created != 1 || resp.Updated != 1 || resp.Rejected != 1 || len(resp.Rows) != 3 {
+		t.Fatalf("Expected one created, one updated and one rejected row, got %+v", resp)
+	}
+
+	for i, row := range resp.Rows {
+		if row.Line != report.Rows[i].Line || row.Code != report.Rows[i].Code || row.Status != report.Rows[i].Status ||
+			row.ProductID != report.Rows[i].ProductID {
+			t.Errorf("Expected row %d to be %+v, got %+v", i, report.Rows[i], row)
+		}
+	}
+
+	if resp.Rows[0].Reason != "" || !strings.Contains(resp.Rows[2].Reason, "12.345") {
+		t.Errorf("Expected only the rejected row to be explained, got %q and %q", resp.Rows[0].Reason, resp.Rows[2].Reason)
+	}
+}
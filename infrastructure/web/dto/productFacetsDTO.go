@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"codifin-challenge/domain/model"
+	"time"
+)
+
+type ProductFacetsDTO struct {
+	Prices     []*PriceFacetDTO     `json:"prices"`
+	Categories []*CategoryFacetDTO  `json:"categories"`
+	Created    []*CreatedFacetDTO   `json:"created"`
+	Attributes []*AttributeFacetDTO `json:"attributes"`
+}
+
+type PriceFacetDTO struct {
+	Min   model.Money  `json:"min"`
+	Max   *model.Money `json:"max,omitempty"`
+	Count uint         `json:"count"`
+}
+
+type CategoryFacetDTO struct {
+	CategoryID uint   `json:"categoryID"`
+	Name       string `json:"name"`
+	Count      uint   `json:"count"`
+}
+
+type CreatedFacetDTO struct {
+	Days  int       `json:"days"`
+	After time.Time `json:"after"`
+	Count uint      `json:"count"`
+}
+
+type AttributeFacetDTO struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Count uint   `json:"count"`
+}
+
+func ToProductFacetsDTO(facets *model.ProductFacets) *ProductFacetsDTO {
+	if facets == nil {
+		return nil
+	}
+
+	facetsDTO := &ProductFacetsDTO{
+		Prices:     make([]*PriceFacetDTO, 0, len(facets.Prices)),
+		Categories: make([]*CategoryFacetDTO, 0, len(facets.Categories)),
+		Created:    make([]*CreatedFacetDTO, 0, len(facets.Created)),
+		Attributes: make([]*AttributeFacetDTO, 0, len(facets.Attributes)),
+	}
+
+	for _, v := range facets.Prices {
+		facetsDTO.Prices = append(facetsDTO.Prices, &PriceFacetDTO{Min: v.Min, Max: v.Max, Count: v.Count})
+	}
+
+	for _, v := range facets.Categories {
+		facetsDTO.Categories = append(facetsDTO.Categories, &CategoryFacetDTO{CategoryID: v.CategoryID, Name: v.Name, Count: v.Count})
+	}
+
+	for _, v := range facets.Created {
+		facetsDTO.Created = append(facetsDTO.Created, &CreatedFacetDTO{Days: v.Days, After: v.After, Count: v.Count})
+	}
+
+	for _, v := range facets.Attributes {
+		facetsDTO.Attributes = append(facetsDTO.Attributes, &AttributeFacetDTO{Name: v.Name, Value: v.Value, Count: v.Count})
+	}
+
+	return facetsDTO
+}
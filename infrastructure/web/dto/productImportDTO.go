@@ -0,0 +1,271 @@
+package dto
+
+import (
+	"bufio"
+	"bytes"
+	"codifin-challenge/domain/i18n"
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ImportReportDTO struct {
+	DryRun   bool               `json:"dryRun"`
+	Created  uint               `json:"created"`
+	Updated  uint               `json:"updated"`
+	Rejected uint               `json:"rejected"`
+	Rows     []*ImportResultDTO `json:"rows"`
+}
+
+type ImportResultDTO struct {
+	Line      int    `json:"line"`
+	Code      string `json:"code,omitempty"`
+	Status    string `json:"status"`
+	ProductID uint   `json:"productID,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func ToImportReportDTO(report *model.ImportReport, language string) *ImportReportDTO {
+	rows := make([]*ImportResultDTO, 0, len(report.Rows))
+	for _, v := range report.Rows {
+		rows = append(rows, &ImportResultDTO{
+			Line:      v.Line,
+			Code:      v.Code,
+			Status:    v.Status,
+			ProductID: v.ProductID,
+			Reason:    i18n.Localize(v.Reason, language),
+		})
+	}
+
+	return &ImportReportDTO{
+		DryRun:   report.DryRun,
+		Created:  report.Created,
+		Updated:  report.Updated,
+		Rejected: report.Rejected,
+		Rows:     rows,
+	}
+}
+
+// importFields are the fields of ProductData a row of an import may have.
+var importFields = map[string]bool{
+	"code": true, "name": true, "description": true, "price": true,
+	"imageURL": true, "taxCategory": true, "stock": true, "categoryIDs": true,
+	"status": true, "publishAt": true, "unpublishAt": true,
+}
+
+// readOnlyFields are the columns of an export that imports skip.
+var readOnlyFields = map[string]bool{"id": true, "available": true, "createdAt": true, "updatedAt": true}
+
+// CSVProductSource reads products from a CSV file whose header names the
+// fields of ProductData, plus an optional currency for the price. Category
+// IDs are separated by |, and empty cells are left out of the row.
+type CSVProductSource struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+func NewCSVProductSource(r io.Reader) (*CSVProductSource, error) {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, utils.NewError(utils.ErrImportHeaderUnreadable, err)
+	}
+
+	columns := make([]string, 0, len(header))
+	for i, v := range header {
+		column := strings.TrimSpace(v)
+		if i == 0 {
+			column = strings.TrimPrefix(column, "\ufeff")
+		}
+
+		if !importFields[column] && !readOnlyFields[column] && column != "currency" {
+			return nil, utils.NewError(utils.ErrUnknownImportColumn, fmt.Errorf("unknown import column %q", column), column)
+		}
+		columns = append(columns, column)
+	}
+
+	return &CSVProductSource{reader: reader, columns: columns}, nil
+}
+
+func (s *CSVProductSource) Next() (*model.ImportRow, error) {
+	record, err := s.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		code := utils.ErrInvalidCSVLine
+		if errors.Is(err, csv.ErrFieldCount) {
+			code = utils.ErrCSVColumnCountMismatch
+		}
+		return &model.ImportRow{Line: parseErr.StartLine, Err: utils.NewError(code, err)}, nil
+	}
+	if err != nil {
+		return nil, utils.NewError(utils.ErrImportFileUnreadable, err)
+	}
+
+	line, _ := s.reader.FieldPos(0)
+	row := &model.ImportRow{Line: line, Fields: make(map[string]bool)}
+
+	var data ProductData
+	var price, currency string
+	for i, column := range s.columns {
+		value := strings.TrimSpace(record[i])
+		if value == "" || readOnlyFields[column] {
+			continue
+		}
+
+		switch column {
+		case "code":
+			data.Code = value
+		case "name":
+			data.Name = value
+		case "description":
+			data.Description = value
+		case "imageURL":
+			data.ImageURL = value
+		case "taxCategory":
+			data.TaxCategory = value
+		case "status":
+			data.Status = value
+		case "publishAt":
+			if data.PublishAt, err = parseImportTime(value); err != nil {
+				row.Err = utils.NewError(utils.ErrInvalidImportPublishAt, err, value)
+				return row, nil
+			}
+		case "unpublishAt":
+			if data.UnpublishAt, err = parseImportTime(value); err != nil {
+				row.Err = utils.NewError(utils.ErrInvalidImportUnpublishAt, err, value)
+				return row, nil
+			}
+		case "currency":
+			currency = value
+			continue
+		case "price":
+			price = value
+		case "stock":
+			if data.Stock, err = parseStock(value); err != nil {
+				row.Err = utils.NewError(utils.ErrInvalidImportStock, err, value)
+				return row, nil
+			}
+		case "categoryIDs":
+			if data.CategoryIDs, err = parseCategoryIDs(value); err != nil {
+				row.Err = utils.NewError(utils.ErrInvalidImportCategories, err, value)
+				return row, nil
+			}
+		}
+		row.Fields[column] = true
+	}
+
+	if price != "" {
+		if data.Price, err = model.ParseMoney(price, currency); err != nil {
+			row.Err = utils.NewError(utils.ErrInvalidImportPrice, err, price)
+			return row, nil
+		}
+	}
+
+	row.Product = data.ToProduct()
+
+	return row, nil
+}
+
+func parseStock(value string) (uint, error) {
+	stock, err := strconv.ParseUint(value, 10, 0)
+	return uint(stock), err
+}
+
+func parseImportTime(value string) (*time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func parseCategoryIDs(value string) ([]uint, error) {
+	ids := make([]uint, 0)
+	for _, v := range strings.Split(value, "|") {
+		id, err := strconv.ParseUint(strings.TrimSpace(v), 10, 0)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("invalid category ID %q", v)
+		}
+		ids = append(ids, uint(id))
+	}
+
+	return ids, nil
+}
+
+// NDJSONProductSource reads products from a file with a ProductData JSON
+// object per line. Blank lines are skipped.
+type NDJSONProductSource struct {
+	reader *bufio.Reader
+	line   int
+}
+
+func NewNDJSONProductSource(r io.Reader) *NDJSONProductSource {
+	return &NDJSONProductSource{reader: bufio.NewReader(r)}
+}
+
+func (s *NDJSONProductSource) Next() (*model.ImportRow, error) {
+	for {
+		data, err := s.reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, utils.NewError(utils.ErrImportFileUnreadable, err)
+		}
+
+		if len(data) == 0 && errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+
+		s.line++
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		return s.row(data), nil
+	}
+}
+
+func (s *NDJSONProductSource) row(data []byte) *model.ImportRow {
+	row := &model.ImportRow{Line: s.line, Fields: make(map[string]bool)}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		row.Err = utils.NewError(utils.ErrInvalidJSONLine, err)
+		return row
+	}
+
+	for field := range fields {
+		// The currency of an export is part of its price.
+		if readOnlyFields[field] || field == "currency" {
+			continue
+		}
+
+		if !importFields[field] {
+			row.Err = utils.NewError(utils.ErrUnknownImportField, fmt.Errorf("unknown import field %q", field), field)
+			return row
+		}
+		row.Fields[field] = true
+	}
+
+	var productData ProductData
+	if err := json.Unmarshal(data, &productData); err != nil {
+		row.Err = utils.NewError(utils.ErrInvalidProductData, err)
+		return row
+	}
+
+	row.Product = productData.ToProduct()
+
+	return row
+}
@@ -0,0 +1,149 @@
+package dto
+
+import (
+	"codifin-challenge/domain/i18n"
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"codifin-challenge/infrastructure/web/patch"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Media types of the changes a product update may be sent as.
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// productFields maps the JSON names of the fields of ProductData, the
+// document a patch changes, to their indexes.
+var productFields = jsonFields(reflect.TypeOf(ProductData{}))
+
+func jsonFields(t reflect.Type) map[string]int {
+	fields := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = i
+		}
+	}
+
+	return fields
+}
+
+// ProductPatch is a JSON Merge Patch or a JSON Patch of the ProductData
+// document of a product.
+type ProductPatch struct {
+	apply func(doc interface{}) (interface{}, error)
+}
+
+func NewMergePatch(data []byte) (*ProductPatch, error) {
+	changes, err := patch.Decode(data)
+	if err != nil {
+		return nil, utils.NewError(utils.ErrMalformedPatch, err)
+	}
+
+	return &ProductPatch{apply: func(doc interface{}) (interface{}, error) {
+		return patch.Merge(doc, changes), nil
+	}}, nil
+}
+
+func NewJSONPatch(data []byte) (*ProductPatch, error) {
+	operations, err := patch.ParseOperations(data)
+	if err != nil {
+		return nil, utils.NewError(utils.ErrInvalidPatchOperations, err)
+	}
+
+	return &ProductPatch{apply: func(doc interface{}) (interface{}, error) {
+		return patch.Apply(doc, operations)
+	}}, nil
+}
+
+// Apply returns a copy of the product with the patch applied to its document.
+// The copy keeps what the document leaves out, such as its ID and version.
+func (p *ProductPatch) Apply(product *model.Product) (*model.Product, error) {
+	data, err := json.Marshal(ToProductDTO(product).ProductData)
+	if err != nil {
+		return nil, utils.NewError(utils.ErrProductUpdateFailed, err)
+	}
+
+	doc, err := patch.Decode(data)
+	if err != nil {
+		return nil, utils.NewError(utils.ErrProductUpdateFailed, err)
+	}
+
+	patched, err := p.apply(doc)
+	if errors.Is(err, patch.ErrTestFailed) {
+		return nil, utils.NewError(utils.ErrPatchTestFailed, err)
+	}
+	if err != nil {
+		return nil, utils.NewError(utils.ErrPatchNotApplicable, err)
+	}
+
+	fields, ok := patched.(map[string]interface{})
+	if !ok {
+		return nil, utils.NewError(utils.ErrPatchResultNotObject, fmt.Errorf("patched product is a %T", patched))
+	}
+
+	productData, err := decodeProductData(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = Validate(productData); err != nil {
+		return nil, err
+	}
+
+	updated := productData.ToProduct()
+	updated.Model = product.Model
+	updated.Reserved = product.Reserved
+	updated.Version = product.Version
+	updated.Variants = product.Variants
+
+	return updated, nil
+}
+
+// decodeProductData decodes the fields of a ProductData document one by one,
+// so every unknown field and invalid value is reported by its name. Fields
+// left out keep their zero value.
+func decodeProductData(fields map[string]interface{}) (*ProductData, error) {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var data ProductData
+	value := reflect.ValueOf(&data).Elem()
+	invalid := make([]*utils.FieldError, 0)
+
+	for _, name := range names {
+		i, ok := productFields[name]
+		if !ok {
+			invalid = append(invalid, &utils.FieldError{
+				Field: name, Rule: "unknown", Message: i18n.NewMessage("RULE_UNKNOWN_FIELD", name),
+			})
+			continue
+		}
+
+		raw, err := json.Marshal(fields[name])
+		if err == nil {
+			err = json.Unmarshal(raw, value.Field(i).Addr().Interface())
+		}
+		if err != nil {
+			invalid = append(invalid, &utils.FieldError{
+				Field: name, Rule: "type", Message: i18n.NewMessage("RULE_INVALID_VALUE", name),
+			})
+		}
+	}
+
+	if len(invalid) > 0 {
+		return nil, utils.ToValidationError(invalid)
+	}
+
+	return &data, nil
+}
@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"codifin-challenge/domain/model"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+type PromotionData struct {
+	Code          string      `json:"code" binding:"required,max=64"`
+	Description   string      `json:"description" binding:"max=5000"`
+	Type          string      `json:"type" binding:"required,oneof=percentage fixed_amount buy_x_get_y free_item_over_threshold" enums:"percentage,fixed_amount,buy_x_get_y,free_item_over_threshold"`
+	Value         float64     `json:"value" binding:"gte=0"`
+	AmountOff     model.Money `json:"amountOff" binding:"gte=0"`
+	BuyQuantity   uint        `json:"buyQuantity"`
+	GetQuantity   uint        `json:"getQuantity"`
+	Threshold     model.Money `json:"threshold" binding:"gte=0"`
+	FreeProductID *uint       `json:"freeProductID" binding:"omitempty,gt=0"`
+	StartsAt      *time.Time  `json:"startsAt"`
+	EndsAt        *time.Time  `json:"endsAt"`
+	UsageLimit    uint        `json:"usageLimit"`
+	PerCartLimit  uint        `json:"perCartLimit"`
+	ProductIDs    []uint      `json:"productIDs" binding:"dive,gt=0"`
+	CategoryIDs   []uint      `json:"categoryIDs" binding:"dive,gt=0"`
+}
+
+type PromotionDTO struct {
+	ID uint `json:"id"`
+	PromotionData
+	UsageCount uint `json:"usageCount"`
+}
+
+func (p *PromotionData) ToPromotion() *model.Promotion {
+	products := make([]*model.Product, 0, len(p.ProductIDs))
+	for _, id := range p.ProductIDs {
+		products = append(products, &model.Product{Model: gorm.Model{ID: id}})
+	}
+
+	categories := make([]*model.Category, 0, len(p.CategoryIDs))
+	for _, id := range p.CategoryIDs {
+		categories = append(categories, &model.Category{Model: gorm.Model{ID: id}})
+	}
+
+	return &model.Promotion{
+		Code:          strings.TrimSpace(strings.ToUpper(p.Code)),
+		Description:   strings.TrimSpace(p.Description),
+		Type:          model.PromotionType(p.Type),
+		Value:         p.Value,
+		AmountOff:     p.AmountOff,
+		BuyQuantity:   p.BuyQuantity,
+		GetQuantity:   p.GetQuantity,
+		Threshold:     p.Threshold,
+		FreeProductID: p.FreeProductID,
+		StartsAt:      p.StartsAt,
+		EndsAt:        p.EndsAt,
+		UsageLimit:    p.UsageLimit,
+		PerCartLimit:  p.PerCartLimit,
+		Products:      products,
+		Categories:    categories,
+	}
+}
+
+func ToPromotionDTO(promotion *model.Promotion) *PromotionDTO {
+	if promotion != nil {
+		productIDs := make([]uint, 0, len(promotion.Products))
+		for _, v := range promotion.Products {
+			productIDs = append(productIDs, v.ID)
+		}
+
+		categoryIDs := make([]uint, 0, len(promotion.Categories))
+		for _, v := range promotion.Categories {
+			categoryIDs = append(categoryIDs, v.ID)
+		}
+
+		return &PromotionDTO{
+			ID: promotion.ID,
+			PromotionData: PromotionData{
+				Code:          promotion.Code,
+				Description:   promotion.Description,
+				Type:          string(promotion.Type),
+				Value:         promotion.Value,
+				AmountOff:     promotion.AmountOff,
+				BuyQuantity:   promotion.BuyQuantity,
+				GetQuantity:   promotion.GetQuantity,
+				Threshold:     promotion.Threshold,
+				FreeProductID: promotion.FreeProductID,
+				StartsAt:      promotion.StartsAt,
+				EndsAt:        promotion.EndsAt,
+				UsageLimit:    promotion.UsageLimit,
+				PerCartLimit:  promotion.PerCartLimit,
+				ProductIDs:    productIDs,
+				CategoryIDs:   categoryIDs,
+			},
+			UsageCount: promotion.UsageCount,
+		}
+	}
+	return nil
+}
+
+func ToPromotionsDTO(promotions []*model.Promotion) []*PromotionDTO {
+	promotionsDTO := make([]*PromotionDTO, 0)
+	for _, v := range promotions {
+		promotionsDTO = append(promotionsDTO, ToPromotionDTO(v))
+	}
+	return promotionsDTO
+}
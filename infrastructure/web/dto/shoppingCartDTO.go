@@ -1,23 +1,56 @@
 package dto
 
 import (
+	"codifin-challenge/domain/i18n"
 	"codifin-challenge/domain/model"
 )
 
 type ShoppingCartDTO struct {
-	ID    uint           `json:"id"`
-	Items []*ItemCartDTO `json:"items"`
+	ID               uint           `json:"id"`
+	Region           string         `json:"region"`
+	Items            []*ItemCartDTO `json:"items"`
+	Discounts        []*DiscountDTO `json:"discounts"`
+	ItemCount        uint           `json:"itemCount"`
+	Subtotal         model.Money    `json:"subtotal"`
+	Discount         model.Money    `json:"discount"`
+	Tax              model.Money    `json:"tax"`
+	Total            model.Money    `json:"total"`
+	PricesIncludeTax bool           `json:"pricesIncludeTax"`
+	Locked           bool           `json:"locked"`
+}
+
+type CouponData struct {
+	Code string `json:"code" binding:"required,max=64"`
+}
+
+type DiscountDTO struct {
+	Code        string      `json:"code"`
+	Description string      `json:"description,omitempty"`
+	Amount      model.Money `json:"amount"`
+	Reason      string      `json:"reason,omitempty"`
 }
 
 type ItemData struct {
-	ProductID uint `json:"productID"`
-	Count     uint `json:"count"`
+	ProductID uint  `json:"productID" binding:"required"`
+	VariantID *uint `json:"variantID" binding:"omitempty,gt=0"`
+	Count     uint  `json:"count" binding:"min=1"`
 }
 
 type ItemCartDTO struct {
 	Product   *ProductDTO `json:"product"`
 	ProductID uint        `json:"productID"`
-	Count     uint        `json:"count"`
+	// ProductDeleted tells that the product was deleted after the item was
+	// added to the cart; the item is priced at zero and the cart cannot be
+	// checked out with it.
+	ProductDeleted bool        `json:"productDeleted,omitempty"`
+	Variant        *VariantDTO `json:"variant"`
+	VariantID      *uint       `json:"variantID"`
+	Count          uint        `json:"count"`
+	UnitPrice      model.Money `json:"unitPrice"`
+	LineTotal      model.Money `json:"lineTotal"`
+	Discount       model.Money `json:"discount"`
+	TaxRate        float64     `json:"taxRate"`
+	Tax            model.Money `json:"tax"`
 }
 
 func ToItemsCart(shoppingCartID uint, items []*ItemData) []*model.ItemCart {
@@ -34,33 +67,85 @@ func (i *ItemData) ToItemCart(shoppingCartID uint) *model.ItemCart {
 	return &model.ItemCart{
 		ShoppingCartID: shoppingCartID,
 		ProductID:      i.ProductID,
+		VariantID:      i.VariantID,
 		Count:          i.Count,
 	}
 }
 
-func ToShoppingCartDTO(cart *model.ShoppingCart) *ShoppingCartDTO {
-	if cart != nil {
-		return &ShoppingCartDTO{
-			ID:    cart.ID,
-			Items: ToItemsCartDTO(cart.Items),
+func ToShoppingCartDTO(summary *model.CartSummary, language string) *ShoppingCartDTO {
+	if summary != nil && summary.Cart != nil {
+		cartDTO := &ShoppingCartDTO{
+			ID:               summary.Cart.ID,
+			Region:           summary.Cart.Region,
+			Items:            ToItemsCartDTO(summary.Lines, summary.PricesIncludeTax),
+			Discounts:        ToDiscountsDTO(summary.Discounts, language),
+			ItemCount:        summary.ItemCount,
+			Subtotal:         summary.Subtotal,
+			Discount:         summary.DiscountTotal,
+			Tax:              summary.TaxTotal,
+			Total:            summary.Total,
+			PricesIncludeTax: summary.PricesIncludeTax,
+			Locked:           summary.Cart.IsLocked(),
 		}
+
+		if summary.PricesIncludeTax {
+			cartDTO.Subtotal = model.NewMoney(0, summary.Subtotal.Currency)
+			for _, v := range cartDTO.Items {
+				cartDTO.Subtotal = cartDTO.Subtotal.Add(v.LineTotal)
+			}
+			cartDTO.Discount = cartDTO.Subtotal.Sub(cartDTO.Total)
+		}
+
+		return cartDTO
 	}
 
 	return nil
 }
 
-func ToItemCartDTO(item *model.ItemCart) *ItemCartDTO {
-	return &ItemCartDTO{
-		ProductID: item.ProductID,
-		Product:   ToProductDTO(item.Product),
-		Count:     item.Count,
+func ToItemCartDTO(line *model.CartLine, pricesIncludeTax bool) *ItemCartDTO {
+	itemDTO := &ItemCartDTO{
+		ProductID: line.Item.ProductID,
+		Product:   ToProductDTO(line.Item.Product),
+		VariantID: line.Item.VariantID,
+		Variant:   ToVariantDTO(line.Item.Variant),
+		Count:     line.Item.Count,
+		UnitPrice: line.UnitPrice,
+		LineTotal: line.LineTotal,
+		Discount:  line.Discount,
+		TaxRate:   line.TaxRate,
+		Tax:       line.Tax,
+	}
+
+	if line.Item.Product == nil || line.Item.Product.IsDeleted() {
+		itemDTO.ProductDeleted = true
+	}
+
+	if pricesIncludeTax {
+		itemDTO.UnitPrice = line.UnitPrice.Gross(line.TaxRate)
+		itemDTO.LineTotal = line.LineTotal.Gross(line.TaxRate)
+		itemDTO.Discount = line.Discount.Gross(line.TaxRate)
 	}
+
+	return itemDTO
 }
 
-func ToItemsCartDTO(items []*model.ItemCart) []*ItemCartDTO {
+func ToItemsCartDTO(lines []*model.CartLine, pricesIncludeTax bool) []*ItemCartDTO {
 	itemsDTO := make([]*ItemCartDTO, 0)
-	for _, v := range items {
-		itemsDTO = append(itemsDTO, ToItemCartDTO(v))
+	for _, v := range lines {
+		itemsDTO = append(itemsDTO, ToItemCartDTO(v, pricesIncludeTax))
 	}
 	return itemsDTO
 }
+
+func ToDiscountsDTO(discounts []*model.CartDiscount, language string) []*DiscountDTO {
+	discountsDTO := make([]*DiscountDTO, 0)
+	for _, v := range discounts {
+		discountsDTO = append(discountsDTO, &DiscountDTO{
+			Code:        v.Promotion.Code,
+			Description: v.Promotion.Description,
+			Amount:      v.Amount,
+			Reason:      i18n.Localize(v.Reason, language),
+		})
+	}
+	return discountsDTO
+}
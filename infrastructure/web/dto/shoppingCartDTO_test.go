@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"codifin-challenge/domain/model"
+	"testing"
+)
+
+func cartSummary(pricesIncludeTax bool) *model.CartSummary {
+	mxn := func(amount int64) model.Money { return model.NewMoney(amount, model.DefaultCurrency) }
+
+	return &model.CartSummary{
+		Cart: &model.ShoppingCart{Region: "MX"},
+		Lines: []*model.CartLine{
+			{Item: &model.ItemCart{ProductID: 1, Product: &model.Product{TaxCategory: model.TaxStandard}, Count: 2},
+				UnitPrice: mxn(1000), LineTotal: mxn(2000), Discount: mxn(500), TaxRate: 0.16, Tax: mxn(240)},
+			{Item: &model.ItemCart{ProductID: 2, Product: &model.Product{TaxCategory: model.TaxExempt}, Count: 1},
+				UnitPrice: mxn(300), LineTotal: mxn(300), Discount: mxn(0), TaxRate: 0, Tax: mxn(0)},
+		},
+		ItemCount:        3,
+		Subtotal:         mxn(2300),
+		DiscountTotal:    mxn(500),
+		TaxTotal:         mxn(240),
+		Total:            mxn(2040),
+		PricesIncludeTax: pricesIncludeTax,
+	}
+}
+
+// Test_ToShoppingCartDTOPricesIncludeTax tests that carts displayed with their taxes show gross prices and discounts,
+// and a subtotal and discount that still lead to the total charged.
+func Test_ToShoppingCartDTOPricesIncludeTax(t *testing.T) {
+	cases := []struct {
+		pricesIncludeTax bool
+		units            [2]int64
+		lines            [2]int64
+		discounts        [2]int64
+		subtotal         int64
+		discount         int64
+	}{
+		{false, [2]int64{1000, 300}, [2]int64{2000, 300}, [2]int64{500, 0}, 2300, 500},
+		{true, [2]int64{1160, 300}, [2]int64{2320, 300}, [2]int64{580, 0}, 2620, 580},
+	}
+
+	for _, v := range cases {
+		cart := ToShoppingCartDTO(cartSummary(v.pricesIncludeTax), "es")
+
+		for i, item := range cart.Items {
+			if item.UnitPrice.Amount != v.units[i] || item.LineTotal.Amount != v.lines[i] || item.Discount.Amount != v.discounts[i] {
+				t.Errorf("Expected item %d to be displayed at %d, %d and %d off with taxes %v, got %v, %v and %v", i,
+					v.units[i], v.lines[i], v.discounts[i], v.pricesIncludeTax, item.UnitPrice, item.LineTotal, item.Discount)
+			}
+		}
+
+		if cart.Subtotal.Amount != v.subtotal || cart.Discount.Amount != v.discount || cart.Tax.Amount != 240 ||
+			cart.Total.Amount != 2040 {
+			t.Errorf("Expected a subtotal of %d, %d off, 240 of taxes and a total of 2040 with taxes %v, got %v, %v, %v and %v",
+				v.subtotal, v.discount, v.pricesIncludeTax, cart.Subtotal, cart.Discount, cart.Tax, cart.Total)
+		}
+	}
+}
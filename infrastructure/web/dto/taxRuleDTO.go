@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"codifin-challenge/domain/model"
+	"strings"
+)
+
+type TaxRuleData struct {
+	Region      string  `json:"region" binding:"required,max=16"`
+	TaxCategory string  `json:"taxCategory" binding:"required,max=64"`
+	Rate        float64 `json:"rate" binding:"gte=0,lte=1"`
+}
+
+type TaxRuleDTO struct {
+	ID uint `json:"id"`
+	TaxRuleData
+}
+
+func (t *TaxRuleData) ToTaxRule() *model.TaxRule {
+	return &model.TaxRule{
+		Region:      strings.TrimSpace(strings.ToUpper(t.Region)),
+		TaxCategory: strings.TrimSpace(strings.ToLower(t.TaxCategory)),
+		Rate:        t.Rate,
+	}
+}
+
+func ToTaxRuleDTO(rule *model.TaxRule) *TaxRuleDTO {
+	if rule != nil {
+		return &TaxRuleDTO{
+			ID: rule.ID,
+			TaxRuleData: TaxRuleData{
+				Region:      rule.Region,
+				TaxCategory: rule.TaxCategory,
+				Rate:        rule.Rate,
+			},
+		}
+	}
+	return nil
+}
+
+func ToTaxRulesDTO(rules []*model.TaxRule) []*TaxRuleDTO {
+	rulesDTO := make([]*TaxRuleDTO, 0)
+	for _, v := range rules {
+		rulesDTO = append(rulesDTO, ToTaxRuleDTO(v))
+	}
+	return rulesDTO
+}
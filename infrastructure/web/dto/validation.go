@@ -0,0 +1,193 @@
+package dto
+
+import (
+	"codifin-challenge/domain/i18n"
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"strings"
+)
+
+// RegisterValidations sets up the validator of the binding rules of the DTOs:
+// fields are reported by their JSON names, and money is checked by its
+// amount in minor units, so gte=0 rejects negative prices.
+func RegisterValidations() error {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("unsupported validator %T", binding.Validator.Engine())
+	}
+
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	validate.RegisterCustomTypeFunc(func(v reflect.Value) interface{} {
+		return v.Interface().(model.Money).Amount
+	}, model.Money{})
+
+	return nil
+}
+
+// BindingError returns the error of binding a request body to obj: the
+// fields that break a binding rule or have a value of the wrong type, or else
+// a malformed body.
+func BindingError(obj interface{}, err error) *utils.DBError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return utils.ToValidationError([]*utils.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: i18n.NewMessage("RULE_TYPE", typeErr.Field, jsonType(typeErr.Type)),
+		}})
+	}
+
+	var sliceErr binding.SliceValidationError
+	if errors.As(err, &sliceErr) {
+		err = nil
+		if fields := listErrors("", obj); len(fields) > 0 {
+			return utils.ToValidationError(fields)
+		}
+	}
+
+	if fields := fieldErrors("", err); len(fields) > 0 {
+		return utils.ToValidationError(fields)
+	}
+
+	return utils.NewError(utils.ErrMalformedBody, err)
+}
+
+// Validate checks the binding rules of a DTO that was not bound from a
+// request body as a whole.
+func Validate(obj interface{}) error {
+	if fields := fieldErrors("", binding.Validator.ValidateStruct(obj)); len(fields) > 0 {
+		return utils.ToValidationError(fields)
+	}
+
+	return nil
+}
+
+// ValidateIDs checks a list of IDs sent as a request body: it has to have at
+// least one ID, and IDs are positive.
+func ValidateIDs(field string, ids []uint) error {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return nil
+	}
+
+	if fields := fieldErrors(field, validate.Var(ids, "min=1,dive,gt=0")); len(fields) > 0 {
+		return utils.ToValidationError(fields)
+	}
+
+	return nil
+}
+
+// listErrors validates the elements of a list one by one, since the errors
+// gin returns for lists leave out which elements fail.
+func listErrors(prefix string, list interface{}) []*utils.FieldError {
+	value := reflect.Indirect(reflect.ValueOf(list))
+
+	fields := make([]*utils.FieldError, 0)
+	for i := 0; i < value.Len(); i++ {
+		err := binding.Validator.ValidateStruct(value.Index(i).Interface())
+		fields = append(fields, fieldErrors(fmt.Sprintf("%s[%d]", prefix, i), err)...)
+	}
+
+	return fields
+}
+
+// fieldErrors lists the fields of a validation error, with their paths
+// prefixed by the path of what was validated.
+func fieldErrors(prefix string, err error) []*utils.FieldError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	fields := make([]*utils.FieldError, 0, len(validationErrs))
+	for _, v := range validationErrs {
+		field := fieldPath(prefix, v.Namespace())
+		fields = append(fields, &utils.FieldError{Field: field, Rule: v.Tag(), Message: ruleMessage(field, v)})
+	}
+
+	return fields
+}
+
+// fieldPath returns the path of a field from its namespace, which starts with
+// the name of the validated type, or is the index of an element of a list.
+func fieldPath(prefix, namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		namespace = namespace[i+1:]
+	} else if !strings.HasPrefix(namespace, "[") {
+		namespace = ""
+	}
+
+	switch {
+	case prefix == "":
+		return namespace
+	case namespace == "" || strings.HasPrefix(namespace, "["):
+		return prefix + namespace
+	default:
+		return prefix + "." + namespace
+	}
+}
+
+// ruleMessage explains the rule a field breaks.
+func ruleMessage(field string, err validator.FieldError) *i18n.Message {
+	param := err.Param()
+	unit := ""
+	switch err.Kind() {
+	case reflect.String:
+		unit = "_CHARS"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = "_ITEMS"
+	}
+
+	switch err.Tag() {
+	case "required":
+		return i18n.NewMessage("RULE_REQUIRED", field)
+	case "min", "gte":
+		return i18n.NewMessage("RULE_MIN"+unit, field, param)
+	case "max", "lte":
+		return i18n.NewMessage("RULE_MAX"+unit, field, param)
+	case "gt":
+		return i18n.NewMessage("RULE_GT", field, param)
+	case "lt":
+		return i18n.NewMessage("RULE_LT", field, param)
+	case "url":
+		return i18n.NewMessage("RULE_URL", field)
+	case "oneof":
+		return i18n.NewMessage("RULE_ONEOF", field, strings.ReplaceAll(param, " ", ", "))
+	}
+
+	return i18n.NewMessage("RULE_OTHER", field, err.Tag())
+}
+
+// jsonType describes the JSON values Go values of a type are decoded from.
+func jsonType(t reflect.Type) *i18n.Message {
+	switch t.Kind() {
+	case reflect.String:
+		return i18n.NewMessage("TYPE_STRING")
+	case reflect.Bool:
+		return i18n.NewMessage("TYPE_BOOLEAN")
+	case reflect.Slice, reflect.Array:
+		return i18n.NewMessage("TYPE_LIST")
+	case reflect.Map, reflect.Struct:
+		return i18n.NewMessage("TYPE_OBJECT")
+	case reflect.Ptr:
+		return jsonType(t.Elem())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return i18n.NewMessage("TYPE_UNSIGNED")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return i18n.NewMessage("TYPE_INTEGER")
+	}
+	return i18n.NewMessage("TYPE_NUMBER")
+}
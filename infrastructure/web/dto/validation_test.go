@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"codifin-challenge/domain/model"
+	"codifin-challenge/domain/utils"
+	"encoding/json"
+	"github.com/gin-gonic/gin/binding"
+	"net/http"
+	"testing"
+)
+
+func fieldRules(t *testing.T, err error) map[string]string {
+	t.Helper()
+
+	e, ok := err.(*utils.DBError)
+	if !ok || e.Status != http.StatusBadRequest {
+		t.Fatalf("Expected a bad request, got %v", err)
+	}
+
+	rules := make(map[string]string)
+	for _, v := range e.Fields {
+		rules[v.Field] = v.Rule
+	}
+	return rules
+}
+
+// Test_Validate tests that the binding rules of ProductData are reported by
+// the JSON names of the fields.
+func Test_Validate(t *testing.T) {
+	if err := RegisterValidations(); err != nil {
+		t.Fatalf("Error registering validations: %v", err)
+	}
+
+	product := &ProductData{Price: model.NewMoney(-100, model.DefaultCurrency), ImageURL: "image", CategoryIDs: []uint{1, 0}}
+	rules := fieldRules(t, Validate(product))
+
+	expected := map[string]string{"name": "required", "price": "gte", "imageURL": "url", "categoryIDs[1]": "gt"}
+	for field, rule := range expected {
+		if rules[field] != rule {
+			t.Errorf("Expected %s to break %s, got %v", field, rule, rules)
+		}
+	}
+
+	product = &ProductData{Name: "Product", Price: model.NewMoney(0, model.DefaultCurrency), ImageURL: "https://example.com/a.png"}
+	if err := Validate(product); err != nil {
+		t.Errorf("Expected a valid product, got %v", err)
+	}
+}
+
+// Test_BindingError tests the fields reported for lists of items, values of
+// the wrong type and lists of IDs.
+func Test_BindingError(t *testing.T) {
+	if err := RegisterValidations(); err != nil {
+		t.Fatalf("Error registering validations: %v", err)
+	}
+
+	items := []*ItemData{{ProductID: 1, Count: 1}, {Count: 0}}
+	rules := fieldRules(t, BindingError(items, binding.Validator.ValidateStruct(items)))
+	if len(rules) != 2 || rules["[1].productID"] != "required" || rules["[1].count"] != "min" {
+		t.Errorf("Expected the second item to miss its product and count, got %v", rules)
+	}
+
+	var item ItemData
+	err := json.Unmarshal([]byte(`{"productID":1,"count":-1}`), &item)
+	if rules = fieldRules(t, BindingError(&item, err)); rules["count"] != "type" {
+		t.Errorf("Expected count to have the wrong type, got %v", rules)
+	}
+
+	err = json.Unmarshal([]byte(`{`), &item)
+	if e := BindingError(&item, err); len(e.Fields) != 0 || e.Code != utils.ErrMalformedBody {
+		t.Errorf("Expected malformed JSON to be reported without fields, got %+v", e)
+	}
+
+	if rules = fieldRules(t, ValidateIDs("productIds", []uint{})); rules["productIds"] != "min" {
+		t.Errorf("Expected an empty list of IDs to be rejected, got %v", rules)
+	}
+
+	if rules = fieldRules(t, ValidateIDs("productIds", []uint{3, 0})); rules["productIds[1]"] != "gt" {
+		t.Errorf("Expected the ID 0 to be rejected, got %v", rules)
+	}
+}
@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"codifin-challenge/domain/model"
+	"strings"
+)
+
+type VariantData struct {
+	SKU        string            `json:"sku" binding:"required,max=64"`
+	Price      *model.Money      `json:"price" binding:"omitempty,gte=0"`
+	Stock      uint              `json:"stock"`
+	Attributes map[string]string `json:"attributes" binding:"dive,keys,required,max=64,endkeys,max=255"`
+}
+
+type VariantDTO struct {
+	ID uint `json:"id"`
+	VariantData
+	Available uint `json:"available"`
+}
+
+func (v *VariantData) ToVariant(productID uint) *model.ProductVariant {
+	attributes := model.Attributes{}
+	for name, value := range v.Attributes {
+		attributes[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return &model.ProductVariant{
+		ProductID:  productID,
+		SKU:        strings.TrimSpace(strings.ToUpper(v.SKU)),
+		Price:      v.Price,
+		Stock:      v.Stock,
+		Attributes: attributes,
+	}
+}
+
+func ToVariantDTO(variant *model.ProductVariant) *VariantDTO {
+	if variant != nil {
+		return &VariantDTO{
+			ID: variant.ID,
+			VariantData: VariantData{
+				SKU:        variant.SKU,
+				Price:      variant.Price,
+				Stock:      variant.Stock,
+				Attributes: variant.Attributes,
+			},
+			Available: variant.Available(),
+		}
+	}
+	return nil
+}
+
+func ToVariantsDTO(variants []*model.ProductVariant) []*VariantDTO {
+	variantsDTO := make([]*VariantDTO, 0)
+	for _, v := range variants {
+		variantsDTO = append(variantsDTO, ToVariantDTO(v))
+	}
+	return variantsDTO
+}
@@ -0,0 +1,57 @@
+package web
+
+import (
+	"log"
+	"time"
+)
+
+// startJobs launches the background jobs of the server.
+func (s *Server) startJobs() {
+	releaseInterval := time.Duration(s.cfg.Cart.ReleaseIntervalSeconds) * time.Second
+	go s.runEvery(releaseInterval, "release expired reservations", func() error {
+		released, err := s.services.shoppingCartService.ReleaseExpiredReservations()
+		if released > 0 {
+			log.Printf("%d expired stock reservations were released", released)
+		}
+		return err
+	})
+
+	scheduleInterval := time.Duration(s.cfg.Catalog.ScheduleIntervalSeconds) * time.Second
+	go s.runEvery(scheduleInterval, "apply product schedules", func() error {
+		published, archived, err := s.services.productService.ApplySchedule()
+		if published > 0 || archived > 0 {
+			log.Printf("%d scheduled products were published and %d archived", published, archived)
+		}
+		return err
+	})
+
+	// Deleted products are kept for the retention days, or forever without them.
+	purgeInterval := time.Duration(s.cfg.Catalog.PurgeIntervalSeconds) * time.Second
+	if s.cfg.Catalog.RetentionDays <= 0 {
+		purgeInterval = 0
+	}
+	go s.runEvery(purgeInterval, "purge deleted products", func() error {
+		purged, err := s.services.productService.PurgeDeletedProducts(s.cfg.Catalog.RetentionDays)
+		if purged > 0 {
+			log.Printf("%d deleted products were purged", purged)
+		}
+		return err
+	})
+}
+
+// runEvery runs the job once per interval until the process ends.
+func (s *Server) runEvery(interval time.Duration, name string, job func() error) {
+	if interval <= 0 {
+		log.Printf("job '%s' is disabled", name)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := job(); err != nil {
+			log.Printf("job '%s' failed: %s", name, err.Error())
+		}
+	}
+}
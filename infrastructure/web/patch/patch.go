@@ -0,0 +1,443 @@
+// Package patch applies JSON Merge Patches (RFC 7396) and JSON Patches
+// (RFC 6902) to JSON documents decoded into interface{} values. Numbers are
+// kept as json.Number, so they are not rounded on the way.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrTestFailed is returned when a test operation of a JSON Patch does not
+// find the value it expects.
+var ErrTestFailed = errors.New("test operation failed")
+
+// Operation is an operation of a JSON Patch. Value is only meaningful for the
+// operations that take one.
+type Operation struct {
+	Op    string
+	Path  string
+	From  string
+	Value interface{}
+}
+
+// Decode decodes a JSON document, keeping its numbers as json.Number.
+func Decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid data after the JSON document")
+	}
+
+	return doc, nil
+}
+
+// Merge returns the document with a JSON Merge Patch applied: the members of
+// a patch object replace the ones of the document, recursively, and its null
+// members remove them. Any other patch replaces the whole document. The
+// document is left unchanged.
+func Merge(doc, patch interface{}) interface{} {
+	changes, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	target, _ := doc.(map[string]interface{})
+	merged := make(map[string]interface{}, len(target)+len(changes))
+	for k, v := range target {
+		merged[k] = v
+	}
+
+	for k, v := range changes {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = Merge(merged[k], v)
+	}
+
+	return merged
+}
+
+// ParseOperations decodes the operations of a JSON Patch, making sure every
+// operation has the members it needs.
+func ParseOperations(data []byte) ([]*Operation, error) {
+	var raw []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	operations := make([]*Operation, 0, len(raw))
+	for i, v := range raw {
+		operation, err := parseOperation(v)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+		operations = append(operations, operation)
+	}
+
+	return operations, nil
+}
+
+func parseOperation(members map[string]json.RawMessage) (*Operation, error) {
+	operation := &Operation{}
+
+	if err := stringMember(members, "op", &operation.Op); err != nil {
+		return nil, err
+	}
+
+	if err := stringMember(members, "path", &operation.Path); err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "add", "replace", "test":
+		value, ok := members["value"]
+		if !ok {
+			return nil, fmt.Errorf("%s without value", operation.Op)
+		}
+
+		var err error
+		if operation.Value, err = Decode(value); err != nil {
+			return nil, err
+		}
+	case "move", "copy":
+		if err := stringMember(members, "from", &operation.From); err != nil {
+			return nil, err
+		}
+	case "remove":
+	default:
+		return nil, fmt.Errorf("unknown op %q", operation.Op)
+	}
+
+	return operation, nil
+}
+
+func stringMember(members map[string]json.RawMessage, name string, value *string) error {
+	raw, ok := members[name]
+	if !ok {
+		return fmt.Errorf("missing %s", name)
+	}
+
+	if err := json.Unmarshal(raw, value); err != nil {
+		return fmt.Errorf("%s is not a string", name)
+	}
+
+	return nil
+}
+
+// Apply returns the document with the operations of a JSON Patch applied in
+// order. Either every operation applies or the patch fails, and the document
+// is left unchanged either way.
+func Apply(doc interface{}, operations []*Operation) (interface{}, error) {
+	doc = deepCopy(doc)
+
+	for i, v := range operations {
+		var err error
+		if doc, err = applyOperation(doc, v); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, v.Op, v.Path, err)
+		}
+	}
+
+	return doc, nil
+}
+
+func applyOperation(doc interface{}, operation *Operation) (interface{}, error) {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "add":
+		return add(doc, path, deepCopy(operation.Value))
+	case "remove":
+		doc, _, err = remove(doc, path)
+		return doc, err
+	case "replace":
+		if len(path) == 0 {
+			return deepCopy(operation.Value), nil
+		}
+		if doc, _, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+		return add(doc, path, deepCopy(operation.Value))
+	case "test":
+		value, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !Equal(value, operation.Value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	}
+
+	from, err := parsePointer(operation.From)
+	if err != nil {
+		return nil, err
+	}
+
+	if operation.Op == "copy" {
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, deepCopy(value))
+	}
+
+	if isPrefix(from, path) && len(from) < len(path) {
+		return nil, errors.New("cannot move a value into itself")
+	}
+
+	doc, value, err := remove(doc, from)
+	if err != nil {
+		return nil, err
+	}
+
+	return add(doc, path, value)
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its reference tokens.
+// The empty pointer refers to the whole document.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, v := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(v, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+
+	for i, v := range prefix {
+		if path[i] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// get returns the value a path refers to.
+func get(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			doc = value
+		case []interface{}:
+			i, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("cannot refer to %q inside a scalar value", token)
+		}
+	}
+
+	return doc, nil
+}
+
+// add returns the document with a value added at a path: a new or replaced
+// member of an object, or a new element of an array, where - appends.
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return update(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[token] = value
+			return node, nil
+		case []interface{}:
+			i := len(node)
+			if token != "-" {
+				var err error
+				if i, err = arrayIndex(token, len(node)); err != nil {
+					return nil, err
+				}
+			}
+
+			node = append(node, nil)
+			copy(node[i+1:], node[i:])
+			node[i] = value
+			return node, nil
+		default:
+			return nil, fmt.Errorf("cannot add %q to a scalar value", token)
+		}
+	})
+}
+
+// remove returns the document without the value at a path, and the value.
+func remove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.New("cannot remove the whole document")
+	}
+
+	var removed interface{}
+	doc, err := update(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			removed = value
+			delete(node, token)
+			return node, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[i]
+			return append(node[:i], node[i+1:]...), nil
+		default:
+			return nil, fmt.Errorf("cannot remove %q from a scalar value", token)
+		}
+	})
+
+	return doc, removed, err
+}
+
+// update returns the document with the container that holds the last token
+// of a path replaced by what fn makes of it.
+func update(doc interface{}, path []string, fn func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	token := path[0]
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		child, ok := node[token]
+		if !ok {
+			return nil, fmt.Errorf("member %q not found", token)
+		}
+
+		updated, err := update(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[token] = updated
+		return node, nil
+	case []interface{}:
+		i, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+
+		updated, err := update(node[i], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = updated
+		return node, nil
+	default:
+		return nil, fmt.Errorf("cannot refer to %q inside a scalar value", token)
+	}
+}
+
+// arrayIndex parses the index of an array element, which has to be a decimal
+// without leading zeros of at most last.
+func arrayIndex(token string, last int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+
+	i, err := strconv.Atoi(token)
+	if err != nil || i > last {
+		return 0, fmt.Errorf("array index %s out of bounds", token)
+	}
+
+	return i, nil
+}
+
+// Equal reports whether two decoded JSON values are equal: numbers by their
+// value, objects regardless of the order of their members.
+func Equal(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			w, ok := b[k]
+			if !ok || !Equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !Equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, okA := new(big.Rat).SetString(a.String())
+		y, okB := new(big.Rat).SetString(b.String())
+		return okA && okB && x.Cmp(y) == 0
+	default:
+		return a == b
+	}
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for k, w := range v {
+			copied[k] = deepCopy(w)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, w := range v {
+			copied[i] = deepCopy(w)
+		}
+		return copied
+	default:
+		return v
+	}
+}
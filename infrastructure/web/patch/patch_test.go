@@ -0,0 +1,131 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+)
+
+func mustDecode(t *testing.T, data string) interface{} {
+	t.Helper()
+
+	doc, err := Decode([]byte(data))
+	if err != nil {
+		t.Fatalf("Error decoding %s: %v", data, err)
+	}
+	return doc
+}
+
+// Test_Merge tests Merge against the examples of RFC 7396.
+func Test_Merge(t *testing.T) {
+	cases := []struct{ doc, patch, expected string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, v := range cases {
+		doc := mustDecode(t, v.doc)
+		merged := Merge(doc, mustDecode(t, v.patch))
+		if !Equal(merged, mustDecode(t, v.expected)) {
+			t.Errorf("Expected %s merged with %s to be %s, got %v", v.doc, v.patch, v.expected, merged)
+		}
+
+		if !Equal(doc, mustDecode(t, v.doc)) {
+			t.Errorf("Expected %s to be left unchanged, got %v", v.doc, doc)
+		}
+	}
+}
+
+// Test_Apply tests Apply against examples of RFC 6902.
+func Test_Apply(t *testing.T) {
+	cases := []struct{ doc, patch, expected string }{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"foo":{"bar":1}}`, `[{"op":"copy","from":"/foo","path":"/baz"}]`, `{"foo":{"bar":1},"baz":{"bar":1}}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10},{"op":"remove","path":"/~1"}]`, `{"~1":10}`},
+		{`{"foo":null}`, `[{"op":"replace","path":"/foo","value":null}]`, `{"foo":null}`},
+	}
+
+	for _, v := range cases {
+		operations, err := ParseOperations([]byte(v.patch))
+		if err != nil {
+			t.Fatalf("Error parsing %s: %v", v.patch, err)
+		}
+
+		doc := mustDecode(t, v.doc)
+		patched, err := Apply(doc, operations)
+		if err != nil {
+			t.Fatalf("Error applying %s to %s: %v", v.patch, v.doc, err)
+		}
+
+		if !Equal(patched, mustDecode(t, v.expected)) {
+			t.Errorf("Expected %s patched with %s to be %s, got %v", v.doc, v.patch, v.expected, patched)
+		}
+
+		if !Equal(doc, mustDecode(t, v.doc)) {
+			t.Errorf("Expected %s to be left unchanged, got %v", v.doc, doc)
+		}
+	}
+}
+
+// Test_ApplyErrors tests that patches that cannot apply fail as a whole.
+func Test_ApplyErrors(t *testing.T) {
+	cases := []struct{ doc, patch string }{
+		{`{"foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/2","value":"qux"}]`},
+		{`{"foo":["bar"]}`, `[{"op":"remove","path":"/foo/01"}]`},
+		{`{"foo":{"bar":1}}`, `[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`},
+		{`{"foo":"bar"}`, `[{"op":"remove","path":""}]`},
+	}
+
+	for _, v := range cases {
+		operations, err := ParseOperations([]byte(v.patch))
+		if err != nil {
+			t.Fatalf("Error parsing %s: %v", v.patch, err)
+		}
+
+		if _, err = Apply(mustDecode(t, v.doc), operations); err == nil || errors.Is(err, ErrTestFailed) {
+			t.Errorf("Expected %s not to apply to %s, got %v", v.patch, v.doc, err)
+		}
+	}
+
+	operations, _ := ParseOperations([]byte(`[{"op":"replace","path":"/foo","value":"baz"},{"op":"test","path":"/foo","value":"bar"}]`))
+	if _, err := Apply(mustDecode(t, `{"foo":"bar"}`), operations); !errors.Is(err, ErrTestFailed) {
+		t.Errorf("Expected the test to fail, got %v", err)
+	}
+
+	invalid := []string{
+		`{"op":"add","path":"/a","value":1}`,
+		`[{"op":"add","path":"/a"}]`,
+		`[{"op":"move","path":"/a"}]`,
+		`[{"op":"launch","path":"/a"}]`,
+		`[{"path":"/a","value":1}]`,
+	}
+	for _, v := range invalid {
+		if _, err := ParseOperations([]byte(v)); err == nil {
+			t.Errorf("Expected %s to be an invalid patch", v)
+		}
+	}
+}
@@ -1,27 +1,140 @@
 package responses
 
 import (
+	"codifin-challenge/domain/i18n"
 	"codifin-challenge/domain/utils"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
 )
 
+// ProblemContentType is the media type of error responses (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix starts the URI of the type of a problem, which ends with
+// its error code.
+const problemTypePrefix = "urn:codifin:problem:"
+
+// debugMode adds what caused errors to their responses.
+var debugMode bool
+
+// SuccessCode is the code of the message of a successful response.
+type SuccessCode string
+
+const (
+	ServiceOnline       SuccessCode = "SERVICE_ONLINE"
+	ProductUpdated      SuccessCode = "PRODUCT_UPDATED"
+	ProductDeleted      SuccessCode = "PRODUCT_DELETED"
+	ProductRestored     SuccessCode = "PRODUCT_RESTORED"
+	VariantDeleted      SuccessCode = "VARIANT_DELETED"
+	CategoryDeleted     SuccessCode = "CATEGORY_DELETED"
+	PromotionDeleted    SuccessCode = "PROMOTION_DELETED"
+	ExchangeRateDeleted SuccessCode = "EXCHANGE_RATE_DELETED"
+	ProductPriceDeleted SuccessCode = "PRODUCT_PRICE_DELETED"
+	TaxRuleDeleted      SuccessCode = "TAX_RULE_DELETED"
+)
+
+// SuccessDTO is a message of a successful response. Messages of the catalog
+// are sent in the language of the request.
 type SuccessDTO struct {
 	Message interface{} `json:"message"`
 }
 
-type ErrorDTO struct {
-	Message      string `json:"message"`
-	ErrorMessage string `json:"errorMessage"`
+// NewSuccess returns the message of a success code.
+func NewSuccess(code SuccessCode) SuccessDTO {
+	return SuccessDTO{Message: i18n.NewMessage(string(code))}
+}
+
+// ProblemDTO is an error response, a problem details object of RFC 7807
+// extended with the code of the error and the fields that are invalid. Debug
+// is what caused the error, only sent in debug mode.
+type ProblemDTO struct {
+	Type     string           `json:"type" example:"urn:codifin:problem:product-not-found"`
+	Title    string           `json:"title" example:"Recurso no encontrado"`
+	Status   int              `json:"status" example:"404"`
+	Detail   string           `json:"detail" example:"El producto solicitado no existe"`
+	Instance string           `json:"instance" example:"/v1/product/7"`
+	Code     string           `json:"code" example:"PRODUCT_NOT_FOUND"`
+	Fields   []*FieldErrorDTO `json:"fields,omitempty"`
+	Debug    string           `json:"debug,omitempty"`
+}
+
+type FieldErrorDTO struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// SetDebugMode sets whether error responses include what caused them, which
+// may expose internals such as SQL errors.
+func SetDebugMode(enabled bool) {
+	debugMode = enabled
+}
+
+// Language returns the language of the messages of a request, negotiated from
+// its Accept-Language header.
+func Language(c *gin.Context) string {
+	return i18n.Negotiate(c.GetHeader("Accept-Language"))
 }
 
 func SendError(c *gin.Context, err *utils.DBError) {
-	c.JSON(err.Code, newErrorResponse(err.UserMessage, err.DevelopMessage.Error()))
+	language := setLanguage(c)
+	resp := newProblem(err, c.Request.URL.RequestURI(), language)
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(err.Status, resp)
 }
 
 func SendSuccess(c *gin.Context, statusCode int, data interface{}) {
+	language := setLanguage(c)
+	if success, ok := data.(SuccessDTO); ok {
+		if m, ok := success.Message.(i18n.Localizable); ok {
+			data = SuccessDTO{Message: m.Localize(language)}
+		}
+	}
+
 	c.JSON(statusCode, data)
 }
 
-func newErrorResponse(message, errorMessage string) *ErrorDTO {
-	return &ErrorDTO{Message: message, ErrorMessage: errorMessage}
+// SendNotModified answers 304 to a conditional request, with the headers the
+// full response would have had.
+func SendNotModified(c *gin.Context) {
+	setLanguage(c)
+	c.Status(http.StatusNotModified)
+}
+
+// setLanguage negotiates the language of a response and tells clients and
+// caches which one it is.
+func setLanguage(c *gin.Context) string {
+	language := Language(c)
+	c.Header("Content-Language", language)
+	c.Writer.Header().Add("Vary", "Accept-Language")
+
+	return language
+}
+
+func newProblem(err *utils.DBError, instance, language string) *ProblemDTO {
+	title := i18n.Translate(language, fmt.Sprintf("TITLE_%d", err.Status))
+	if strings.HasPrefix(title, "TITLE_") {
+		title = http.StatusText(err.Status)
+	}
+
+	problem := &ProblemDTO{
+		Type:     problemTypePrefix + strings.ReplaceAll(strings.ToLower(string(err.Code)), "_", "-"),
+		Title:    title,
+		Status:   err.Status,
+		Detail:   err.Localize(language),
+		Instance: instance,
+		Code:     string(err.Code),
+	}
+
+	for _, v := range err.Fields {
+		problem.Fields = append(problem.Fields, &FieldErrorDTO{Field: v.Field, Rule: v.Rule, Message: i18n.Localize(v.Message, language)})
+	}
+
+	if debugMode && err.DevelopMessage != nil {
+		problem.Debug = err.DevelopMessage.Error()
+	}
+
+	return problem
 }
@@ -12,7 +12,7 @@ func (s *Server) setRoutes() {
 	s.router.Use(s.middlewares.AddCORS())
 
 	s.router.GET("", func(c *gin.Context) {
-		responses.SendSuccess(c, http.StatusOK, gin.H{"message": "El servicio está en línea"})
+		responses.SendSuccess(c, http.StatusOK, responses.NewSuccess(responses.ServiceOnline))
 		return
 	})
 
@@ -31,11 +31,44 @@ func (s *Server) addV1Routes() {
 	products := v1.Group("products")
 	products.GET("", s.controllers.productCtrl.FindProducts)
 	products.POST("", s.controllers.productCtrl.NewProduct)
+	products.POST("import", s.controllers.productCtrl.ImportProducts)
+	products.GET("export", s.controllers.productCtrl.ExportProducts)
+	products.GET("by-code/:code", s.controllers.productCtrl.FindProductByCode)
 
 	product := v1.Group("product")
 	product.GET(":id", s.controllers.productCtrl.FindProduct)
 	product.PATCH(":id", s.controllers.productCtrl.UpdateProduct)
 	product.DELETE(":id", s.controllers.productCtrl.RemoveProduct)
+	product.POST(":id/variants", s.controllers.variantCtrl.NewVariant)
+	product.PUT(":id/variants/:variantID", s.controllers.variantCtrl.UpdateVariant)
+	product.DELETE(":id/variants/:variantID", s.controllers.variantCtrl.RemoveVariant)
+
+	categories := v1.Group("categories")
+	categories.GET("", s.controllers.categoryCtrl.FindCategoryTree)
+	categories.POST("", s.controllers.categoryCtrl.NewCategory)
+
+	category := v1.Group("category")
+	category.GET(":id", s.controllers.categoryCtrl.FindCategory)
+	category.PUT(":id", s.controllers.categoryCtrl.UpdateCategory)
+	category.DELETE(":id", s.controllers.categoryCtrl.RemoveCategory)
+
+	promotions := v1.Group("promotions")
+	promotions.GET("", s.controllers.promotionCtrl.FindPromotions)
+	promotions.POST("", s.controllers.promotionCtrl.NewPromotion)
+
+	promotion := v1.Group("promotion")
+	promotion.GET(":id", s.controllers.promotionCtrl.FindPromotion)
+	promotion.PUT(":id", s.controllers.promotionCtrl.UpdatePromotion)
+	promotion.DELETE(":id", s.controllers.promotionCtrl.RemovePromotion)
+
+	taxRules := v1.Group("tax-rules")
+	taxRules.GET("", s.controllers.taxRuleCtrl.FindTaxRules)
+	taxRules.POST("", s.controllers.taxRuleCtrl.NewTaxRule)
+
+	taxRule := v1.Group("tax-rule")
+	taxRule.GET(":id", s.controllers.taxRuleCtrl.FindTaxRule)
+	taxRule.PUT(":id", s.controllers.taxRuleCtrl.UpdateTaxRule)
+	taxRule.DELETE(":id", s.controllers.taxRuleCtrl.RemoveTaxRule)
 
 	carts := v1.Group("carts")
 	carts.POST("", s.controllers.shoppingCartCtrl.NewCart)
@@ -44,5 +77,25 @@ func (s *Server) addV1Routes() {
 	cart.POST(":id/items", s.controllers.shoppingCartCtrl.AddItem)
 	cart.DELETE(":id/items", s.controllers.shoppingCartCtrl.RemoveItems)
 	cart.GET(":id", s.controllers.shoppingCartCtrl.FindShoppingCart)
+	cart.POST(":id/coupons", s.controllers.shoppingCartCtrl.ApplyCoupon)
+	cart.DELETE(":id/coupons/:code", s.controllers.shoppingCartCtrl.RemoveCoupon)
+	cart.POST(":id/checkout", s.controllers.orderCtrl.Checkout)
+
+	order := v1.Group("order")
+	order.GET(":id", s.controllers.orderCtrl.FindOrder)
+	order.POST(":id/:action", s.controllers.orderCtrl.ChangeOrderStatus)
 
+	admin := v1.Group("admin")
+	admin.GET("products", s.controllers.productCtrl.FindAdminProducts)
+	admin.GET("products/deleted", s.controllers.productCtrl.FindDeletedProducts)
+	admin.GET("products/by-code/:code", s.controllers.productCtrl.FindAdminProductByCode)
+	admin.GET("product/:id", s.controllers.productCtrl.FindAdminProduct)
+	admin.DELETE("products/deleted", s.controllers.productCtrl.PurgeProducts)
+	admin.POST("product/:id/restore", s.controllers.productCtrl.RestoreProduct)
+	admin.GET("exchange-rates", s.controllers.priceCtrl.FindExchangeRates)
+	admin.PUT("exchange-rate/:currency", s.controllers.priceCtrl.SetExchangeRate)
+	admin.DELETE("exchange-rate/:currency", s.controllers.priceCtrl.RemoveExchangeRate)
+	admin.GET("product/:id/prices", s.controllers.priceCtrl.FindProductPrices)
+	admin.PUT("product/:id/prices", s.controllers.priceCtrl.SetProductPrice)
+	admin.DELETE("product/:id/prices/:currency", s.controllers.priceCtrl.RemoveProductPrice)
 }
@@ -3,15 +3,19 @@ package web
 import (
 	"codifin-challenge/config"
 	_ "codifin-challenge/docs"
+	"codifin-challenge/domain/i18n"
 	"codifin-challenge/domain/repository"
 	"codifin-challenge/domain/service"
 	"codifin-challenge/infrastructure/web/controller"
 	"codifin-challenge/infrastructure/web/database"
+	"codifin-challenge/infrastructure/web/dto"
 	"codifin-challenge/infrastructure/web/middlewares"
+	"codifin-challenge/infrastructure/web/responses"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"log"
+	"time"
 )
 
 type Server struct {
@@ -27,16 +31,34 @@ type Server struct {
 type Controllers struct {
 	productCtrl      *controller.ProductController
 	shoppingCartCtrl *controller.ShoppingCartController
+	orderCtrl        *controller.OrderController
+	categoryCtrl     *controller.CategoryController
+	variantCtrl      *controller.VariantController
+	promotionCtrl    *controller.PromotionController
+	taxRuleCtrl      *controller.TaxRuleController
+	priceCtrl        *controller.PriceController
 }
 
 type Services struct {
 	productService      service.ProductService
 	shoppingCartService service.ShoppingCartService
+	orderService        service.OrderService
+	categoryService     service.CategoryService
+	variantService      service.VariantService
+	promotionService    service.PromotionService
+	taxService          service.TaxService
+	priceService        service.PriceService
 }
 
 type Repositories struct {
 	productRepository      repository.ProductRepository
 	shoppingCartRepository repository.ShoppingCartRepository
+	orderRepository        repository.OrderRepository
+	categoryRepository     repository.CategoryRepository
+	variantRepository      repository.VariantRepository
+	promotionRepository    repository.PromotionRepository
+	taxRuleRepository      repository.TaxRuleRepository
+	priceRepository        repository.PriceRepository
 }
 
 func NewServer() *Server {
@@ -46,6 +68,8 @@ func NewServer() *Server {
 }
 
 func (s *Server) Run() {
+	s.startJobs()
+
 	err := s.router.Run(fmt.Sprintf(":%s", s.cfg.Host.Port))
 	if err != nil {
 		log.Fatalf("server can not run: %s", err.Error())
@@ -67,7 +91,7 @@ func (s *Server) setConfig() {
 }
 
 func (s *Server) setDataBase() {
-	db, err := database.NewDataBase(s.cfg.DB.Host, s.cfg.DB.Port, s.cfg.DB.User, s.cfg.DB.Password, s.cfg.DB.Name, s.cfg.DB.Retries)
+	db, err := OpenDataBase(s.cfg.DB)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -80,23 +104,68 @@ func (s *Server) setDataBase() {
 	s.db = db
 }
 
+// OpenDataBase connects to the database described by the configuration.
+func OpenDataBase(cfg config.DB) (*gorm.DB, error) {
+	return database.NewDataBase(database.Settings{
+		Driver:      cfg.Driver,
+		Host:        cfg.Host,
+		Port:        cfg.Port,
+		User:        cfg.User,
+		Password:    cfg.Password,
+		Name:        cfg.Name,
+		SSLMode:     cfg.SSLMode,
+		SSLRootCert: cfg.SSLRootCert,
+		SSLCert:     cfg.SSLCert,
+		SSLKey:      cfg.SSLKey,
+		Retries:     cfg.Retries,
+	})
+}
+
 func (s *Server) setRouter() {
 	s.router = gin.Default()
+	responses.SetDebugMode(s.cfg.DebugMode)
+
+	if err := i18n.SetDefaultLanguage(s.cfg.I18n.DefaultLanguage); err != nil {
+		log.Fatal(err.Error())
+	}
+
+	if err := dto.RegisterValidations(); err != nil {
+		log.Fatal(err.Error())
+	}
 }
 
 func (s *Server) setRepositories() {
 	s.repositories.productRepository = repository.NewProductRepository(s.db)
-	s.repositories.shoppingCartRepository = repository.NewShoppingCartRepository(s.db)
+	reservationTTL := time.Duration(s.cfg.Cart.ReservationMinutes) * time.Minute
+	s.repositories.shoppingCartRepository = repository.NewShoppingCartRepository(s.db, reservationTTL)
+	s.repositories.orderRepository = repository.NewOrderRepository(s.db)
+	s.repositories.categoryRepository = repository.NewCategoryRepository(s.db)
+	s.repositories.variantRepository = repository.NewVariantRepository(s.db)
+	s.repositories.promotionRepository = repository.NewPromotionRepository(s.db)
+	s.repositories.taxRuleRepository = repository.NewTaxRuleRepository(s.db)
+	s.repositories.priceRepository = repository.NewPriceRepository(s.db)
 }
 
 func (s *Server) setServices() {
 	s.middlewares = middlewares.NewMiddlewareService()
 
-	s.services.productService = service.NewProductService(s.repositories.productRepository)
-	s.services.shoppingCartService = service.NewShoppingCartService(s.repositories.shoppingCartRepository)
+	s.services.categoryService = service.NewCategoryService(s.repositories.categoryRepository)
+	s.services.taxService = service.NewTaxService(s.repositories.taxRuleRepository, s.cfg.Tax.DefaultRegion, s.cfg.Tax.PricesIncludeTax)
+	s.services.priceService = service.NewPriceService(s.repositories.priceRepository, s.repositories.productRepository)
+	s.services.productService = service.NewProductService(s.repositories.productRepository, s.services.categoryService, s.services.taxService, s.services.priceService)
+	s.services.variantService = service.NewVariantService(s.repositories.variantRepository, s.repositories.productRepository)
+	s.services.promotionService = service.NewPromotionService(s.repositories.promotionRepository, s.repositories.productRepository, s.services.categoryService)
+	s.services.shoppingCartService = service.NewShoppingCartService(s.repositories.shoppingCartRepository, s.repositories.promotionRepository, s.services.categoryService, s.services.taxService, s.services.priceService)
+	s.services.orderService = service.NewOrderService(s.repositories.orderRepository, s.services.shoppingCartService)
 }
 
 func (s *Server) setControllers() {
 	s.controllers.productCtrl = controller.NewProductController(s.services.productService)
 	s.controllers.shoppingCartCtrl = controller.NewShoppingCartController(s.services.shoppingCartService)
+	s.controllers.orderCtrl = controller.NewOrderController(s.services.orderService)
+	s.controllers.categoryCtrl = controller.NewCategoryController(s.services.categoryService)
+	s.controllers.variantCtrl = controller.NewVariantController(s.services.variantService)
+	s.controllers.promotionCtrl = controller.NewPromotionController(s.services.promotionService)
+	s.controllers.taxRuleCtrl = controller.NewTaxRuleController(s.services.taxService)
+	s.controllers.priceCtrl = controller.NewPriceController(s.services.priceService)
 }
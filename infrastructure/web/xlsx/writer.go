@@ -0,0 +1,136 @@
+// Package xlsx writes single-sheet XLSX workbooks row by row, so large sheets
+// are streamed instead of built in memory.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	spreadsheetNS   = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	relationshipsNS = "http://schemas.openxmlformats.org/package/2006/relationships"
+	officeNS        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+)
+
+// staticParts are the parts of the workbook that do not depend on its rows.
+var staticParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="` + relationshipsNS + `">` +
+		`<Relationship Id="rId1" Type="` + officeNS + `/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="` + relationshipsNS + `">` +
+		`<Relationship Id="rId1" Type="` + officeNS + `/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// Writer writes the rows of the only sheet of a workbook.
+type Writer struct {
+	zip   *zip.Writer
+	sheet io.Writer
+}
+
+// NewWriter starts a workbook with a sheet named sheetName.
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	archive := zip.NewWriter(w)
+
+	for _, part := range staticParts {
+		if err := writePart(archive, part.name, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<workbook xmlns="` + spreadsheetNS + `" xmlns:r="` + officeNS + `">` +
+		`<sheets><sheet name="` + escape(sheetName) + `" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`
+	if err := writePart(archive, "xl/workbook.xml", workbook); err != nil {
+		return nil, err
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+
+		`<worksheet xmlns="`+spreadsheetNS+`"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{zip: archive, sheet: sheet}, nil
+}
+
+// WriteRow writes a row. Integers and floats are written as numbers, times
+// as RFC 3339 text, nil as an empty cell and everything else as text.
+func (w *Writer) WriteRow(values []interface{}) error {
+	var row strings.Builder
+
+	row.WriteString("<row>")
+	for _, v := range values {
+		switch value := v.(type) {
+		case nil:
+			row.WriteString("<c/>")
+		case int:
+			row.WriteString("<c><v>" + strconv.Itoa(value) + "</v></c>")
+		case int64:
+			row.WriteString("<c><v>" + strconv.FormatInt(value, 10) + "</v></c>")
+		case uint:
+			row.WriteString("<c><v>" + strconv.FormatUint(uint64(value), 10) + "</v></c>")
+		case float64:
+			row.WriteString("<c><v>" + strconv.FormatFloat(value, 'f', -1, 64) + "</v></c>")
+		case time.Time:
+			row.WriteString(`<c t="inlineStr"><is><t>` + value.Format(time.RFC3339) + "</t></is></c>")
+		default:
+			row.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">` + escape(fmt.Sprint(value)) + "</t></is></c>")
+		}
+	}
+	row.WriteString("</row>")
+
+	_, err := io.WriteString(w.sheet, row.String())
+	return err
+}
+
+// Close ends the sheet and the workbook. It does not close the underlying
+// writer.
+func (w *Writer) Close() error {
+	if _, err := io.WriteString(w.sheet, "</sheetData></worksheet>"); err != nil {
+		return err
+	}
+
+	return w.zip.Close()
+}
+
+func writePart(archive *zip.Writer, name, content string) error {
+	part, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(part, content)
+	return err
+}
+
+// escape escapes text for XML, replacing the characters XML cannot hold.
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}